@@ -9,17 +9,24 @@
 //
 //	internal/domain/order/entity.go          - Domain entity
 //	internal/domain/order/repository.go      - Repository interface
+//	internal/domain/order/permission.go      - RBAC permission declarations
 //	internal/infrastructure/persistence/database/order_model.go  - Data model
 //	internal/infrastructure/persistence/database/order_repo.go   - Repository impl
 //	internal/application/dto/order_dto.go    - DTO
 //	internal/application/service/order_service.go - Application service
 //	internal/interfaces/http/handler/order_handler.go - HTTP handler
-//	Also auto-registers in router.go and container.go
+//	Also auto-registers routes (guarded by order:read/write/delete) in router.go
+//	and service, migration and permissions in container.go
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"strings"
@@ -78,6 +85,7 @@ func main() {
 	}{
 		{"templates/domain_entity.go.tmpl", fmt.Sprintf("internal/domain/%s/entity.go", data.SnakeName)},
 		{"templates/domain_repository.go.tmpl", fmt.Sprintf("internal/domain/%s/repository.go", data.SnakeName)},
+		{"templates/domain_permission.go.tmpl", fmt.Sprintf("internal/domain/%s/permission.go", data.SnakeName)},
 		{"templates/infra_model.go.tmpl", fmt.Sprintf("internal/infrastructure/persistence/database/%s_model.go", data.SnakeName)},
 		{"templates/infra_repo.go.tmpl", fmt.Sprintf("internal/infrastructure/persistence/database/%s_repo.go", data.SnakeName)},
 		{"templates/app_dto.go.tmpl", fmt.Sprintf("internal/application/dto/%s_dto.go", data.SnakeName)},
@@ -114,6 +122,13 @@ func main() {
 		fmt.Println("  + model migration registered in container.go")
 	}
 
+	// Auto-register permissions
+	if err := appendPermissions(data); err != nil {
+		fmt.Fprintf(os.Stderr, "  ! failed to register permissions: %v (please add manually)\n", err)
+	} else {
+		fmt.Println("  + permissions registered in container.go")
+	}
+
 	fmt.Printf("\nModule %s generated!\n", data.PascalName)
 	fmt.Println("\nNext steps:")
 	fmt.Printf("  1. Edit internal/domain/%s/entity.go - add domain fields and business methods\n", data.SnakeName)
@@ -161,22 +176,22 @@ func appendRoute(data ModuleData) error {
 			%sHandler := handler.New%sHandler(c.%sService)
 			%s := authorized.Group("/%s")
 			{
-				%s.GET("", %sHandler.List)
-				%s.POST("", %sHandler.Create)
-				%s.GET("/:id", %sHandler.Get)
-				%s.PUT("/:id", %sHandler.Update)
-				%s.DELETE("/:id", %sHandler.Delete)
+				%s.GET("", authz.RequirePermission("%s:read"), %sHandler.List)
+				%s.POST("", authz.RequirePermission("%s:write"), %sHandler.Create)
+				%s.GET("/:id", authz.RequirePermission("%s:read"), %sHandler.Get)
+				%s.PUT("/:id", authz.RequirePermission("%s:write"), %sHandler.Update)
+				%s.DELETE("/:id", authz.RequirePermission("%s:delete"), %sHandler.Delete)
 			}
 
 			`,
 		data.PascalName,
 		data.CamelName, data.PascalName, data.PascalName,
 		data.PluralName, data.PluralName,
-		data.PluralName, data.CamelName,
-		data.PluralName, data.CamelName,
-		data.PluralName, data.CamelName,
-		data.PluralName, data.CamelName,
-		data.PluralName, data.CamelName,
+		data.PluralName, data.SnakeName, data.CamelName,
+		data.PluralName, data.SnakeName, data.CamelName,
+		data.PluralName, data.SnakeName, data.CamelName,
+		data.PluralName, data.SnakeName, data.CamelName,
+		data.PluralName, data.SnakeName, data.CamelName,
 	)
 
 	newContent := strings.Replace(string(content), marker, routeCode+marker, 1)
@@ -238,6 +253,43 @@ func appendMigration(data ModuleData) error {
 	return os.WriteFile(containerFile, []byte(newContent), 0o644)
 }
 
+func appendPermissions(data ModuleData) error {
+	containerFile := "internal/container/container.go"
+	content, err := os.ReadFile(containerFile)
+	if err != nil {
+		return err
+	}
+
+	marker := "// GEN:PERMISSION_REGISTER - Code generator appends permissions here, do not remove"
+	permCode := fmt.Sprintf("%s.Permissions,\n\t\t", data.SnakeName)
+	newContent := strings.Replace(string(content), marker, permCode+marker, 1)
+	if newContent == string(content) {
+		return fmt.Errorf("permission marker comment not found")
+	}
+
+	// Import the domain package next to the other internal imports, then let
+	// go/ast put it in order
+	anchor := fmt.Sprintf("\t\"%s/internal/application/service\"\n", data.ModulePath)
+	domainImport := fmt.Sprintf("\t\"%s/internal/domain/%s\"\n", data.ModulePath, data.SnakeName)
+	if !strings.Contains(newContent, anchor) {
+		return fmt.Errorf("service import not found")
+	}
+	newContent = strings.Replace(newContent, anchor, anchor+domainImport, 1)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, containerFile, newContent, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parse container.go: %w", err)
+	}
+	ast.SortImports(fset, file)
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return fmt.Errorf("format container.go: %w", err)
+	}
+	return os.WriteFile(containerFile, buf.Bytes(), 0o644)
+}
+
 func detectModulePath() string {
 	content, err := os.ReadFile("go.mod")
 	if err != nil {
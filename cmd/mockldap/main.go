@@ -0,0 +1,88 @@
+// Mock LDAP server for developing and testing directory sign-in
+//
+// Usage:
+//
+//	go run ./cmd/mockldap -addr :3389
+//	make mockldap
+//
+// Then point the service at it:
+//
+//	security:
+//	  authenticators: ["local", "ldap"]
+//	ldap:
+//	  url: ldap://localhost:3389
+//	  start_tls: true
+//	  insecure_skip_verify: true
+//	  bind_dn: cn=admin,dc=example,dc=org
+//	  bind_password: admin
+//	  base_dn: ou=people,dc=example,dc=org
+//	  role_mappings:
+//	    - group: admins
+//	      role: admin
+//
+// The directory is kept in memory and built from -users; StartTLS uses a
+// certificate generated at startup. Every bind and search is logged. Only
+// what sign-in needs is implemented: never expose this server outside a
+// development machine.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"go-ddd-scaffold/pkg/directory/directorytest"
+)
+
+var (
+	addr         = flag.String("addr", ":3389", "listen address")
+	baseDN       = flag.String("base-dn", "dc=example,dc=org", "directory suffix; users live under ou=people, groups under ou=groups")
+	bindDN       = flag.String("bind-dn", "", "search user DN (default: cn=admin,<base-dn>)")
+	bindPassword = flag.String("bind-password", "admin", "search user password")
+	ldaps        = flag.Bool("ldaps", false, "serve ldaps:// (implicit TLS) instead of ldap:// with StartTLS")
+	anonymous    = flag.Bool("anonymous", false, "allow searches without binding")
+	users        = flag.String("users", "alice:password:developers,bob:password:admins;developers",
+		"comma-separated username:password[:group;group...] entries; mail is <username>@example.com")
+)
+
+func main() {
+	flag.Parse()
+
+	opts := directorytest.Options{
+		Addr:            *addr,
+		BaseDN:          *baseDN,
+		BindDN:          *bindDN,
+		BindPassword:    *bindPassword,
+		LDAPS:           *ldaps,
+		AnonymousSearch: *anonymous,
+		Logger:          log.Default(),
+	}
+	for _, spec := range strings.Split(*users, ",") {
+		parts := strings.SplitN(strings.TrimSpace(spec), ":", 3)
+		if len(parts) < 2 || parts[0] == "" {
+			log.Fatalf("invalid -users entry %q, want username:password[:groups]", spec)
+		}
+		u := directorytest.User{Username: parts[0], Password: parts[1], Email: parts[0] + "@example.com"}
+		if len(parts) == 3 && parts[2] != "" {
+			u.Groups = strings.Split(parts[2], ";")
+		}
+		opts.Users = append(opts.Users, u)
+	}
+
+	srv, err := directorytest.Start(opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("mock LDAP server %s base_dn=%s bind_dn=%s listening on %s", srv.URL, srv.BaseDN, srv.BindDN, *addr)
+	for _, u := range opts.Users {
+		log.Printf("  user %s password=%s groups=%s", u.Username, u.Password, strings.Join(u.Groups, ","))
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	srv.Close()
+}
@@ -0,0 +1,75 @@
+// Mock OpenID Connect provider for developing and testing single sign-on
+//
+// Usage:
+//
+//	go run ./cmd/mockoidc -addr :9000 -client-id my-service
+//	make mockoidc
+//
+// Then point the service at it:
+//
+//	sso:
+//	  enabled: true
+//	  issuer: http://localhost:9000
+//	  client_id: my-service
+//	  redirect_url: http://localhost:8080/api/v1/auth/oidc/callback
+//
+// The authorize endpoint shows a form where any username, email and groups
+// can be entered; with -auto it signs in the user given by the flags without
+// asking. Codes require PKCE (S256). Nothing is persisted and no password is
+// checked: never expose this server outside a development machine.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"go-ddd-scaffold/pkg/oidctest"
+)
+
+var (
+	addr     = flag.String("addr", ":9000", "listen address")
+	issuer   = flag.String("issuer", "http://localhost:9000", "issuer URL, must match how the service reaches this server")
+	clientID = flag.String("client-id", "my-service", "accepted client_id (also the id_token audience)")
+	secret   = flag.String("client-secret", "", "required client_secret; empty accepts public clients")
+	auto     = flag.Bool("auto", false, "skip the sign-in form and use the flag values below")
+	subject  = flag.String("sub", "", "subject for -auto (default: derived from username)")
+	username = flag.String("username", "alice", "preferred_username for -auto")
+	email    = flag.String("email", "alice@example.com", "verified email for -auto")
+	groups   = flag.String("groups", "", "comma-separated groups for -auto")
+)
+
+func main() {
+	flag.Parse()
+
+	opts := oidctest.Options{
+		Addr:         *addr,
+		Issuer:       *issuer,
+		ClientID:     *clientID,
+		ClientSecret: *secret,
+		Logger:       log.Default(),
+	}
+	if *auto {
+		u := &oidctest.User{Subject: *subject, Username: *username, Email: *email}
+		for _, g := range strings.Split(*groups, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				u.Groups = append(u.Groups, g)
+			}
+		}
+		opts.AutoLogin = u
+	}
+
+	srv, err := oidctest.Start(opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("mock OIDC provider issuer=%s client_id=%s listening on %s", srv.Issuer, srv.ClientID, *addr)
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	srv.Close()
+}
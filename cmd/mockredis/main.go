@@ -0,0 +1,60 @@
+// Mock Redis server for developing with the shared cache
+//
+// Usage:
+//
+//	go run ./cmd/mockredis -addr :6379
+//	make mockredis
+//
+// Then point every replica of the service at it:
+//
+//	cache:
+//	  driver: redis
+//	  redis:
+//	    addrs: ["localhost:6379"]
+//
+// Data is kept in memory and lost on exit. Only standalone mode is offered,
+// without TLS: never expose this server outside a development machine.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go-ddd-scaffold/pkg/cache/redistest"
+)
+
+var (
+	addr     = flag.String("addr", ":6379", "listen address")
+	password = flag.String("password", "", "password clients must send with AUTH; empty disables it")
+)
+
+func main() {
+	flag.Parse()
+
+	srv, err := redistest.StartAddr(*addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *password != "" {
+		srv.RequireAuth(*password)
+	}
+	log.Printf("mock Redis server listening on %s", srv.Addr())
+
+	// miniredis only expires keys when its clock is advanced; follow real time
+	go func() {
+		last := time.Now()
+		for now := range time.Tick(time.Second) {
+			srv.FastForward(now.Sub(last))
+			last = now
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	srv.Close()
+}
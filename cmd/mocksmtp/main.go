@@ -0,0 +1,66 @@
+// Mock SMTP server for developing and testing outgoing mail
+//
+// Usage:
+//
+//	go run ./cmd/mocksmtp -addr :2525 -http :8025
+//	make mocksmtp
+//
+// Then point the service at it:
+//
+//	mail:
+//	  driver: smtp
+//	  smtp:
+//	    host: localhost
+//	    port: 2525
+//	    encryption: none
+//
+// Every message is accepted, printed and kept in memory; GET / on the -http
+// address lists them as JSON, newest first, with the decoded text body.
+// With -dir each message is also saved as an .eml file. No authentication or
+// TLS is offered: never expose this server outside a development machine.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go-ddd-scaffold/pkg/mailer/mailertest"
+)
+
+var (
+	addr     = flag.String("addr", ":2525", "SMTP listen address")
+	httpAddr = flag.String("http", ":8025", "listen address of the JSON message list; empty disables it")
+	dir      = flag.String("dir", "", "directory to save messages as .eml files")
+	limit    = flag.Int("keep", 100, "number of messages kept in memory")
+)
+
+func main() {
+	flag.Parse()
+
+	srv, err := mailertest.Start(mailertest.Options{
+		Addr:   *addr,
+		Dir:    *dir,
+		Keep:   *limit,
+		Logger: log.Default(),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("mock SMTP server listening on %s", *addr)
+
+	if *httpAddr != "" {
+		go func() {
+			log.Printf("message list on http://%s/", *httpAddr)
+			log.Fatal(http.ListenAndServe(*httpAddr, srv))
+		}()
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	srv.Close()
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/apikey"
+)
+
+// APIKeyResponse describes a key without its secret
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // leading characters of the key, for recognition
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// FromAPIKey converts from domain entity
+func FromAPIKey(k *apikey.APIKey) *APIKeyResponse {
+	scopes := k.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return &APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// FromAPIKeyList converts from domain entity list
+func FromAPIKeyList(items []*apikey.APIKey) []*APIKeyResponse {
+	result := make([]*APIKeyResponse, len(items))
+	for i, item := range items {
+		result[i] = FromAPIKey(item)
+	}
+	return result
+}
+
+// CreateAPIKeyRequest is the create request DTO
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,dive,required,max=100"` // permission codes, each granted by the owner's role
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=3650"`    // 0 means the key never expires
+}
+
+// APIKeyCreatedResponse includes the key secret, which is shown only once
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
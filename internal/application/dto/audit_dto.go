@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/audit"
+)
+
+// QueryAuditLogRequest filters the audit log; from/to are RFC 3339 times
+type QueryAuditLogRequest struct {
+	Page           int       `form:"page" json:"page"`
+	PageSize       int       `form:"page_size" json:"page_size"`
+	ActorID        uint      `form:"actor_id" json:"actor_id"`
+	ImpersonatorID uint      `form:"impersonator_id" json:"impersonator_id"`
+	Action         string    `form:"action" json:"action"`
+	Method         string    `form:"method" json:"method"`
+	TargetID       string    `form:"target_id" json:"target_id"`
+	From           time.Time `form:"from" json:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To             time.Time `form:"to" json:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// AuditChange is the old and new value of one field
+type AuditChange struct {
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+// AuditLogResponse is one audit log entry
+type AuditLogResponse struct {
+	ID               uint                   `json:"id"`
+	TenantID         uint                   `json:"tenant_id"`
+	ActorID          uint                   `json:"actor_id"`
+	ActorName        string                 `json:"actor_name"`
+	ImpersonatorID   uint                   `json:"impersonator_id,omitempty"` // administrator acting as the actor
+	ImpersonatorName string                 `json:"impersonator_name,omitempty"`
+	Action           string                 `json:"action"`
+	Method           string                 `json:"method"`
+	Route            string                 `json:"route"`
+	TargetID         string                 `json:"target_id,omitempty"`
+	Status           int                    `json:"status"`
+	IP               string                 `json:"ip"`
+	RequestID        string                 `json:"request_id"`
+	Changes          map[string]AuditChange `json:"changes,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+}
+
+// FromAuditEntry converts from domain entity
+func FromAuditEntry(e *audit.Entry) *AuditLogResponse {
+	resp := &AuditLogResponse{
+		ID:               e.ID,
+		TenantID:         e.TenantID,
+		ActorID:          e.ActorID,
+		ActorName:        e.ActorName,
+		ImpersonatorID:   e.ImpersonatorID,
+		ImpersonatorName: e.ImpersonatorName,
+		Action:           e.Action,
+		Method:           e.Method,
+		Route:            e.Route,
+		TargetID:         e.TargetID,
+		Status:           e.Status,
+		IP:               e.IP,
+		RequestID:        e.RequestID,
+		CreatedAt:        e.CreatedAt,
+	}
+	if len(e.Changes) > 0 {
+		resp.Changes = make(map[string]AuditChange, len(e.Changes))
+		for k, c := range e.Changes {
+			resp.Changes[k] = AuditChange{Old: c.Old, New: c.New}
+		}
+	}
+	return resp
+}
+
+// FromAuditEntryList converts from domain entity list
+func FromAuditEntryList(items []*audit.Entry) []*AuditLogResponse {
+	result := make([]*AuditLogResponse, len(items))
+	for i, item := range items {
+		result[i] = FromAuditEntry(item)
+	}
+	return result
+}
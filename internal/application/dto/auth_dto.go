@@ -0,0 +1,69 @@
+package dto
+
+// LoginRequest is the login request DTO
+type LoginRequest struct {
+	Tenant   string `json:"tenant"` // tenant code; the default tenant when empty
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	// Captcha answer, required after repeated failures (error 10010)
+	CaptchaID     string `json:"captcha_id"`
+	CaptchaAnswer string `json:"captcha_answer"`
+}
+
+// CaptchaResponse is a single-use image challenge
+type CaptchaResponse struct {
+	CaptchaID string `json:"captcha_id"`
+	Image     string `json:"image"`      // PNG data URI
+	ExpiresIn int    `json:"expires_in"` // seconds the answer is accepted
+}
+
+// RefreshTokenRequest is the refresh request DTO
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest is the logout request DTO
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"` // also revoke this refresh token's family
+	All          bool   `json:"all"`           // revoke every token of the current user
+}
+
+// TokenResponse is the token pair response DTO
+type TokenResponse struct {
+	Token            string `json:"token"`
+	ExpiresAt        int64  `json:"expires_at"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	RefreshExpiresAt int64  `json:"refresh_expires_at,omitempty"`
+	Restriction      string `json:"restriction,omitempty"` // "password_change", "mfa_pending" or "mfa_enroll": only the matching endpoints accept the token
+}
+
+// ImpersonationResponse is a token acting as another user. It cannot be
+// refreshed; requests made with it carry an X-Impersonated-By response header.
+type ImpersonationResponse struct {
+	Token     string        `json:"token"`
+	ExpiresAt int64         `json:"expires_at"`
+	User      *UserResponse `json:"user"` // the impersonated user
+}
+
+// ChangePasswordRequest is the self-service password change DTO
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,max=72"`
+}
+
+// ForgotPasswordRequest asks for a password reset link by mail
+type ForgotPasswordRequest struct {
+	Tenant string `json:"tenant"` // tenant code; the default tenant when empty
+	Email  string `json:"email" binding:"required,email,max=255"`
+}
+
+// ResetPasswordByTokenRequest sets a new password with a mailed reset token
+type ResetPasswordByTokenRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,max=72"`
+}
+
+// VerifyEmailRequest confirms an email address with a mailed token
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+// MFACodeRequest carries a second-factor code
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"` // TOTP code, or a recovery code where accepted
+}
+
+// MFAStatusResponse is the current user's two-factor state
+type MFAStatusResponse struct {
+	TOTPEnabled            bool  `json:"totp_enabled"`
+	Required               bool  `json:"required"` // the user's role requires 2FA
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// TOTPSetupResponse is the enrollment material for an authenticator app
+type TOTPSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`     // otpauth:// provisioning URI
+	QRCode string `json:"qr_code"` // PNG data URI of the provisioning URI
+}
+
+// RecoveryCodesResponse lists freshly generated recovery codes; they are shown only once
+type RecoveryCodesResponse struct {
+	Codes []string `json:"codes"`
+}
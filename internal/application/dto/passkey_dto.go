@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/passkey"
+
+	"github.com/google/uuid"
+)
+
+// PasskeyResponse describes a registered passkey without its key material
+type PasskeyResponse struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	AAGUID         string     `json:"aaguid"` // authenticator model, all zeros when not disclosed
+	Transports     []string   `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"` // the passkey may be synced to other devices
+	BackupState    bool       `json:"backup_state"`    // the passkey is currently synced
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// FromPasskey converts from domain entity
+func FromPasskey(p *passkey.Passkey) *PasskeyResponse {
+	transports := p.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+	aaguid, err := uuid.FromBytes(p.AAGUID)
+	if err != nil {
+		aaguid = uuid.Nil
+	}
+	return &PasskeyResponse{
+		ID:             p.ID,
+		Name:           p.Name,
+		AAGUID:         aaguid.String(),
+		Transports:     transports,
+		BackupEligible: p.BackupEligible,
+		BackupState:    p.BackupState,
+		LastUsedAt:     p.LastUsedAt,
+		CreatedAt:      p.CreatedAt,
+	}
+}
+
+// FromPasskeyList converts from domain entity list
+func FromPasskeyList(items []*passkey.Passkey) []*PasskeyResponse {
+	result := make([]*PasskeyResponse, len(items))
+	for i, item := range items {
+		result[i] = FromPasskey(item)
+	}
+	return result
+}
+
+// PasskeyChallengeResponse starts a WebAuthn ceremony. Options is passed as is
+// to navigator.credentials.create() or get() after decoding its base64url
+// fields; ChallengeID is sent back with the result.
+type PasskeyChallengeResponse struct {
+	ChallengeID string `json:"challenge_id"`
+	Options     any    `json:"options" swaggertype:"object"` // {"publicKey": {...}}
+}
+
+// FinishPasskeyRegistrationRequest completes a registration
+type FinishPasskeyRegistrationRequest struct {
+	ChallengeID string          `json:"challenge_id" binding:"required,max=64"`
+	Name        string          `json:"name" binding:"omitempty,max=100"`                   // defaults to "Passkey"
+	Credential  json.RawMessage `json:"credential" binding:"required" swaggertype:"object"` // PublicKeyCredential from navigator.credentials.create()
+}
+
+// FinishPasskeyLoginRequest completes a login
+type FinishPasskeyLoginRequest struct {
+	ChallengeID string          `json:"challenge_id" binding:"required,max=64"`
+	Credential  json.RawMessage `json:"credential" binding:"required" swaggertype:"object"` // PublicKeyCredential from navigator.credentials.get()
+}
+
+// RenamePasskeyRequest is the rename request DTO
+type RenamePasskeyRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/rbac"
+)
+
+// RoleResponse is the role response DTO
+type RoleResponse struct {
+	ID          uint      `json:"id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	RequireMFA  bool      `json:"require_mfa"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// FromRole converts from domain entity
+func FromRole(r *rbac.Role) *RoleResponse {
+	perms := r.Permissions
+	if perms == nil {
+		perms = []string{}
+	}
+	return &RoleResponse{
+		ID:          r.ID,
+		Code:        r.Code,
+		Name:        r.Name,
+		Description: r.Description,
+		RequireMFA:  r.RequireMFA,
+		Permissions: perms,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+// FromRoleList converts from domain entity list
+func FromRoleList(items []*rbac.Role) []*RoleResponse {
+	result := make([]*RoleResponse, len(items))
+	for i, item := range items {
+		result[i] = FromRole(item)
+	}
+	return result
+}
+
+// PermissionResponse is the permission response DTO
+type PermissionResponse struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// FromPermissionList converts from domain entity list
+func FromPermissionList(items []*rbac.Permission) []*PermissionResponse {
+	result := make([]*PermissionResponse, len(items))
+	for i, item := range items {
+		result[i] = &PermissionResponse{Code: item.Code, Description: item.Description}
+	}
+	return result
+}
+
+// CreateRoleRequest is the create role request DTO
+type CreateRoleRequest struct {
+	Code        string   `json:"code" binding:"required,max=50,alphanum"`
+	Name        string   `json:"name" binding:"required,max=100"`
+	Description string   `json:"description" binding:"max=255"`
+	RequireMFA  bool     `json:"require_mfa"`
+	Permissions []string `json:"permissions"`
+}
+
+// UpdateRoleRequest is the update role request DTO
+type UpdateRoleRequest struct {
+	Name        *string   `json:"name" binding:"omitempty,max=100"`
+	Description *string   `json:"description" binding:"omitempty,max=255"`
+	RequireMFA  *bool     `json:"require_mfa"`
+	Permissions *[]string `json:"permissions"`
+}
+
+// AssignRoleRequest is the role assignment request DTO
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required,max=50"`
+}
@@ -0,0 +1,158 @@
+package dto
+
+import (
+	"strconv"
+	"strings"
+
+	"go-ddd-scaffold/internal/domain/rbac"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/scim"
+)
+
+// Locations of SCIM resources, relative to the server root
+const (
+	SCIMUsersPath  = "/scim/v2/Users/"
+	SCIMGroupsPath = "/scim/v2/Groups/"
+)
+
+// SCIMUser is a user in the SCIM core User schema, as returned and as
+// accepted by POST and PUT
+type SCIMUser struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *SCIMName    `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []SCIMEmail  `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"`   // absent in a request means true
+	Password    string       `json:"password,omitempty"` // write-only
+	Groups      []SCIMMember `json:"groups,omitempty"`   // read-only: the user's role
+	Meta        *scim.Meta   `json:"meta,omitempty"`
+}
+
+// SCIMName is the user's name; only the formatted name is stored
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// SCIMEmail is one of the user's addresses; only the primary one is stored
+type SCIMEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// SCIMGroup is a role in the SCIM core Group schema. The display name is the
+// role code.
+type SCIMGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []SCIMMember `json:"members,omitempty"`
+	Meta        *scim.Meta   `json:"meta,omitempty"`
+}
+
+// SCIMMember references a user from a group or a group from a user
+type SCIMMember struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+// SCIMListQuery holds the query parameters of SCIM list requests
+type SCIMListQuery struct {
+	Filter             string `form:"filter"`
+	StartIndex         int    `form:"startIndex"`
+	Count              *int   `form:"count"`              // absent means the server's maximum
+	ExcludedAttributes string `form:"excludedAttributes"` // only "members" of groups is honored
+}
+
+// DisplayNameValue returns the name to store as nickname: displayName,
+// else the formatted name, else the given and family names
+func (u *SCIMUser) DisplayNameValue() string {
+	if u.DisplayName != "" || u.Name == nil {
+		return u.DisplayName
+	}
+	return u.Name.Value()
+}
+
+// Value returns the formatted name, or the given and family names joined
+func (n *SCIMName) Value() string {
+	if n.Formatted != "" {
+		return n.Formatted
+	}
+	return strings.TrimSpace(n.GivenName + " " + n.FamilyName)
+}
+
+// PrimaryEmail returns the primary address, else the first one
+func PrimaryEmail(emails []SCIMEmail) string {
+	for _, e := range emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(emails) > 0 {
+		return emails[0].Value
+	}
+	return ""
+}
+
+// FromUserSCIM converts from domain entity; role may be nil if it no longer exists
+func FromUserSCIM(u *user.User, externalID string, role *rbac.Role) *SCIMUser {
+	id := strconv.FormatUint(uint64(u.ID), 10)
+	active := u.IsActive()
+	resp := &SCIMUser{
+		Schemas:     []string{scim.SchemaUser},
+		ID:          id,
+		ExternalID:  externalID,
+		UserName:    u.Username,
+		DisplayName: u.Nickname,
+		Active:      &active,
+		Meta: &scim.Meta{
+			ResourceType: "User",
+			Created:      &u.CreatedAt,
+			LastModified: &u.UpdatedAt,
+			Location:     SCIMUsersPath + id,
+		},
+	}
+	if u.Nickname != "" {
+		resp.Name = &SCIMName{Formatted: u.Nickname}
+	}
+	if u.Email != "" {
+		resp.Emails = []SCIMEmail{{Value: u.Email, Type: "work", Primary: true}}
+	}
+	if role != nil {
+		resp.Groups = []SCIMMember{FromRoleMemberSCIM(role)}
+	}
+	return resp
+}
+
+// FromRoleSCIM converts from domain entity; members are omitted when nil
+func FromRoleSCIM(r *rbac.Role, members []*user.User) *SCIMGroup {
+	id := strconv.FormatUint(uint64(r.ID), 10)
+	resp := &SCIMGroup{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          id,
+		DisplayName: r.Code,
+		Meta: &scim.Meta{
+			ResourceType: "Group",
+			Created:      &r.CreatedAt,
+			LastModified: &r.UpdatedAt,
+			Location:     SCIMGroupsPath + id,
+		},
+	}
+	for _, m := range members {
+		memberID := strconv.FormatUint(uint64(m.ID), 10)
+		resp.Members = append(resp.Members, SCIMMember{Value: memberID, Ref: SCIMUsersPath + memberID, Display: m.Username})
+	}
+	return resp
+}
+
+// FromRoleMemberSCIM converts a role to the group reference of its members
+func FromRoleMemberSCIM(r *rbac.Role) SCIMMember {
+	id := strconv.FormatUint(uint64(r.ID), 10)
+	return SCIMMember{Value: id, Ref: SCIMGroupsPath + id, Display: r.Code}
+}
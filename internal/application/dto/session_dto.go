@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/session"
+)
+
+// SessionResponse describes a signed-in device
+type SessionResponse struct {
+	ID             uint      `json:"id"`
+	UserAgent      string    `json:"user_agent"`
+	IP             string    `json:"ip"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastSeenAt     time.Time `json:"last_seen_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	Current        bool      `json:"current"`                   // the session making this request
+	ImpersonatorID uint      `json:"impersonator_id,omitempty"` // administrator signed in as the user
+}
+
+// FromSession converts from domain entity; currentID is the caller's session, if any
+func FromSession(s *session.Session, currentID string) *SessionResponse {
+	return &SessionResponse{
+		ID:             s.ID,
+		UserAgent:      s.UserAgent,
+		IP:             s.IP,
+		CreatedAt:      s.CreatedAt,
+		LastSeenAt:     s.LastSeenAt,
+		ExpiresAt:      s.ExpiresAt,
+		Current:        currentID != "" && s.FamilyID == currentID,
+		ImpersonatorID: s.ImpersonatorID,
+	}
+}
+
+// FromSessionList converts from domain entity list
+func FromSessionList(items []*session.Session, currentID string) []*SessionResponse {
+	result := make([]*SessionResponse, len(items))
+	for i, item := range items {
+		result[i] = FromSession(item, currentID)
+	}
+	return result
+}
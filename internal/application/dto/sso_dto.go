@@ -0,0 +1,11 @@
+package dto
+
+// SSOConfigResponse tells the login page whether to offer single sign-on
+type SSOConfigResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+// SSOExchangeRequest redeems the one-time code returned to the frontend after the callback
+type SSOExchangeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
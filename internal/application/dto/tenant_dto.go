@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/tenant"
+)
+
+// TenantResponse is the tenant response DTO
+type TenantResponse struct {
+	ID        uint      `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FromTenant converts from domain entity
+func FromTenant(t *tenant.Tenant) *TenantResponse {
+	return &TenantResponse{
+		ID:        t.ID,
+		Code:      t.Code,
+		Name:      t.Name,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// FromTenantList converts from domain entity list
+func FromTenantList(items []*tenant.Tenant) []*TenantResponse {
+	result := make([]*TenantResponse, len(items))
+	for i, item := range items {
+		result[i] = FromTenant(item)
+	}
+	return result
+}
+
+// CreateTenantRequest creates a tenant together with its first administrator
+type CreateTenantRequest struct {
+	Code          string `json:"code" binding:"required,min=2,max=50,lowercase,alphanum"`
+	Name          string `json:"name" binding:"max=100"`
+	AdminUsername string `json:"admin_username" binding:"required,min=3,max=50"`
+	AdminPassword string `json:"admin_password" binding:"required,max=72"`
+}
+
+// UpdateTenantRequest is the update request DTO
+type UpdateTenantRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/user"
+)
+
+// UserResponse is the user response DTO
+type UserResponse struct {
+	ID                 uint      `json:"id"`
+	TenantID           uint      `json:"tenant_id"`
+	Username           string    `json:"username"`
+	Nickname           string    `json:"nickname"`
+	Email              string    `json:"email"`
+	EmailVerified      bool      `json:"email_verified"`
+	Role               string    `json:"role"`
+	Status             string    `json:"status"`
+	MustChangePassword bool      `json:"must_change_password"`
+	ServiceAccount     bool      `json:"service_account"`
+	SuperAdmin         bool      `json:"super_admin"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// FromUser converts from domain entity
+func FromUser(u *user.User) *UserResponse {
+	return &UserResponse{
+		ID:                 u.ID,
+		TenantID:           u.TenantID,
+		Username:           u.Username,
+		Nickname:           u.Nickname,
+		Email:              u.Email,
+		EmailVerified:      u.IsEmailVerified(),
+		Role:               string(u.Role),
+		Status:             string(u.Status),
+		MustChangePassword: u.MustChangePassword,
+		ServiceAccount:     u.ServiceAccount,
+		SuperAdmin:         u.SuperAdmin,
+		CreatedAt:          u.CreatedAt,
+		UpdatedAt:          u.UpdatedAt,
+	}
+}
+
+// FromUserList converts from domain entity list
+func FromUserList(items []*user.User) []*UserResponse {
+	result := make([]*UserResponse, len(items))
+	for i, item := range items {
+		result[i] = FromUser(item)
+	}
+	return result
+}
+
+// CreateUserRequest is the create request DTO
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+	Password string `json:"password" binding:"required,max=72"`
+	Nickname string `json:"nickname" binding:"max=100"`
+	Email    string `json:"email" binding:"omitempty,email,max=255"`
+	Role     string `json:"role" binding:"max=50"`
+}
+
+// CreateServiceAccountRequest is the create request DTO for machine identities
+type CreateServiceAccountRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+	Nickname string `json:"nickname" binding:"max=100"`
+	Role     string `json:"role" binding:"max=50"`
+}
+
+// UpdateUserRequest is the update request DTO
+type UpdateUserRequest struct {
+	Nickname *string `json:"nickname" binding:"omitempty,max=100"`
+	Email    *string `json:"email" binding:"omitempty,email,max=255"`
+}
+
+// ResetPasswordRequest is the admin password reset DTO
+type ResetPasswordRequest struct {
+	Password string `json:"password" binding:"required,max=72"` // temporary password, must be changed at next login
+}
+
+// QueryUserRequest is the query request DTO
+type QueryUserRequest struct {
+	Page     int    `form:"page" json:"page"`
+	PageSize int    `form:"page_size" json:"page_size"`
+	Keyword  string `form:"keyword" json:"keyword"`
+	Status   string `form:"status" json:"status"`
+}
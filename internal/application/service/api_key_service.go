@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/apikey"
+	"go-ddd-scaffold/internal/domain/audit"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/errcode"
+)
+
+const (
+	// APIKeyPrefix marks bearer credentials that are API keys rather than JWTs
+	APIKeyPrefix = "ak_"
+
+	apiKeyDisplayLength = 12              // characters of the key kept for listings
+	apiKeyTouchInterval = 1 * time.Minute // granularity of last-used tracking
+)
+
+// IsAPIKey reports whether a bearer credential looks like an API key
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
+
+// APIKeyAppService manages personal access tokens and service-account keys
+// and authenticates requests made with them
+type APIKeyAppService struct {
+	repo  apikey.Repository
+	users user.Repository
+	rbac  *RBACAppService
+}
+
+// NewAPIKeyAppService creates a new application service
+func NewAPIKeyAppService(repo apikey.Repository, users user.Repository, rbac *RBACAppService) *APIKeyAppService {
+	return &APIKeyAppService{repo: repo, users: users, rbac: rbac}
+}
+
+// Create issues a personal access token for the owner. Every scope must be
+// granted by the owner's role; the secret is returned only in this response.
+func (s *APIKeyAppService) Create(ctx context.Context, ownerID uint, req *dto.CreateAPIKeyRequest) (*dto.APIKeyCreatedResponse, error) {
+	owner, err := s.findUser(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.create(ctx, owner, req)
+}
+
+// CreateForServiceAccount issues a key for a service account on behalf of an administrator
+func (s *APIKeyAppService) CreateForServiceAccount(ctx context.Context, accountID uint, req *dto.CreateAPIKeyRequest) (*dto.APIKeyCreatedResponse, error) {
+	account, err := s.findUser(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.ServiceAccount {
+		return nil, errcode.ErrInvalidParams.WithMessage("not a service account")
+	}
+	return s.create(ctx, account, req)
+}
+
+// List returns the owner's keys; the owner must belong to the caller's tenant
+func (s *APIKeyAppService) List(ctx context.Context, ownerID uint) ([]*dto.APIKeyResponse, error) {
+	if _, err := s.findUser(ctx, ownerID); err != nil {
+		return nil, err
+	}
+	keys, err := s.repo.ListByUser(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromAPIKeyList(keys), nil
+}
+
+// Revoke revokes one of the owner's keys; the owner must belong to the caller's tenant
+func (s *APIKeyAppService) Revoke(ctx context.Context, ownerID, keyID uint) error {
+	if _, err := s.findUser(ctx, ownerID); err != nil {
+		return err
+	}
+	key, err := s.repo.FindByID(keyID)
+	if err != nil {
+		if errors.Is(err, apikey.ErrAPIKeyNotFound) {
+			return errcode.ErrAPIKeyNotFound
+		}
+		return err
+	}
+	if key.UserID != ownerID {
+		return errcode.ErrAPIKeyNotFound
+	}
+	auditEvent(ctx, audit.ActionAPIKeyRevoke)
+	auditTarget(ctx, key.ID)
+	if key.IsRevoked() {
+		return nil
+	}
+
+	key.Revoke(time.Now())
+	return s.repo.Save(key)
+}
+
+// Authenticate resolves an API key to claims for its owner. The owner is
+// re-read so disabled accounts and role changes take effect immediately.
+// Super-admin rights are never carried over: scopes only narrow tenant
+// permissions, so a key would otherwise reach every tenant unrestricted.
+func (s *APIKeyAppService) Authenticate(ctx context.Context, raw string) (*Claims, error) {
+	key, err := s.repo.FindByHash(hashOpaqueToken(raw))
+	if err != nil {
+		if errors.Is(err, apikey.ErrAPIKeyNotFound) {
+			return nil, errcode.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.IsRevoked() {
+		return nil, errcode.ErrTokenRevoked
+	}
+	if key.IsExpired(now) {
+		return nil, errcode.ErrTokenExpired
+	}
+
+	// Keys are looked up before the tenant is known; the owner decides it
+	u, err := s.users.FindByID(tenant.WithAllTenants(ctx), key.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, errcode.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if !u.IsActive() {
+		return nil, errcode.ErrAccountDisabled
+	}
+
+	if err := s.repo.Touch(key.ID, now, apiKeyTouchInterval); err != nil {
+		return nil, err
+	}
+	return &Claims{
+		UserID:   u.ID,
+		Username: u.Username,
+		Role:     string(u.Role),
+		TenantID: u.TenantID,
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+func (s *APIKeyAppService) create(ctx context.Context, owner *user.User, req *dto.CreateAPIKeyRequest) (*dto.APIKeyCreatedResponse, error) {
+	if !owner.IsActive() {
+		return nil, errcode.ErrAccountDisabled
+	}
+
+	scopes := normalizeScopes(req.Scopes)
+	if err := s.rbac.validatePermissions(scopes); err != nil {
+		return nil, err
+	}
+	for _, scope := range scopes {
+		ok, err := s.rbac.HasPermission(ctx, string(owner.Role), scope)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errcode.ErrScopeNotGranted.WithMessage("scope not granted by role: " + scope)
+		}
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	secret, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key := apikey.NewAPIKey(owner.ID, req.Name, secret[:apiKeyDisplayLength], hashOpaqueToken(secret), scopes, expiresAt)
+	if err := s.repo.Save(key); err != nil {
+		return nil, err
+	}
+	return &dto.APIKeyCreatedResponse{APIKeyResponse: *dto.FromAPIKey(key), Key: secret}, nil
+}
+
+func (s *APIKeyAppService) findUser(ctx context.Context, id uint) (*user.User, error) {
+	u, err := s.users.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, errcode.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// generateAPIKey returns a new prefixed key with 256 bits of entropy
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// normalizeScopes trims and de-duplicates scopes, keeping their order
+func normalizeScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" || seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		result = append(result, scope)
+	}
+	return result
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/audit"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/logger"
+)
+
+const (
+	auditQueueSize     = 4096
+	auditBatchSize     = 100
+	auditFlushInterval = time.Second
+)
+
+// AuditRequest describes a completed request for its audit entry
+type AuditRequest struct {
+	Method    string
+	Route     string // route pattern; empty for unmatched paths, which are not audited
+	TargetID  string // ":id" path parameter, if any
+	Status    int
+	IP        string
+	RequestID string
+	Body      []byte // JSON request body, recorded when no diff was recorded
+}
+
+// AuditAppService records audit entries and serves the audit log. Entries are
+// queued and written in batches by a background worker, so requests never wait
+// for the database; when the queue is full entries are dropped and logged.
+type AuditAppService struct {
+	repo audit.Repository
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan *audit.Entry
+	done   chan struct{}
+}
+
+// NewAuditAppService creates the service and starts its writer
+func NewAuditAppService(repo audit.Repository) *AuditAppService {
+	s := &AuditAppService{
+		repo:  repo,
+		queue: make(chan *audit.Entry, auditQueueSize),
+		done:  make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Begin returns ctx carrying an empty audit entry for the request
+func (s *AuditAppService) Begin(ctx context.Context) context.Context {
+	return audit.WithEntry(ctx, &audit.Entry{})
+}
+
+// Finish completes the request's audit entry and queues it. Mutating requests
+// are always recorded; reads only when they produced an authentication event
+// or were made while impersonating a user.
+func (s *AuditAppService) Finish(ctx context.Context, req AuditRequest) {
+	e := audit.FromContext(ctx)
+	if e == nil || req.Route == "" {
+		return
+	}
+	c, _ := ctx.Value(claimsContextKey{}).(*Claims)
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		if e.Action == "" && !c.IsImpersonated() {
+			return
+		}
+	}
+
+	if e.Action == "" {
+		e.Action = req.Method + " " + req.Route
+	}
+	e.Method, e.Route, e.Status = req.Method, req.Route, req.Status
+	e.IP, e.RequestID = req.IP, req.RequestID
+	if e.TargetID == "" {
+		e.TargetID = req.TargetID
+	}
+	if e.Changes == nil && len(req.Body) > 0 {
+		e.Changes = audit.FromBody(req.Body)
+	}
+
+	if c != nil && e.ActorID == 0 {
+		e.ActorID, e.ActorName, e.TenantID = c.UserID, c.Username, c.TenantID
+	}
+	if c.IsImpersonated() {
+		e.ImpersonatorID, e.ImpersonatorName = c.Actor.UserID, c.Actor.Username
+	}
+	// Record the tenant acted on, which differs from the caller's for
+	// super-admins working in another tenant
+	if id, ok := tenant.FromContext(ctx); ok {
+		e.TenantID = id
+	}
+	// Requests before sign-in that name no known tenant belong to the default one
+	if e.TenantID == 0 {
+		e.TenantID = tenant.DefaultID
+	}
+	e.CreatedAt = time.Now()
+	s.enqueue(e)
+}
+
+// List returns the audit log of the caller's tenant
+func (s *AuditAppService) List(ctx context.Context, req *dto.QueryAuditLogRequest) ([]*dto.AuditLogResponse, int64, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	filter := audit.Filter{
+		ActorID:        req.ActorID,
+		ImpersonatorID: req.ImpersonatorID,
+		Action:         req.Action,
+		Method:         req.Method,
+		TargetID:       req.TargetID,
+	}
+	if !req.From.IsZero() {
+		filter.From = &req.From
+	}
+	if !req.To.IsZero() {
+		filter.To = &req.To
+	}
+	entries, total, err := s.repo.List(ctx, filter, req.Page, req.PageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return dto.FromAuditEntryList(entries), total, nil
+}
+
+// Close stops accepting entries and writes the queued ones
+func (s *AuditAppService) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.queue)
+	s.mu.Unlock()
+	<-s.done
+}
+
+func (s *AuditAppService) enqueue(e *audit.Entry) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.queue <- e:
+	default:
+		logger.Warnf("audit queue full, dropped %s by user %d", e.Action, e.ActorID)
+	}
+}
+
+// run writes queued entries in batches of up to auditBatchSize, at least
+// every auditFlushInterval
+func (s *AuditAppService) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(auditFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*audit.Entry, 0, auditBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		// Entries carry their tenant; the writer works across tenants
+		if err := s.repo.SaveBatch(tenant.WithAllTenants(context.Background()), batch); err != nil {
+			logger.Errorf("failed to write %d audit entries: %v", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case e, ok := <-s.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) == auditBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// auditEvent names the current request's audit entry after an event it produced
+func auditEvent(ctx context.Context, action string) {
+	if e := audit.FromContext(ctx); e != nil {
+		e.Action = action
+	}
+}
+
+// auditActor records who a request acts as before a token exists, e.g. while
+// signing in; username may be an attempted one that matches no user
+func auditActor(ctx context.Context, tenantID, userID uint, username string) {
+	if e := audit.FromContext(ctx); e != nil {
+		e.TenantID, e.ActorID, e.ActorName = tenantID, userID, username
+	}
+}
+
+// auditUser records u as the actor of the current request
+func auditUser(ctx context.Context, u *user.User) {
+	auditActor(ctx, u.TenantID, u.ID, u.Username)
+}
+
+// auditTarget records the entity the current request acted on
+func auditTarget(ctx context.Context, targetID uint) {
+	if e := audit.FromContext(ctx); e != nil {
+		e.TargetID = strconv.FormatUint(uint64(targetID), 10)
+	}
+}
+
+// auditChange records the entity the current request changed and the
+// difference between its representations before and after the change. Pass
+// nil before for a creation and nil after for a deletion.
+func auditChange(ctx context.Context, targetID uint, before, after any) {
+	auditTarget(ctx, targetID)
+	if e := audit.FromContext(ctx); e != nil {
+		e.Changes = audit.Diff(before, after)
+	}
+}
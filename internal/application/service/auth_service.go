@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/audit"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/captcha"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+)
+
+// LockedError is returned when login is throttled; it unwraps to errcode.ErrAccountLocked
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string { return errcode.ErrAccountLocked.Message }
+func (e *LockedError) Unwrap() error { return errcode.ErrAccountLocked }
+
+// AuthAppService orchestrates login, token refresh and logout
+type AuthAppService struct {
+	users         user.Repository
+	tenants       tenant.Repository
+	tokens        *TokenService
+	passwords     *PasswordService
+	mfa           *MFAAppService
+	authenticator Authenticator
+	throttle      *LoginThrottle
+	captchas      *captcha.Manager
+	captchaAfter  int
+}
+
+// NewAuthAppService creates a new application service. Passwords at login
+// are checked by authenticator and failures counted by throttle. Login requires
+// a captcha once the account or client IP has failed captchaAfter times; 0
+// never requires one.
+func NewAuthAppService(users user.Repository, tenants tenant.Repository, tokens *TokenService, passwords *PasswordService, mfa *MFAAppService, authenticator Authenticator, throttle *LoginThrottle, captchas *captcha.Manager, captchaAfter int) *AuthAppService {
+	return &AuthAppService{users: users, tenants: tenants, tokens: tokens, passwords: passwords, mfa: mfa, authenticator: authenticator, throttle: throttle, captchas: captchas, captchaAfter: captchaAfter}
+}
+
+// Login verifies credentials in the requested tenant and starts a new token
+// family. Failures are throttled per account and per client IP, first with a
+// captcha and then with a lockout.
+// Users with 2FA get a short-lived "mfa_pending" token instead, to be
+// exchanged through VerifyMFA.
+func (s *AuthAppService) Login(ctx context.Context, req *dto.LoginRequest, client ClientInfo) (*dto.TokenResponse, error) {
+	tenantCode := req.Tenant
+	if tenantCode == "" {
+		tenantCode = tenant.DefaultCode
+	}
+	account := accountKey(tenantCode, req.Username)
+	auditActor(ctx, 0, 0, req.Username)
+	if err := s.throttle.Locked(ctx, account, client.IP); err != nil {
+		if t, err := s.tenants.FindByCode(tenantCode); err == nil {
+			auditActor(ctx, t.ID, 0, req.Username)
+		}
+		auditEvent(ctx, audit.ActionLoginLocked)
+		return nil, err
+	}
+	if s.captchaRequired(ctx, account, client.IP) && !s.captchas.Verify(ctx, req.CaptchaID, req.CaptchaAnswer) {
+		auditEvent(ctx, audit.ActionLoginFailure)
+		if req.CaptchaID == "" {
+			return nil, errcode.ErrCaptchaRequired
+		}
+		return nil, errcode.ErrCaptchaInvalid
+	}
+
+	u, err := s.Authenticate(ctx, tenantCode, req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, errcode.ErrServerBusy) {
+			return nil, err
+		}
+		auditEvent(ctx, audit.ActionLoginFailure)
+		// A directory outage counts as well, or it would lift the guessing
+		// limit for local accounts checked before the directory
+		if errors.Is(err, errcode.ErrInvalidCredential) || errors.Is(err, errcode.ErrDirectoryUnavailable) {
+			if lockErr := s.throttle.Fail(ctx, account, client.IP); lockErr != nil {
+				logger.Warnf("login locked tenant=%s username=%s ip=%s", tenantCode, req.Username, client.IP)
+				auditEvent(ctx, audit.ActionLoginLocked)
+				return nil, lockErr
+			}
+			// Ask for a captcha along with the next attempt right away
+			if s.captchaRequired(ctx, account, client.IP) {
+				return nil, errcode.ErrCaptchaRequired.WithMessage(err.Error() + "，请输入验证码")
+			}
+		}
+		return nil, err
+	}
+
+	enabled, err := s.mfa.Enabled(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		// Failure counters are kept until the second factor passes, so a
+		// correct password cannot be used to reset the code guessing budget
+		auditEvent(ctx, audit.ActionMFAChallenge)
+		return s.tokens.IssueMFAChallenge(u)
+	}
+
+	s.throttle.Clear(ctx, account)
+	auditEvent(ctx, audit.ActionLoginSuccess)
+	return s.issue(u, "", client)
+}
+
+// VerifyMFA exchanges an "mfa_pending" token and a TOTP or recovery code for a
+// token pair. Wrong codes count towards the same lockout as wrong passwords.
+func (s *AuthAppService) VerifyMFA(ctx context.Context, claims *Claims, req *dto.MFACodeRequest, client ClientInfo) (*dto.TokenResponse, error) {
+	if claims.Restriction != RestrictionMFAPending {
+		return nil, errcode.ErrInvalidToken
+	}
+	account, err := s.throttle.Account(claims.TenantID, claims.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.throttle.Locked(ctx, account, client.IP); err != nil {
+		auditEvent(ctx, audit.ActionLoginLocked)
+		return nil, err
+	}
+
+	u, err := s.GetUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsActive() {
+		return nil, errcode.ErrAccountDisabled
+	}
+
+	if err := s.mfa.Verify(u, req.Code); err != nil {
+		if errors.Is(err, errcode.ErrInvalidOTP) {
+			auditEvent(ctx, audit.ActionLoginFailure)
+			if lockErr := s.throttle.Fail(ctx, account, client.IP); lockErr != nil {
+				logger.Warnf("mfa locked username=%s ip=%s", u.Username, client.IP)
+				auditEvent(ctx, audit.ActionLoginLocked)
+				return nil, lockErr
+			}
+		}
+		return nil, err
+	}
+
+	// The challenge token is single-use
+	if err := s.tokens.Revoke(ctx, claims); err != nil {
+		return nil, err
+	}
+	s.throttle.Clear(ctx, account)
+	auditEvent(ctx, audit.ActionLoginSuccess)
+	return s.issue(u, "", client)
+}
+
+// SignIn starts a session for a user already authenticated by other means,
+// such as single sign-on. A local second factor is still enforced.
+func (s *AuthAppService) SignIn(ctx context.Context, u *user.User, client ClientInfo) (*dto.TokenResponse, error) {
+	auditUser(ctx, u)
+	if !u.IsActive() {
+		auditEvent(ctx, audit.ActionLoginFailure)
+		return nil, errcode.ErrAccountDisabled
+	}
+	enabled, err := s.mfa.Enabled(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		auditEvent(ctx, audit.ActionMFAChallenge)
+		return s.tokens.IssueMFAChallenge(u)
+	}
+	auditEvent(ctx, audit.ActionLoginSuccess)
+	return s.issue(u, "", client)
+}
+
+// SignInMultiFactor starts a session for a user authenticated by a credential
+// that is a second factor in itself, such as a passkey that verified the user.
+// Neither a TOTP code nor TOTP enrollment is asked for.
+func (s *AuthAppService) SignInMultiFactor(ctx context.Context, u *user.User, client ClientInfo) (*dto.TokenResponse, error) {
+	auditUser(ctx, u)
+	if !u.IsActive() {
+		auditEvent(ctx, audit.ActionLoginFailure)
+		return nil, errcode.ErrAccountDisabled
+	}
+	auditEvent(ctx, audit.ActionLoginSuccess)
+	restriction := ""
+	if u.MustChangePassword {
+		restriction = RestrictionPasswordChange
+	}
+	return s.tokens.IssuePair(u, "", restriction, client)
+}
+
+// issue creates a token pair carrying whatever restriction the user's state
+// calls for; an empty familyID starts a new session for the client
+func (s *AuthAppService) issue(u *user.User, familyID string, client ClientInfo) (*dto.TokenResponse, error) {
+	restriction, err := s.restrictionFor(u)
+	if err != nil {
+		return nil, err
+	}
+	return s.tokens.IssuePair(u, familyID, restriction, client)
+}
+
+// restrictionFor returns the restriction the user's tokens must carry, if any
+func (s *AuthAppService) restrictionFor(u *user.User) (string, error) {
+	if u.MustChangePassword {
+		return RestrictionPasswordChange, nil
+	}
+	required, err := s.mfa.Required(u)
+	if err != nil || !required {
+		return "", err
+	}
+	enabled, err := s.mfa.Enabled(u.ID)
+	if err != nil {
+		return "", err
+	}
+	if !enabled {
+		return RestrictionMFAEnroll, nil
+	}
+	return "", nil
+}
+
+// NewCaptcha returns a captcha challenge for the login form
+func (s *AuthAppService) NewCaptcha(ctx context.Context) (*dto.CaptchaResponse, error) {
+	c, err := s.captchas.Generate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CaptchaResponse{
+		CaptchaID: c.ID,
+		Image:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(c.Image),
+		ExpiresIn: int(s.captchas.TTL().Seconds()),
+	}, nil
+}
+
+// captchaRequired reports whether the account or client IP has failed often
+// enough for login to need a captcha
+func (s *AuthAppService) captchaRequired(ctx context.Context, account, clientIP string) bool {
+	if s.captchaAfter <= 0 {
+		return false
+	}
+	return s.throttle.Failures(ctx, account, clientIP) >= s.captchaAfter
+}
+
+// Authenticate verifies username and password within the tenant with the
+// configured authenticators and returns the matching user
+func (s *AuthAppService) Authenticate(ctx context.Context, tenantCode, username, pwd string) (*user.User, error) {
+	return s.authenticator.Authenticate(ctx, tenantCode, username, pwd)
+}
+
+// Refresh rotates a refresh token. The user is re-read so that deleted,
+// disabled or demoted accounts cannot keep refreshing with stale claims.
+func (s *AuthAppService) Refresh(ctx context.Context, req *dto.RefreshTokenRequest) (*dto.TokenResponse, error) {
+	rt, err := s.tokens.ConsumeRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// The refresh token is not tenant-scoped; its user decides the tenant
+	u, err := s.GetUser(tenant.WithAllTenants(ctx), rt.UserID)
+	if err == nil && !u.IsActive() {
+		err = errcode.ErrAccountDisabled
+	}
+	if err != nil {
+		_ = s.tokens.RevokeSession(ctx, rt.FamilyID)
+		return nil, err
+	}
+	auditUser(ctx, u)
+	return s.issue(u, rt.FamilyID, ClientInfo{})
+}
+
+// Logout ends the current session (its access and refresh tokens), or every
+// session of the user when req.All is set
+func (s *AuthAppService) Logout(ctx context.Context, claims *Claims, req *dto.LogoutRequest) error {
+	// An impersonation only ends itself, never the user's own sessions
+	if req.All && !claims.IsImpersonated() {
+		auditEvent(ctx, audit.ActionSessionRevokeAll)
+		return s.tokens.RevokeAll(ctx, claims.UserID)
+	}
+	auditEvent(ctx, audit.ActionLogout)
+	if claims.SessionID != "" {
+		if err := s.tokens.RevokeSession(ctx, claims.SessionID); err != nil {
+			return err
+		}
+	}
+	if req.RefreshToken != "" {
+		if err := s.tokens.RevokeRefreshToken(ctx, req.RefreshToken, claims.UserID); err != nil {
+			return err
+		}
+	}
+	return s.tokens.Revoke(ctx, claims)
+}
+
+// ChangePassword changes the current user's password after verifying the old one.
+// Every existing token is revoked and a fresh, unrestricted pair is returned.
+// A wrong old password counts towards the same lockout as a failed login, so
+// a stolen session cannot be used to guess the password without limit.
+func (s *AuthAppService) ChangePassword(ctx context.Context, claims *Claims, req *dto.ChangePasswordRequest, client ClientInfo) (*dto.TokenResponse, error) {
+	u, err := s.GetUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.throttle.Account(u.TenantID, u.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.throttle.Locked(ctx, account, client.IP); err != nil {
+		auditEvent(ctx, audit.ActionLoginLocked)
+		return nil, err
+	}
+	ok, err := s.passwords.Verify(ctx, u, req.OldPassword)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if lockErr := s.throttle.Fail(ctx, account, client.IP); lockErr != nil {
+			logger.Warnf("password change locked username=%s ip=%s", u.Username, client.IP)
+			auditEvent(ctx, audit.ActionLoginLocked)
+			return nil, lockErr
+		}
+		return nil, errcode.ErrWrongPassword
+	}
+	s.throttle.Clear(ctx, account)
+
+	if err := s.passwords.Change(ctx, u, req.NewPassword); err != nil {
+		return nil, err
+	}
+	return s.issue(u, "", client)
+}
+
+// GetUser loads a user by ID from the tenant carried by ctx
+func (s *AuthAppService) GetUser(ctx context.Context, id uint) (*user.User, error) {
+	u, err := s.users.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, errcode.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return u, nil
+}
@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/container"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/errcode"
+)
+
+const (
+	testPassword = "Zq8#vLm2!pT9x"
+	testClientIP = "192.0.2.50"
+)
+
+// signIn creates a user with testPassword and returns the claims of a fresh session
+func signIn(t *testing.T, c *container.Container, username string) *service.Claims {
+	t.Helper()
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+	if _, err := c.UserService.Create(ctx, &dto.CreateUserRequest{Username: username, Password: testPassword}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.AuthService.Login(context.Background(), &dto.LoginRequest{Username: username, Password: testPassword}, service.ClientInfo{IP: testClientIP})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	claims, err := c.TokenService.Parse(context.Background(), resp.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+// requireLocked fails the test unless err is a lockout
+func requireLocked(t *testing.T, err error) {
+	t.Helper()
+	var locked *service.LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("error = %v, want the account locked", err)
+	}
+}
+
+func TestChangePasswordWrongOldPasswordLocksOut(t *testing.T) {
+	c := newTestContainer(t, nil)
+	claims := signIn(t, c, "judy")
+	client := service.ClientInfo{IP: testClientIP}
+	ctx := tenant.WithTenant(context.Background(), claims.TenantID)
+
+	threshold := config.DefaultConfig().Security.Login.MaxAccountFailures
+	for i := 1; i < threshold; i++ {
+		_, err := c.AuthService.ChangePassword(ctx, claims, &dto.ChangePasswordRequest{OldPassword: "guess", NewPassword: "Xw7!kPq3#mN8z"}, client)
+		requireCode(t, err, errcode.ErrWrongPassword)
+	}
+	_, err := c.AuthService.ChangePassword(ctx, claims, &dto.ChangePasswordRequest{OldPassword: "guess", NewPassword: "Xw7!kPq3#mN8z"}, client)
+	requireLocked(t, err)
+
+	// While locked even the right password is refused, here and at login
+	_, err = c.AuthService.ChangePassword(ctx, claims, &dto.ChangePasswordRequest{OldPassword: testPassword, NewPassword: "Xw7!kPq3#mN8z"}, client)
+	requireLocked(t, err)
+	_, err = c.AuthService.Login(context.Background(), &dto.LoginRequest{Username: "judy", Password: testPassword}, service.ClientInfo{IP: "192.0.2.51"})
+	requireLocked(t, err)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/errcode"
+)
+
+// Authenticator verifies a username and password within a tenant and
+// returns the matching local user. Credentials it cannot vouch for fail with
+// errcode.ErrInvalidCredential so that a chain can try the next one.
+type Authenticator interface {
+	Authenticate(ctx context.Context, tenantCode, username, pwd string) (*user.User, error)
+}
+
+// Authenticators is a chain tried in order: the first one to accept the
+// credentials or to fail with anything but invalid credentials decides
+type Authenticators []Authenticator
+
+// Authenticate implements Authenticator
+func (a Authenticators) Authenticate(ctx context.Context, tenantCode, username, pwd string) (*user.User, error) {
+	for _, authenticator := range a {
+		u, err := authenticator.Authenticate(ctx, tenantCode, username, pwd)
+		if err == nil || !errors.Is(err, errcode.ErrInvalidCredential) {
+			return u, err
+		}
+	}
+	return nil, errcode.ErrInvalidCredential
+}
+
+// LocalAuthenticator checks passwords against the hashes in the database
+type LocalAuthenticator struct {
+	users     user.Repository
+	tenants   tenant.Repository
+	passwords *PasswordService
+}
+
+// NewLocalAuthenticator creates a new authenticator
+func NewLocalAuthenticator(users user.Repository, tenants tenant.Repository, passwords *PasswordService) *LocalAuthenticator {
+	return &LocalAuthenticator{users: users, tenants: tenants, passwords: passwords}
+}
+
+// Authenticate implements Authenticator. Unknown tenants fail like unknown
+// usernames.
+func (a *LocalAuthenticator) Authenticate(ctx context.Context, tenantCode, username, pwd string) (*user.User, error) {
+	t, err := a.tenants.FindByCode(tenantCode)
+	if err != nil && !errors.Is(err, tenant.ErrTenantNotFound) {
+		return nil, err
+	}
+	var u *user.User
+	if err == nil {
+		auditActor(ctx, t.ID, 0, username)
+		ctx = tenant.WithTenant(ctx, t.ID)
+		u, err = a.users.FindByUsername(ctx, username)
+	}
+	if err != nil {
+		if errors.Is(err, tenant.ErrTenantNotFound) || errors.Is(err, user.ErrUserNotFound) {
+			// Burn the same CPU as a real check so response time does not reveal valid usernames
+			if err := a.passwords.VerifyDummy(ctx, pwd); err != nil {
+				return nil, err
+			}
+			return nil, errcode.ErrInvalidCredential
+		}
+		return nil, err
+	}
+
+	auditUser(ctx, u)
+
+	// Service accounts have no password and sign in with API keys only
+	if u.ServiceAccount {
+		return nil, errcode.ErrInvalidCredential
+	}
+	ok, err := a.passwords.VerifyLogin(ctx, u, pwd)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errcode.ErrInvalidCredential
+	}
+	// Checked after the password so the status of an account is not revealed to guessers
+	if !u.IsActive() {
+		return nil, errcode.ErrAccountDisabled
+	}
+	return u, nil
+}
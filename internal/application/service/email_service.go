@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/audit"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/token"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/cache"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+	"go-ddd-scaffold/pkg/mailer"
+)
+
+// mailInterval is the minimum time between two mails of one kind to one user
+const mailInterval = time.Minute
+
+// Mail templates
+const (
+	templateVerifyEmail   = "verify_email"
+	templateResetPassword = "reset_password"
+)
+
+// EmailAppService verifies email addresses and resets forgotten passwords
+// through single-use links sent by mail. Only token hashes are stored, and
+// a token is only valid for the address it was sent to.
+type EmailAppService struct {
+	users     user.Repository
+	tenants   tenant.Repository
+	tokens    token.ActionRepository
+	passwords *PasswordService
+	mailer    mailer.Mailer
+	templates *mailer.Templates
+	cache     cache.Cache
+	appName   string
+	linkBase  string
+	verifyTTL time.Duration
+	resetTTL  time.Duration
+
+	sending sync.WaitGroup
+}
+
+// NewEmailAppService creates a new application service. Mailed links open
+// linkBaseURL/login with a verify_token or reset_token query parameter.
+func NewEmailAppService(users user.Repository, tenants tenant.Repository, tokens token.ActionRepository, passwords *PasswordService, m mailer.Mailer, templates *mailer.Templates, c cache.Cache, appName, linkBaseURL string, verifyTTL, resetTTL time.Duration) *EmailAppService {
+	return &EmailAppService{
+		users:     users,
+		tenants:   tenants,
+		tokens:    tokens,
+		passwords: passwords,
+		mailer:    m,
+		templates: templates,
+		cache:     c,
+		appName:   appName,
+		linkBase:  strings.TrimRight(linkBaseURL, "/"),
+		verifyTTL: verifyTTL,
+		resetTTL:  resetTTL,
+	}
+}
+
+// SendVerification mails a verification link to the user's address unless
+// it is empty or already verified. Earlier links stop working.
+func (s *EmailAppService) SendVerification(ctx context.Context, u *user.User) error {
+	if u.Email == "" || u.IsEmailVerified() || u.ServiceAccount {
+		return nil
+	}
+	return s.send(ctx, u, token.PurposeEmailVerification, templateVerifyEmail, "verify_token", s.verifyTTL)
+}
+
+// ResendVerification mails a new verification link to the caller
+func (s *EmailAppService) ResendVerification(ctx context.Context, claims *Claims) error {
+	u, err := s.users.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case u.Email == "":
+		return errcode.ErrEmailRequired
+	case u.IsEmailVerified():
+		return errcode.ErrEmailVerified
+	case s.throttled(ctx, token.PurposeEmailVerification, u.ID):
+		return errcode.ErrTooFrequent
+	}
+	return s.SendVerification(ctx, u)
+}
+
+// VerifyEmail marks the address a verification link was sent to as verified
+func (s *EmailAppService) VerifyEmail(ctx context.Context, req *dto.VerifyEmailRequest) error {
+	ctx, u, err := s.redeem(ctx, req.Token, token.PurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+	u.VerifyEmail(time.Now())
+	if err := s.users.Save(ctx, u); err != nil {
+		return err
+	}
+	auditEvent(ctx, audit.ActionEmailVerified)
+	return nil
+}
+
+// ForgotPassword mails a password reset link if the tenant has an active user
+// with this verified address. The outcome is never revealed to the caller.
+func (s *EmailAppService) ForgotPassword(ctx context.Context, req *dto.ForgotPasswordRequest) error {
+	code := req.Tenant
+	if code == "" {
+		code = tenant.DefaultCode
+	}
+	t, err := s.tenants.FindByCode(code)
+	if err != nil {
+		if errors.Is(err, tenant.ErrTenantNotFound) {
+			return nil
+		}
+		return err
+	}
+	ctx = tenant.WithTenant(ctx, t.ID)
+
+	u, err := s.users.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	auditUser(ctx, u)
+	auditEvent(ctx, audit.ActionPasswordForgot)
+	// Accounts without a local password sign in through single sign-on or API keys
+	if !u.IsEmailVerified() || !u.IsActive() || u.PasswordHash == "" || s.throttled(ctx, token.PurposePasswordReset, u.ID) {
+		return nil
+	}
+	return s.send(ctx, u, token.PurposePasswordReset, templateResetPassword, "reset_token", s.resetTTL)
+}
+
+// ResetPassword sets a new password with a reset link. Like a password
+// change it signs the user out everywhere; a second factor is still required
+// at the next login.
+func (s *EmailAppService) ResetPassword(ctx context.Context, req *dto.ResetPasswordByTokenRequest) error {
+	rt, err := s.find(req.Token, token.PurposePasswordReset)
+	if err != nil {
+		return err
+	}
+	ctx, u, err := s.owner(ctx, rt)
+	if err != nil {
+		return err
+	}
+	if !u.IsActive() {
+		return errcode.ErrAccountDisabled
+	}
+	// Rejected passwords leave the link usable for another try
+	hash, err := s.passwords.Prepare(ctx, u, req.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.use(rt); err != nil {
+		return err
+	}
+
+	// Following the link proved control of the address
+	if !u.IsEmailVerified() {
+		u.VerifyEmail(time.Now())
+	}
+	if err := s.passwords.Set(ctx, u, hash); err != nil {
+		return err
+	}
+	auditEvent(ctx, audit.ActionPasswordReset)
+	return nil
+}
+
+// Forget removes the user's tokens, e.g. when the user is deleted
+func (s *EmailAppService) Forget(userID uint) error {
+	return s.tokens.DeleteByUser(userID)
+}
+
+// Close waits for mails being sent
+func (s *EmailAppService) Close() {
+	s.sending.Wait()
+}
+
+// send issues a token for the purpose, replacing older ones, and mails the
+// link carrying it in the param query parameter
+func (s *EmailAppService) send(ctx context.Context, u *user.User, purpose token.Purpose, template, param string, ttl time.Duration) error {
+	raw, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := s.tokens.InvalidateByUser(u.ID, purpose, now); err != nil {
+		return err
+	}
+	if err := s.tokens.DeleteExpired(u.ID, now); err != nil {
+		logger.Warnf("failed to delete expired action tokens of user %d: %v", u.ID, err)
+	}
+	if err := s.tokens.Create(token.NewActionToken(u.ID, purpose, hashOpaqueToken(raw), u.Email, now.Add(ttl))); err != nil {
+		return err
+	}
+
+	msg, err := s.templates.Render(template, map[string]string{
+		"AppName":   s.appName,
+		"Username":  u.Username,
+		"Email":     u.Email,
+		"Link":      s.linkBase + "/login?" + url.Values{param: {raw}}.Encode(),
+		"ExpiresIn": humanDuration(ttl),
+	}, u.Email)
+	if err != nil {
+		return err
+	}
+	s.deliver(msg)
+	return nil
+}
+
+// deliver sends in the background, so responses neither wait for the mail
+// server nor reveal by their timing whether a mail was sent
+func (s *EmailAppService) deliver(msg *mailer.Message) {
+	s.sending.Add(1)
+	go func() {
+		defer s.sending.Done()
+		if err := s.mailer.Send(context.Background(), msg); err != nil {
+			logger.Errorf("failed to mail %q to %s: %v", msg.Subject, strings.Join(msg.To, ","), err)
+		}
+	}()
+}
+
+// redeem consumes a token and returns its user with ctx scoped to the user's tenant
+func (s *EmailAppService) redeem(ctx context.Context, raw string, purpose token.Purpose) (context.Context, *user.User, error) {
+	rt, err := s.find(raw, purpose)
+	if err != nil {
+		return ctx, nil, err
+	}
+	ctx, u, err := s.owner(ctx, rt)
+	if err != nil {
+		return ctx, nil, err
+	}
+	if err := s.use(rt); err != nil {
+		return ctx, nil, err
+	}
+	return ctx, u, nil
+}
+
+// find returns the unused, unexpired token for the purpose
+func (s *EmailAppService) find(raw string, purpose token.Purpose) (*token.ActionToken, error) {
+	rt, err := s.tokens.FindByHash(hashOpaqueToken(raw))
+	if err != nil {
+		if errors.Is(err, token.ErrActionTokenNotFound) {
+			return nil, errcode.ErrInvalidLink
+		}
+		return nil, err
+	}
+	if rt.Purpose != purpose || rt.IsUsed() || rt.IsExpired(time.Now()) {
+		return nil, errcode.ErrInvalidLink
+	}
+	return rt, nil
+}
+
+// owner loads the token's user, who must still have the address it was sent to
+func (s *EmailAppService) owner(ctx context.Context, rt *token.ActionToken) (context.Context, *user.User, error) {
+	// Action tokens are not tenant-scoped; their user decides the tenant
+	u, err := s.users.FindByID(tenant.WithAllTenants(ctx), rt.UserID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return ctx, nil, errcode.ErrInvalidLink
+		}
+		return ctx, nil, err
+	}
+	if u.Email != rt.Email {
+		return ctx, nil, errcode.ErrInvalidLink
+	}
+	ctx = tenant.WithTenant(ctx, u.TenantID)
+	auditUser(ctx, u)
+	return ctx, u, nil
+}
+
+// use marks the token used, failing if a concurrent request did first
+func (s *EmailAppService) use(rt *token.ActionToken) error {
+	ok, err := s.tokens.MarkUsed(rt.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errcode.ErrInvalidLink
+	}
+	return nil
+}
+
+// throttled reports whether a mail of this kind went to the user less than
+// mailInterval ago, and otherwise starts a new interval
+func (s *EmailAppService) throttled(ctx context.Context, purpose token.Purpose, userID uint) bool {
+	n, err := s.cache.Increment(ctx, fmt.Sprintf("mail_throttle:%s:%d", purpose, userID), mailInterval)
+	return err == nil && n > 1
+}
+
+// humanDuration formats a link lifetime for mails, e.g. "30 minutes" or "2 days"
+func humanDuration(d time.Duration) string {
+	n, unit := int(d/time.Minute), "minute"
+	switch {
+	case d >= 48*time.Hour && d%(24*time.Hour) == 0:
+		n, unit = int(d/(24*time.Hour)), "day"
+	case d >= time.Hour && d%time.Hour == 0:
+		n, unit = int(d/time.Hour), "hour"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
@@ -0,0 +1,121 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/mailer/mailertest"
+)
+
+var mailLink = regexp.MustCompile(`https?://\S+`)
+
+// mailedToken waits for a mail to addr whose subject contains subject and
+// returns the value of param in the link it carries
+func mailedToken(t *testing.T, smtp *mailertest.Server, addr, subject, param string) string {
+	t.Helper()
+	m := smtp.Wait(5*time.Second, func(m *mailertest.Message) bool {
+		return len(m.To) == 1 && m.To[0] == addr && strings.Contains(m.Subject, subject)
+	})
+	if m == nil {
+		t.Fatalf("no %q mail to %s", subject, addr)
+	}
+	link, err := url.Parse(mailLink.FindString(m.Text))
+	if err != nil {
+		t.Fatalf("mail has no usable link: %v\n%s", err, m.Text)
+	}
+	raw := link.Query().Get(param)
+	if raw == "" {
+		t.Fatalf("link %s has no %s", link, param)
+	}
+	return raw
+}
+
+func TestEmailVerificationAndPasswordResetOverSMTP(t *testing.T) {
+	smtp, err := mailertest.Start(mailertest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(smtp.Close)
+	c := newTestContainer(t, func(cfg *config.Config) {
+		cfg.Mail.Driver = "smtp"
+		cfg.Mail.SMTP.Host = smtp.Host
+		cfg.Mail.SMTP.Port = smtp.Port
+		cfg.Mail.SMTP.Encryption = "none"
+	})
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+
+	const email = "carol@example.com"
+	created, err := c.UserService.Create(ctx, &dto.CreateUserRequest{Username: "carol", Password: "Zq8#vLm2!pT9x", Email: email})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Verification
+	verifyToken := mailedToken(t, smtp, email, "Verify", "verify_token")
+	if err := c.EmailService.VerifyEmail(context.Background(), &dto.VerifyEmailRequest{Token: verifyToken}); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	u, err := c.UserService.GetByID(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.EmailVerified {
+		t.Fatal("email not verified after following the link")
+	}
+	err = c.EmailService.VerifyEmail(context.Background(), &dto.VerifyEmailRequest{Token: verifyToken})
+	requireCode(t, err, errcode.ErrInvalidLink)
+
+	// Password reset
+	smtp.Reset()
+	if err := c.EmailService.ForgotPassword(context.Background(), &dto.ForgotPasswordRequest{Email: email}); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	resetToken := mailedToken(t, smtp, email, "Reset", "reset_token")
+	const newPassword = "Wb5$kPq8!rNz3"
+	if err := c.EmailService.ResetPassword(context.Background(), &dto.ResetPasswordByTokenRequest{Token: resetToken, NewPassword: newPassword}); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	err = c.EmailService.ResetPassword(context.Background(), &dto.ResetPasswordByTokenRequest{Token: resetToken, NewPassword: "Xc7&mLp2!qVt9"})
+	requireCode(t, err, errcode.ErrInvalidLink)
+
+	client := service.ClientInfo{IP: "192.0.2.10"}
+	if _, err := c.AuthService.Login(context.Background(), &dto.LoginRequest{Username: "carol", Password: "Zq8#vLm2!pT9x"}, client); err == nil {
+		t.Fatal("old password still accepted after the reset")
+	}
+	if _, err := c.AuthService.Login(context.Background(), &dto.LoginRequest{Username: "carol", Password: newPassword}, client); err != nil {
+		t.Fatalf("Login with the new password: %v", err)
+	}
+}
+
+func TestForgotPasswordIgnoresUnknownAddresses(t *testing.T) {
+	smtp, err := mailertest.Start(mailertest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(smtp.Close)
+	c := newTestContainer(t, func(cfg *config.Config) {
+		cfg.Mail.Driver = "smtp"
+		cfg.Mail.SMTP.Host = smtp.Host
+		cfg.Mail.SMTP.Port = smtp.Port
+		cfg.Mail.SMTP.Encryption = "none"
+	})
+
+	if err := c.EmailService.ForgotPassword(context.Background(), &dto.ForgotPasswordRequest{Email: "nobody@example.com"}); err != nil {
+		t.Fatalf("ForgotPassword: %v", err)
+	}
+	c.EmailService.Close()
+	if n := len(smtp.Messages()); n != 0 {
+		t.Fatalf("%d mails sent for an unknown address", n)
+	}
+	err = c.EmailService.ResetPassword(context.Background(), &dto.ResetPasswordByTokenRequest{Token: "forged", NewPassword: "Wb5$kPq8!rNz3"})
+	requireCode(t, err, errcode.ErrInvalidLink)
+}
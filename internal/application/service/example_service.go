@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+
 	"go-ddd-scaffold/internal/application/dto"
 	"go-ddd-scaffold/internal/domain/example"
 )
@@ -16,19 +18,21 @@ func NewExampleAppService(repo example.Repository) *ExampleAppService {
 }
 
 // Create creates a new example
-func (s *ExampleAppService) Create(req *dto.CreateExampleRequest) (*dto.ExampleResponse, error) {
+func (s *ExampleAppService) Create(ctx context.Context, req *dto.CreateExampleRequest) (*dto.ExampleResponse, error) {
 	entity := example.NewExample(req.Name, req.Description)
 
-	if err := s.repo.Save(entity); err != nil {
+	if err := s.repo.Save(ctx, entity); err != nil {
 		return nil, err
 	}
 
-	return dto.FromExample(entity), nil
+	resp := dto.FromExample(entity)
+	auditChange(ctx, entity.ID, nil, resp)
+	return resp, nil
 }
 
 // GetByID returns an example by ID
-func (s *ExampleAppService) GetByID(id uint) (*dto.ExampleResponse, error) {
-	entity, err := s.repo.FindByID(id)
+func (s *ExampleAppService) GetByID(ctx context.Context, id uint) (*dto.ExampleResponse, error) {
+	entity, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +40,7 @@ func (s *ExampleAppService) GetByID(id uint) (*dto.ExampleResponse, error) {
 }
 
 // List returns paginated examples
-func (s *ExampleAppService) List(req *dto.QueryExampleRequest) ([]*dto.ExampleResponse, int64, error) {
+func (s *ExampleAppService) List(ctx context.Context, req *dto.QueryExampleRequest) ([]*dto.ExampleResponse, int64, error) {
 	if req.Page < 1 {
 		req.Page = 1
 	}
@@ -47,7 +51,7 @@ func (s *ExampleAppService) List(req *dto.QueryExampleRequest) ([]*dto.ExampleRe
 		req.PageSize = 100
 	}
 
-	entities, total, err := s.repo.List(req.Page, req.PageSize, req.Keyword, example.Status(req.Status))
+	entities, total, err := s.repo.List(ctx, req.Page, req.PageSize, req.Keyword, example.Status(req.Status))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -56,11 +60,12 @@ func (s *ExampleAppService) List(req *dto.QueryExampleRequest) ([]*dto.ExampleRe
 }
 
 // Update updates an example
-func (s *ExampleAppService) Update(id uint, req *dto.UpdateExampleRequest) (*dto.ExampleResponse, error) {
-	entity, err := s.repo.FindByID(id)
+func (s *ExampleAppService) Update(ctx context.Context, id uint, req *dto.UpdateExampleRequest) (*dto.ExampleResponse, error) {
+	entity, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	before := dto.FromExample(entity)
 
 	// Call domain methods
 	if req.Name != nil || req.Description != nil {
@@ -84,14 +89,23 @@ func (s *ExampleAppService) Update(id uint, req *dto.UpdateExampleRequest) (*dto
 		}
 	}
 
-	if err := s.repo.Save(entity); err != nil {
+	if err := s.repo.Save(ctx, entity); err != nil {
 		return nil, err
 	}
 
-	return dto.FromExample(entity), nil
+	resp := dto.FromExample(entity)
+	auditChange(ctx, entity.ID, before, resp)
+	return resp, nil
 }
 
 // Delete deletes an example
-func (s *ExampleAppService) Delete(id uint) error {
-	return s.repo.Delete(id)
+func (s *ExampleAppService) Delete(ctx context.Context, id uint) error {
+	before, _ := s.repo.FindByID(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if before != nil {
+		auditChange(ctx, id, dto.FromExample(before), nil)
+	}
+	return nil
 }
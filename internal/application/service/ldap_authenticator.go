@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/directory"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+)
+
+// ldapProvider is the identity provider name that links local users to
+// directory entries, which are identified by DN
+const ldapProvider = "ldap"
+
+// LDAPAuthenticator checks passwords against an LDAP directory or Active
+// Directory. Directory users live in the default tenant: they are linked or
+// provisioned there on first login, and their role follows their groups.
+type LDAPAuthenticator struct {
+	cfg        *config.LDAPConfig
+	directory  *directory.Client
+	users      user.Repository
+	identities user.IdentityRepository
+	rbac       *RBACAppService
+}
+
+// NewLDAPAuthenticator creates a new authenticator
+func NewLDAPAuthenticator(cfg *config.LDAPConfig, dir *directory.Client, users user.Repository, identities user.IdentityRepository, rbacSvc *RBACAppService) *LDAPAuthenticator {
+	return &LDAPAuthenticator{cfg: cfg, directory: dir, users: users, identities: identities, rbac: rbacSvc}
+}
+
+// Authenticate implements Authenticator
+func (a *LDAPAuthenticator) Authenticate(ctx context.Context, tenantCode, username, pwd string) (*user.User, error) {
+	if tenantCode != tenant.DefaultCode {
+		return nil, errcode.ErrInvalidCredential
+	}
+	auditActor(ctx, tenant.DefaultID, 0, username)
+
+	entry, err := a.directory.Authenticate(ctx, username, pwd)
+	if err != nil {
+		if errors.Is(err, directory.ErrInvalidCredentials) {
+			return nil, errcode.ErrInvalidCredential
+		}
+		logger.Errorf("ldap authentication failed username=%s: %v", username, err)
+		return nil, errcode.ErrDirectoryUnavailable
+	}
+
+	ctx = tenant.WithTenant(ctx, tenant.DefaultID)
+	u, err := a.resolveUser(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	auditUser(ctx, u)
+	if u.ServiceAccount {
+		return nil, errcode.ErrInvalidCredential
+	}
+	if !u.IsActive() {
+		return nil, errcode.ErrAccountDisabled
+	}
+	return u, nil
+}
+
+// resolveUser finds the user linked to the directory entry, linking an
+// existing user by username or provisioning a new one on first login, and
+// applies the role mapped from the entry's groups
+func (a *LDAPAuthenticator) resolveUser(ctx context.Context, entry *directory.Entry) (*user.User, error) {
+	role, matched := a.mapRole(entry)
+	subject := strings.ToLower(entry.DN)
+
+	var u *user.User
+	identity, err := a.identities.Find(ldapProvider, subject)
+	switch {
+	case err == nil:
+		if u, err = a.users.FindByID(ctx, identity.UserID); err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				return nil, errcode.ErrAccountNotFound
+			}
+			return nil, err
+		}
+	case errors.Is(err, user.ErrIdentityNotFound):
+		if u, err = a.linkOrProvision(ctx, entry, role); err != nil {
+			return nil, err
+		}
+		if err := a.identities.Save(user.NewIdentity(u.ID, ldapProvider, subject)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if matched {
+		if err := a.rbac.SyncMappedRole(ctx, u, role); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+func (a *LDAPAuthenticator) linkOrProvision(ctx context.Context, entry *directory.Entry, role string) (*user.User, error) {
+	username := entry.Username
+	if len(username) > maxUsernameLength {
+		return nil, errcode.ErrInvalidCredential.WithMessage("directory username is too long")
+	}
+	existing, err := a.users.FindByUsername(ctx, username)
+	switch {
+	case err == nil:
+		// A local account with the same name is only taken over when configured
+		if !a.cfg.LinkByUsername || existing.ServiceAccount {
+			return nil, errcode.ErrAccountExists
+		}
+		return existing, nil
+	case !errors.Is(err, user.ErrUserNotFound):
+		return nil, err
+	}
+
+	if !a.cfg.AutoProvision {
+		return nil, errcode.ErrAccountNotFound.WithMessage("no local account is linked to this directory user")
+	}
+	if role == "" {
+		return nil, errcode.ErrPermissionDenied.WithMessage("no role is mapped for this directory user")
+	}
+	if err := a.rbac.CheckMappedRole(role); err != nil {
+		return nil, err
+	}
+
+	// No local password: the user signs in through the directory only
+	u := user.NewUser(username, "", user.Role(role))
+	u.UpdateProfile(entry.Name, entry.Email)
+	// Directory addresses are maintained by its administrators
+	if entry.Email != "" {
+		u.VerifyEmail(time.Now())
+	}
+	if err := a.users.Save(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// mapRole returns the role of the first mapping whose group the entry is
+// in, or the default role with matched set to false
+func (a *LDAPAuthenticator) mapRole(entry *directory.Entry) (role string, matched bool) {
+	for _, m := range a.cfg.RoleMappings {
+		if entry.InGroup(m.Group) {
+			return m.Role, true
+		}
+	}
+	return a.cfg.DefaultRole, false
+}
@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/container"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/directory/directorytest"
+	"go-ddd-scaffold/pkg/errcode"
+)
+
+// newLDAPTest starts a directory with alice (group "admins" maps to the admin
+// role) and bob (no mapped group), and a container that tries local
+// passwords first and the directory second
+func newLDAPTest(t *testing.T) (*container.Container, *directorytest.Server) {
+	t.Helper()
+	dir, err := directorytest.Start(directorytest.Options{
+		Users: []directorytest.User{
+			{Username: "alice", Password: "alice-secret", Name: "Alice Liddell", Email: "alice@example.org", Groups: []string{"admins"}},
+			{Username: "bob", Password: "bob-secret", Groups: []string{"staff"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(dir.Close)
+
+	c := newTestContainer(t, func(cfg *config.Config) {
+		cfg.Security.Authenticators = []string{"local", "ldap"}
+		cfg.Security.Login.CaptchaAfter = 0
+		cfg.LDAP.URL = dir.URL
+		cfg.LDAP.BindDN = dir.BindDN
+		cfg.LDAP.BindPassword = dir.BindPassword
+		cfg.LDAP.BaseDN = dir.BaseDN
+		cfg.LDAP.RoleMappings = []config.SSORoleMapping{{Group: "admins", Role: "admin"}}
+	})
+	return c, dir
+}
+
+func ldapLogin(c *container.Container, username, password string) (*dto.TokenResponse, error) {
+	return c.AuthService.Login(context.Background(), &dto.LoginRequest{Username: username, Password: password},
+		service.ClientInfo{IP: "192.0.2.30"})
+}
+
+func TestLDAPLoginProvisionsAndMapsGroups(t *testing.T) {
+	c, _ := newLDAPTest(t)
+	ctx := context.Background()
+
+	resp, err := ldapLogin(c, "alice", "alice-secret")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	claims, err := c.TokenService.Parse(ctx, resp.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Username != "alice" || claims.Role != "admin" {
+		t.Fatalf("signed in as %s with role %s, want alice with admin", claims.Username, claims.Role)
+	}
+	u, err := c.UserService.GetByID(tenant.WithTenant(ctx, tenant.DefaultID), claims.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Email != "alice@example.org" || !u.EmailVerified {
+		t.Fatalf("provisioned user = %+v", u)
+	}
+
+	// The second login finds the linked user instead of provisioning another
+	resp, err = ldapLogin(c, "alice", "alice-secret")
+	if err != nil {
+		t.Fatalf("second Login: %v", err)
+	}
+	if again, _ := c.TokenService.Parse(ctx, resp.Token); again == nil || again.UserID != claims.UserID {
+		t.Fatalf("second login signed in as %+v, want user %d", again, claims.UserID)
+	}
+
+	// Without a mapped group the default role applies
+	resp, err = ldapLogin(c, "bob", "bob-secret")
+	if err != nil {
+		t.Fatalf("Login bob: %v", err)
+	}
+	if claims, _ := c.TokenService.Parse(ctx, resp.Token); claims == nil || claims.Role != "user" {
+		t.Fatalf("bob signed in with %+v, want role user", claims)
+	}
+}
+
+func TestLDAPLoginRejectsInvalidCredentials(t *testing.T) {
+	c, _ := newLDAPTest(t)
+
+	for name, tc := range map[string]struct{ username, password string }{
+		"wrong password": {"alice", "bob-secret"},
+		"unknown user":   {"carol", "alice-secret"},
+		// An empty password would be an anonymous bind, which LDAP servers accept
+		"empty password": {"alice", ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ldapLogin(c, tc.username, tc.password)
+			requireCode(t, err, errcode.ErrInvalidCredential)
+		})
+	}
+}
+
+func TestLDAPDirectoryDownCountsTowardLockout(t *testing.T) {
+	c, dir := newLDAPTest(t)
+	dir.Close()
+
+	threshold := config.DefaultConfig().Security.Login.MaxAccountFailures
+	for i := 1; i < threshold; i++ {
+		_, err := ldapLogin(c, "alice", "alice-secret")
+		requireCode(t, err, errcode.ErrDirectoryUnavailable)
+	}
+	_, err := ldapLogin(c, "alice", "alice-secret")
+	var locked *service.LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("error = %v after %d failures, want the account locked", err, threshold)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/pkg/lockout"
+)
+
+// LoginThrottle counts wrong passwords and one-time codes per account and per
+// client IP, and locks either out once it fails too often. Every check of a
+// user's secret shares it, so guesses spread over login, the second-factor
+// step and the account settings all draw on the same budget.
+type LoginThrottle struct {
+	tenants     tenant.Repository
+	accountLock *lockout.Manager
+	ipLock      *lockout.Manager
+}
+
+// NewLoginThrottle creates a throttle from one lockout per account and one per client IP
+func NewLoginThrottle(tenants tenant.Repository, accountLock, ipLock *lockout.Manager) *LoginThrottle {
+	return &LoginThrottle{tenants: tenants, accountLock: accountLock, ipLock: ipLock}
+}
+
+// Account returns the lockout key of a user known by tenant ID
+func (t *LoginThrottle) Account(tenantID uint, username string) (string, error) {
+	te, err := t.tenants.FindByID(tenantID)
+	if err != nil {
+		return "", err
+	}
+	return accountKey(te.Code, username), nil
+}
+
+// Locked returns a LockedError carrying the longer of the two remaining lockouts
+func (t *LoginThrottle) Locked(ctx context.Context, account, clientIP string) error {
+	wait := t.accountLock.LockedFor(ctx, account)
+	if d := t.ipLock.LockedFor(ctx, clientIP); d > wait {
+		wait = d
+	}
+	if wait > 0 {
+		return &LockedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// Fail counts a wrong secret against the account and the client IP, and
+// returns a LockedError once either of them is locked out
+func (t *LoginThrottle) Fail(ctx context.Context, account, clientIP string) error {
+	t.accountLock.RecordFailure(ctx, account)
+	t.ipLock.RecordFailure(ctx, clientIP)
+	return t.Locked(ctx, account, clientIP)
+}
+
+// Failures returns the larger of the account's and the client IP's failure counts
+func (t *LoginThrottle) Failures(ctx context.Context, account, clientIP string) int {
+	return max(t.accountLock.Failures(ctx, account), t.ipLock.Failures(ctx, clientIP))
+}
+
+// Clear forgets the account's failures after it proved its secret. The per-IP
+// counter is left to expire on its own: clearing it would let anyone holding
+// one valid account reset the budget for guessing others.
+func (t *LoginThrottle) Clear(ctx context.Context, account string) {
+	t.accountLock.Clear(ctx, account)
+}
+
+// accountKey identifies an account for lockout; usernames are only unique
+// within a tenant. Default-tenant accounts keep their bare username.
+func accountKey(tenantCode, username string) string {
+	if tenantCode == tenant.DefaultCode {
+		return username
+	}
+	return tenantCode + "/" + username
+}
@@ -0,0 +1,56 @@
+package service_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-ddd-scaffold/internal/container"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	// Keep expected warnings (failed logins, rejected tokens) out of the test output
+	if err := logger.Init(&config.LogConfig{Level: "error", Format: "console", Output: "console"}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// newTestContainer wires every service against a fresh SQLite database and
+// an in-memory cache, after letting the test adjust the default config.
+// Password hashing uses the cheapest bcrypt cost to keep tests fast.
+func newTestContainer(t *testing.T, configure func(cfg *config.Config)) *container.Container {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Database.Type = "sqlite"
+	cfg.Database.Path = filepath.Join(t.TempDir(), "test.db")
+	cfg.Cache.Driver = "memory"
+	cfg.Security.PasswordHash.Algorithm = "bcrypt"
+	cfg.Security.PasswordHash.BcryptCost = 4
+	if configure != nil {
+		configure(cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid test config: %v", err)
+	}
+
+	c, err := container.New(cfg)
+	if err != nil {
+		t.Fatalf("container.New: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+// requireCode fails the test unless err is an errcode error with want's code
+func requireCode(t *testing.T, err error, want *errcode.Error) {
+	t.Helper()
+	var e *errcode.Error
+	if !errors.As(err, &e) || e.Code != want.Code {
+		t.Fatalf("error = %v, want code %d (%s)", err, want.Code, want.Message)
+	}
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/mfa"
+	"go-ddd-scaffold/internal/domain/rbac"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+	"go-ddd-scaffold/pkg/totp"
+)
+
+const (
+	recoveryCodeCount = 10
+	qrCodeSize        = 256
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFAAppService orchestrates TOTP enrollment, recovery codes and second-factor checks
+type MFAAppService struct {
+	repo     mfa.Repository
+	users    user.Repository
+	roles    rbac.Repository
+	throttle *LoginThrottle
+	issuer   string
+}
+
+// NewMFAAppService creates a new application service. Wrong codes given to
+// change the second factor are counted by throttle; issuer is the account
+// label shown in authenticator apps.
+func NewMFAAppService(repo mfa.Repository, users user.Repository, roles rbac.Repository, throttle *LoginThrottle, issuer string) *MFAAppService {
+	return &MFAAppService{repo: repo, users: users, roles: roles, throttle: throttle, issuer: issuer}
+}
+
+// Required reports whether the user's role requires a second factor
+func (s *MFAAppService) Required(u *user.User) (bool, error) {
+	role, err := s.roles.FindRoleByCode(string(u.Role))
+	if err != nil {
+		if errors.Is(err, rbac.ErrRoleNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return role.RequireMFA, nil
+}
+
+// Enabled reports whether the user has a confirmed TOTP credential
+func (s *MFAAppService) Enabled(userID uint) (bool, error) {
+	cred, err := s.repo.FindTOTP(userID)
+	if err != nil {
+		if errors.Is(err, mfa.ErrTOTPNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return cred.IsEnabled(), nil
+}
+
+// Status returns the user's two-factor state
+func (s *MFAAppService) Status(ctx context.Context, userID uint) (*dto.MFAStatusResponse, error) {
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	enabled, err := s.Enabled(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.Required(u)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := s.repo.CountRecoveryCodes(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.MFAStatusResponse{TOTPEnabled: enabled, Required: required, RecoveryCodesRemaining: remaining}, nil
+}
+
+// SetupTOTP starts (or restarts) enrollment with a new secret.
+// The credential stays inactive until ConfirmTOTP succeeds.
+func (s *MFAAppService) SetupTOTP(ctx context.Context, userID uint) (*dto.TOTPSetupResponse, error) {
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	cred, err := s.repo.FindTOTP(u.ID)
+	switch {
+	case errors.Is(err, mfa.ErrTOTPNotFound):
+		cred = mfa.NewTOTPCredential(u.ID, "")
+	case err != nil:
+		return nil, err
+	case cred.IsEnabled():
+		return nil, errcode.ErrMFAEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	cred.Secret = secret
+	if err := s.repo.SaveTOTP(cred); err != nil {
+		return nil, err
+	}
+
+	uri := totp.URI(s.issuer, u.Username, secret)
+	qr, err := totp.QRCodePNG(uri, qrCodeSize)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TOTPSetupResponse{Secret: secret, URI: uri, QRCode: qr}, nil
+}
+
+// ConfirmTOTP activates the pending credential with a first code and returns
+// the initial recovery codes
+func (s *MFAAppService) ConfirmTOTP(userID uint, code string) (*dto.RecoveryCodesResponse, error) {
+	cred, err := s.repo.FindTOTP(userID)
+	if err != nil {
+		if errors.Is(err, mfa.ErrTOTPNotFound) {
+			return nil, errcode.ErrMFANotEnabled.WithMessage("totp setup has not been started")
+		}
+		return nil, err
+	}
+	if cred.IsEnabled() {
+		return nil, errcode.ErrMFAEnabled
+	}
+
+	now := time.Now()
+	step, ok := totp.Validate(cred.Secret, code, now)
+	if !ok {
+		return nil, errcode.ErrInvalidOTP
+	}
+	cred.Confirm(now, step)
+	if err := s.repo.SaveTOTP(cred); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(userID)
+}
+
+// Verify checks a TOTP code, or failing that a single-use recovery code
+func (s *MFAAppService) Verify(u *user.User, code string) error {
+	cred, err := s.repo.FindTOTP(u.ID)
+	if err != nil {
+		if errors.Is(err, mfa.ErrTOTPNotFound) {
+			return errcode.ErrMFANotEnabled
+		}
+		return err
+	}
+	if !cred.IsEnabled() {
+		return errcode.ErrMFANotEnabled
+	}
+
+	now := time.Now()
+	if step, ok := totp.Validate(cred.Secret, code, now); ok {
+		// Each time step is accepted once so an observed code cannot be replayed
+		fresh, err := s.repo.MarkStepUsed(u.ID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return errcode.ErrInvalidOTP
+		}
+		return nil
+	}
+
+	used, err := s.repo.UseRecoveryCode(u.ID, hashOpaqueToken(normalizeRecoveryCode(code)), now)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errcode.ErrInvalidOTP
+	}
+	return nil
+}
+
+// DisableTOTP removes the user's authenticator after checking a current code
+func (s *MFAAppService) DisableTOTP(ctx context.Context, userID uint, code string, client ClientInfo) error {
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	required, err := s.Required(u)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errcode.ErrMFARequiredByRole
+	}
+	if err := s.verifyThrottled(ctx, u, code, client); err != nil {
+		return err
+	}
+	return s.repo.DeleteTOTP(u.ID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a current code
+func (s *MFAAppService) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string, client ClientInfo) (*dto.RecoveryCodesResponse, error) {
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyThrottled(ctx, u, code, client); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(u.ID)
+}
+
+// verifyThrottled checks a code like Verify, within the login lockout: a
+// locked account is refused outright and wrong codes count as failures, so a
+// stolen session cannot guess its way to turning the second factor off
+func (s *MFAAppService) verifyThrottled(ctx context.Context, u *user.User, code string, client ClientInfo) error {
+	account, err := s.throttle.Account(u.TenantID, u.Username)
+	if err != nil {
+		return err
+	}
+	if err := s.throttle.Locked(ctx, account, client.IP); err != nil {
+		return err
+	}
+	if err := s.Verify(u, code); err != nil {
+		if errors.Is(err, errcode.ErrInvalidOTP) {
+			if lockErr := s.throttle.Fail(ctx, account, client.IP); lockErr != nil {
+				logger.Warnf("mfa settings locked username=%s ip=%s", u.Username, client.IP)
+				return lockErr
+			}
+		}
+		return err
+	}
+	s.throttle.Clear(ctx, account)
+	return nil
+}
+
+// Reset removes a user's second factor, e.g. when an administrator handles a lost device
+func (s *MFAAppService) Reset(userID uint) error {
+	return s.repo.DeleteTOTP(userID)
+}
+
+func (s *MFAAppService) findUser(ctx context.Context, id uint) (*user.User, error) {
+	u, err := s.users.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, errcode.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *MFAAppService) newRecoveryCodes(userID uint) (*dto.RecoveryCodesResponse, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	buf := make([]byte, 5)
+	for i := range codes {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(recoveryEncoding.EncodeToString(buf)) // 8 chars
+		codes[i] = raw[:4] + "-" + raw[4:]
+		hashes[i] = hashOpaqueToken(raw)
+	}
+	if err := s.repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return &dto.RecoveryCodesResponse{Codes: codes}, nil
+}
+
+// normalizeRecoveryCode accepts codes typed with or without the dash, in any case
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
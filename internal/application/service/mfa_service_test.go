@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/container"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/totp"
+)
+
+// enrollTOTP creates a user with an active authenticator and returns the
+// user's ID and TOTP secret
+func enrollTOTP(t *testing.T, c *container.Container, username string) (uint, string) {
+	t.Helper()
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+	u, err := c.UserService.Create(ctx, &dto.CreateUserRequest{Username: username, Password: testPassword})
+	if err != nil {
+		t.Fatal(err)
+	}
+	setup, err := c.MFAService.SetupTOTP(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("SetupTOTP: %v", err)
+	}
+	code, err := totp.Code(setup.Secret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.MFAService.ConfirmTOTP(u.ID, code); err != nil {
+		t.Fatalf("ConfirmTOTP: %v", err)
+	}
+	return u.ID, setup.Secret
+}
+
+func TestMFASettingsWrongCodesLockOut(t *testing.T) {
+	changes := map[string]func(c *container.Container, ctx context.Context, userID uint, code string) error{
+		"disable": func(c *container.Container, ctx context.Context, userID uint, code string) error {
+			return c.MFAService.DisableTOTP(ctx, userID, code, service.ClientInfo{IP: testClientIP})
+		},
+		"regenerate recovery codes": func(c *container.Container, ctx context.Context, userID uint, code string) error {
+			_, err := c.MFAService.RegenerateRecoveryCodes(ctx, userID, code, service.ClientInfo{IP: testClientIP})
+			return err
+		},
+	}
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			c := newTestContainer(t, nil)
+			userID, secret := enrollTOTP(t, c, "kate")
+			ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+
+			threshold := config.DefaultConfig().Security.Login.MaxAccountFailures
+			for i := 1; i < threshold; i++ {
+				requireCode(t, change(c, ctx, userID, "000000"), errcode.ErrInvalidOTP)
+			}
+			requireLocked(t, change(c, ctx, userID, "000000"))
+
+			// A locked account is refused before the code is looked at, so a
+			// right code neither passes nor gets spent
+			code, err := totp.Code(secret, totp.Step(time.Now())+1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requireLocked(t, change(c, ctx, userID, code))
+			if enabled, _ := c.MFAService.Enabled(userID); !enabled {
+				t.Fatal("the second factor was removed while locked")
+			}
+			_, err = c.AuthService.Login(context.Background(), &dto.LoginRequest{Username: "kate", Password: testPassword}, service.ClientInfo{IP: "192.0.2.51"})
+			requireLocked(t, err)
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/audit"
+	"go-ddd-scaffold/internal/domain/passkey"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/cache"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const (
+	passkeyChallengePrefix = "passkey:challenge:"
+
+	defaultPasskeyName = "Passkey"
+)
+
+// passkeyCeremony is what a begin step remembers for the finish step
+type passkeyCeremony struct {
+	UserID  uint                 `json:"user_id"` // registering user; 0 for a login
+	Session webauthn.SessionData `json:"session"`
+}
+
+// PasskeyAppService registers WebAuthn passkeys and signs users in with them.
+// Logins are discoverable: the browser offers the user's passkeys for the
+// site without a username being typed first.
+type PasskeyAppService struct {
+	webauthn *webauthn.WebAuthn
+	repo     passkey.Repository
+	users    user.Repository
+	auth     *AuthAppService
+	cache    cache.Cache
+}
+
+// NewPasskeyAppService creates a new application service; a nil relying
+// party disables passkeys
+func NewPasskeyAppService(rp *webauthn.WebAuthn, repo passkey.Repository, users user.Repository, auth *AuthAppService, c cache.Cache) *PasskeyAppService {
+	return &PasskeyAppService{webauthn: rp, repo: repo, users: users, auth: auth, cache: c}
+}
+
+// BeginRegistration starts registering a passkey for the user. Passkeys the
+// user already has are excluded so an authenticator is not registered twice.
+func (s *PasskeyAppService) BeginRegistration(ctx context.Context, userID uint) (*dto.PasskeyChallengeResponse, error) {
+	if s.webauthn == nil {
+		return nil, errcode.ErrPasskeyDisabled
+	}
+	account, err := s.account(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.user.IsActive() {
+		return nil, errcode.ErrAccountDisabled
+	}
+	if account.user.ServiceAccount {
+		return nil, errcode.ErrInvalidParams.WithMessage("service accounts cannot register passkeys")
+	}
+
+	creation, session, err := s.webauthn.BeginRegistration(account,
+		webauthn.WithExclusions(webauthn.Credentials(account.WebAuthnCredentials()).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s.begin(ctx, userID, session, creation)
+}
+
+// FinishRegistration verifies the new credential against the pending
+// registration and stores it
+func (s *PasskeyAppService) FinishRegistration(ctx context.Context, userID uint, req *dto.FinishPasskeyRegistrationRequest) (*dto.PasskeyResponse, error) {
+	if s.webauthn == nil {
+		return nil, errcode.ErrPasskeyDisabled
+	}
+	ceremony, err := s.take(ctx, req.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony == nil || ceremony.UserID != userID {
+		return nil, errcode.ErrPasskeyInvalid.WithMessage("challenge is invalid or expired")
+	}
+	account, err := s.account(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(req.Credential)
+	if err != nil {
+		return nil, errcode.ErrPasskeyInvalid.WithMessage("malformed credential")
+	}
+	credential, err := s.webauthn.CreateCredential(account, ceremony.Session, parsed)
+	if err != nil {
+		logger.Warnf("passkey registration rejected user_id=%d: %v", userID, err)
+		return nil, errcode.ErrPasskeyInvalid
+	}
+	// Credential IDs are unique per authenticator; a clash means it is already registered
+	if _, err := s.repo.FindByCredentialID(credential.ID); err == nil {
+		return nil, errcode.ErrPasskeyInvalid.WithMessage("passkey is already registered")
+	} else if !errors.Is(err, passkey.ErrPasskeyNotFound) {
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = defaultPasskeyName
+	}
+	p := passkey.NewPasskey(userID, name, credential.ID, credential.PublicKey)
+	p.AttestationType = credential.AttestationType
+	for _, t := range credential.Transport {
+		p.Transports = append(p.Transports, string(t))
+	}
+	p.AAGUID = credential.Authenticator.AAGUID
+	p.SignCount = credential.Authenticator.SignCount
+	p.BackupEligible = credential.Flags.BackupEligible
+	p.BackupState = credential.Flags.BackupState
+	if err := s.repo.Save(p); err != nil {
+		return nil, err
+	}
+	auditEvent(ctx, audit.ActionPasskeyRegister)
+	auditTarget(ctx, p.ID)
+	return dto.FromPasskey(p), nil
+}
+
+// BeginLogin starts a passwordless login
+func (s *PasskeyAppService) BeginLogin(ctx context.Context) (*dto.PasskeyChallengeResponse, error) {
+	if s.webauthn == nil {
+		return nil, errcode.ErrPasskeyDisabled
+	}
+	assertion, session, err := s.webauthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, err
+	}
+	return s.begin(ctx, 0, session, assertion)
+}
+
+// FinishLogin verifies the assertion and issues tokens for the passkey's
+// owner. A passkey that verified the user (PIN, biometrics) satisfies 2FA on
+// its own; otherwise a TOTP code is still asked for like after a password.
+func (s *PasskeyAppService) FinishLogin(ctx context.Context, req *dto.FinishPasskeyLoginRequest, client ClientInfo) (*dto.TokenResponse, error) {
+	tokens, err := s.finishLogin(ctx, req, client)
+	if err != nil {
+		auditEvent(ctx, audit.ActionLoginFailure)
+	}
+	return tokens, err
+}
+
+func (s *PasskeyAppService) finishLogin(ctx context.Context, req *dto.FinishPasskeyLoginRequest, client ClientInfo) (*dto.TokenResponse, error) {
+	if s.webauthn == nil {
+		return nil, errcode.ErrPasskeyDisabled
+	}
+	ceremony, err := s.take(ctx, req.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	if ceremony == nil || ceremony.UserID != 0 {
+		return nil, errcode.ErrPasskeyFailed.WithMessage("challenge is invalid or expired")
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(req.Credential)
+	if err != nil {
+		return nil, errcode.ErrPasskeyFailed.WithMessage("malformed credential")
+	}
+
+	// The credential is looked up before the tenant is known; its owner decides it
+	var p *passkey.Passkey
+	var account *passkeyUser
+	lookup := func(rawID, userHandle []byte) (webauthn.User, error) {
+		var err error
+		if p, err = s.repo.FindByCredentialID(rawID); err != nil {
+			return nil, err
+		}
+		if len(userHandle) != 8 || uint(binary.BigEndian.Uint64(userHandle)) != p.UserID {
+			return nil, errors.New("user handle does not match the credential owner")
+		}
+		account, err = s.account(tenant.WithAllTenants(ctx), p.UserID)
+		return account, err
+	}
+	_, credential, err := s.webauthn.ValidatePasskeyLogin(lookup, ceremony.Session, parsed)
+	if err != nil {
+		if account != nil {
+			auditUser(ctx, account.user)
+		}
+		logger.Warnf("passkey login rejected: %v", err)
+		return nil, errcode.ErrPasskeyFailed
+	}
+	u := account.user
+	auditUser(ctx, u)
+	if credential.Authenticator.CloneWarning {
+		logger.Warnf("passkey sign count went backwards, possible clone passkey_id=%d user_id=%d", p.ID, u.ID)
+		return nil, errcode.ErrPasskeyFailed.WithMessage("passkey may have been cloned")
+	}
+
+	p.RecordUse(time.Now(), credential.Authenticator.SignCount, credential.Flags.BackupState)
+	if err := s.repo.Save(p); err != nil {
+		return nil, err
+	}
+	if credential.Flags.UserVerified {
+		return s.auth.SignInMultiFactor(ctx, u, client)
+	}
+	return s.auth.SignIn(ctx, u, client)
+}
+
+// List returns the user's passkeys
+func (s *PasskeyAppService) List(ctx context.Context, userID uint) ([]*dto.PasskeyResponse, error) {
+	if _, err := s.findUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromPasskeyList(items), nil
+}
+
+// Rename renames one of the user's passkeys
+func (s *PasskeyAppService) Rename(ctx context.Context, userID, id uint, req *dto.RenamePasskeyRequest) (*dto.PasskeyResponse, error) {
+	p, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Rename(req.Name)
+	if err := s.repo.Save(p); err != nil {
+		return nil, err
+	}
+	return dto.FromPasskey(p), nil
+}
+
+// Delete removes one of the user's passkeys; it can no longer sign in
+func (s *PasskeyAppService) Delete(ctx context.Context, userID, id uint) error {
+	p, err := s.find(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	auditEvent(ctx, audit.ActionPasskeyDelete)
+	auditTarget(ctx, p.ID)
+	return s.repo.Delete(p.ID)
+}
+
+// begin parks the session data of a ceremony until its finish step, for as
+// long as the browser is told the ceremony may take
+func (s *PasskeyAppService) begin(ctx context.Context, userID uint, session *webauthn.SessionData, options any) (*dto.PasskeyChallengeResponse, error) {
+	id, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(passkeyCeremony{UserID: userID, Session: *session})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ctx, passkeyChallengePrefix+id, data, time.Until(session.Expires)); err != nil {
+		return nil, err
+	}
+	return &dto.PasskeyChallengeResponse{ChallengeID: id, Options: options}, nil
+}
+
+// take returns and forgets a pending ceremony in one step, so each challenge
+// is answered once even by concurrent requests; nil means it is unknown or
+// expired
+func (s *PasskeyAppService) take(ctx context.Context, id string) (*passkeyCeremony, error) {
+	data, err := s.cache.Take(ctx, passkeyChallengePrefix+id)
+	if err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ceremony passkeyCeremony
+	if err := json.Unmarshal(data, &ceremony); err != nil {
+		return nil, err
+	}
+	return &ceremony, nil
+}
+
+// find returns one of the user's passkeys; the user must belong to the caller's tenant
+func (s *PasskeyAppService) find(ctx context.Context, userID, id uint) (*passkey.Passkey, error) {
+	if _, err := s.findUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	p, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, passkey.ErrPasskeyNotFound) {
+			return nil, errcode.ErrPasskeyNotFound
+		}
+		return nil, err
+	}
+	if p.UserID != userID {
+		return nil, errcode.ErrPasskeyNotFound
+	}
+	return p, nil
+}
+
+// account loads a user with their passkeys
+func (s *PasskeyAppService) account(ctx context.Context, userID uint) (*passkeyUser, error) {
+	u, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	passkeys, err := s.repo.ListByUser(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &passkeyUser{user: u, passkeys: passkeys}, nil
+}
+
+func (s *PasskeyAppService) findUser(ctx context.Context, id uint) (*user.User, error) {
+	u, err := s.users.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, errcode.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// passkeyUser adapts a user and their passkeys to webauthn.User. The user
+// handle is the big-endian user ID: stable, unique across tenants and free of
+// personal data.
+type passkeyUser struct {
+	user     *user.User
+	passkeys []*passkey.Passkey
+}
+
+func (a *passkeyUser) WebAuthnID() []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(a.user.ID))
+}
+
+func (a *passkeyUser) WebAuthnName() string {
+	return a.user.Username
+}
+
+func (a *passkeyUser) WebAuthnDisplayName() string {
+	if a.user.Nickname != "" {
+		return a.user.Nickname
+	}
+	return a.user.Username
+}
+
+func (a *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, len(a.passkeys))
+	for i, p := range a.passkeys {
+		transports := make([]protocol.AuthenticatorTransport, len(p.Transports))
+		for j, t := range p.Transports {
+			transports[j] = protocol.AuthenticatorTransport(t)
+		}
+		credentials[i] = webauthn.Credential{
+			ID:              p.CredentialID,
+			PublicKey:       p.PublicKey,
+			AttestationType: p.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: p.BackupEligible,
+				BackupState:    p.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    p.AAGUID,
+				SignCount: p.SignCount,
+			},
+		}
+	}
+	return credentials
+}
@@ -0,0 +1,163 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/container"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/webauthntest"
+)
+
+const passkeyOrigin = "http://localhost:8080"
+
+// newPasskeyTest returns a container with the default relying party
+// (localhost), a fresh user and a software authenticator holding a passkey
+// registered for that user
+func newPasskeyTest(t *testing.T) (*container.Container, uint, *webauthntest.Authenticator) {
+	t.Helper()
+	c := newTestContainer(t, nil)
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+	u, err := c.UserService.Create(ctx, &dto.CreateUserRequest{Username: "erin", Password: "Zq8#vLm2!pT9x"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	authenticator := webauthntest.New(passkeyOrigin)
+	begin, err := c.PasskeyService.BeginRegistration(ctx, u.ID)
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+	credential, err := authenticator.Register(passkeyOptions(t, begin))
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	p, err := c.PasskeyService.FinishRegistration(ctx, u.ID, &dto.FinishPasskeyRegistrationRequest{
+		ChallengeID: begin.ChallengeID, Name: "Laptop", Credential: credential,
+	})
+	if err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+	if p.Name != "Laptop" {
+		t.Fatalf("registered %+v", p)
+	}
+	return c, u.ID, authenticator
+}
+
+// passkeyOptions encodes a challenge's options as the browser receives them
+func passkeyOptions(t *testing.T, challenge *dto.PasskeyChallengeResponse) []byte {
+	t.Helper()
+	data, err := json.Marshal(challenge.Options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// passkeyAssertion begins a login and lets the authenticator answer it
+func passkeyAssertion(t *testing.T, c *container.Container, authenticator *webauthntest.Authenticator) *dto.FinishPasskeyLoginRequest {
+	t.Helper()
+	begin, err := c.PasskeyService.BeginLogin(context.Background())
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	credential, err := authenticator.Login(passkeyOptions(t, begin))
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return &dto.FinishPasskeyLoginRequest{ChallengeID: begin.ChallengeID, Credential: credential}
+}
+
+func TestPasskeyRegistration(t *testing.T) {
+	c, userID, authenticator := newPasskeyTest(t)
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+
+	list, err := c.PasskeyService.List(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("user has %d passkeys, want 1", len(list))
+	}
+
+	// The registered authenticator is excluded from registering again
+	begin, err := c.PasskeyService.BeginRegistration(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authenticator.Register(passkeyOptions(t, begin)); !errors.Is(err, webauthntest.ErrCredentialExcluded) {
+		t.Fatalf("Register = %v, want ErrCredentialExcluded", err)
+	}
+
+	// A registration challenge belongs to the user it was issued to
+	other := webauthntest.New(passkeyOrigin)
+	credential, err := other.Register(passkeyOptions(t, begin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.PasskeyService.FinishRegistration(ctx, userID+1, &dto.FinishPasskeyRegistrationRequest{ChallengeID: begin.ChallengeID, Credential: credential})
+	requireCode(t, err, errcode.ErrPasskeyInvalid)
+}
+
+func TestPasskeyLogin(t *testing.T) {
+	c, userID, authenticator := newPasskeyTest(t)
+	ctx := context.Background()
+
+	resp, err := c.PasskeyService.FinishLogin(ctx, passkeyAssertion(t, c, authenticator), service.ClientInfo{})
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	claims, err := c.TokenService.Parse(ctx, resp.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != userID || claims.Restriction != "" {
+		t.Fatalf("signed in as user %d with restriction %q, want user %d unrestricted", claims.UserID, claims.Restriction, userID)
+	}
+}
+
+func TestPasskeyLoginChallengeIsTakenOnce(t *testing.T) {
+	c, _, authenticator := newPasskeyTest(t)
+	ctx := context.Background()
+
+	req := passkeyAssertion(t, c, authenticator)
+	var wg sync.WaitGroup
+	var signedIn atomic.Int32
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.PasskeyService.FinishLogin(ctx, req, service.ClientInfo{}); err == nil {
+				signedIn.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := signedIn.Load(); n != 1 {
+		t.Fatalf("assertion accepted %d times, want 1", n)
+	}
+}
+
+func TestPasskeyLoginRejectsClonedAuthenticator(t *testing.T) {
+	c, _, authenticator := newPasskeyTest(t)
+	ctx := context.Background()
+
+	if _, err := c.PasskeyService.FinishLogin(ctx, passkeyAssertion(t, c, authenticator), service.ClientInfo{}); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	// A copy of the key signs with a counter the server has already seen
+	authenticator.Credentials()[0].SignCount = 0
+	_, err := c.PasskeyService.FinishLogin(ctx, passkeyAssertion(t, c, authenticator), service.ClientInfo{})
+	requireCode(t, err, errcode.ErrPasskeyFailed)
+	if !strings.Contains(err.Error(), "cloned") {
+		t.Fatalf("error = %v, want the clone warning", err)
+	}
+}
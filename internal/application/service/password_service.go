@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+	"go-ddd-scaffold/pkg/password"
+)
+
+// PasswordService applies the password policy, hashing, reuse history and
+// token invalidation shared by logins, self-service changes and admin resets
+type PasswordService struct {
+	users        user.Repository
+	history      user.PasswordHistoryRepository
+	tokens       *TokenService
+	hasher       *password.Hasher
+	policy       *password.Policy
+	historyCount int
+
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+// NewPasswordService creates a new password service.
+// historyCount is how many previous passwords cannot be reused; 0 disables the check.
+func NewPasswordService(users user.Repository, history user.PasswordHistoryRepository, tokens *TokenService, hasher *password.Hasher, policy *password.Policy, historyCount int) *PasswordService {
+	return &PasswordService{users: users, history: history, tokens: tokens, hasher: hasher, policy: policy, historyCount: historyCount}
+}
+
+// Hash validates the password against the policy and hashes it. The
+// violated rules are returned as error data. An unreadable breached password
+// list is logged and does not block the password.
+func (s *PasswordService) Hash(ctx context.Context, pwd string) (string, error) {
+	verr, err := s.policy.Validate(pwd)
+	if err != nil {
+		logger.Warnf("password policy: %v", err)
+	}
+	if verr != nil {
+		return "", errcode.ErrPasswordPolicy.WithMessage(verr.Error()).WithData(verr)
+	}
+	hash, err := s.hasher.Hash(ctx, pwd)
+	if errors.Is(err, password.ErrTooLong) {
+		// bcrypt counts bytes, so multi-byte passwords can hit its limit
+		// within policy.MaxLength
+		return "", errcode.ErrPasswordTooLong
+	}
+	return hash, hashError(err)
+}
+
+// Verify reports whether pwd is the user's current password
+func (s *PasswordService) Verify(ctx context.Context, u *user.User, pwd string) (bool, error) {
+	ok, err := s.hasher.Verify(ctx, pwd, u.PasswordHash)
+	return ok, hashError(err)
+}
+
+// VerifyLogin is Verify for sign-ins: a matching password stored with an
+// outdated algorithm or parameters is rehashed and saved. A failed upgrade
+// is logged and does not fail the login.
+func (s *PasswordService) VerifyLogin(ctx context.Context, u *user.User, pwd string) (bool, error) {
+	ok, err := s.Verify(ctx, u, pwd)
+	if err != nil || !ok || !s.hasher.NeedsRehash(u.PasswordHash) {
+		return ok, err
+	}
+
+	hash, err := s.hasher.Hash(ctx, pwd)
+	if err == nil {
+		u.UpgradePasswordHash(hash)
+		err = s.users.Save(ctx, u)
+	}
+	if err != nil {
+		logger.Warnf("failed to upgrade password hash of user %d: %v", u.ID, err)
+	}
+	return true, nil
+}
+
+// VerifyDummy spends as much time as Verify against a throwaway hash, so
+// that unknown usernames cannot be told apart by response time
+func (s *PasswordService) VerifyDummy(ctx context.Context, pwd string) error {
+	s.dummyOnce.Do(func() { s.dummyHash, _ = s.hasher.Hash(ctx, "dummy-password") })
+	_, err := s.hasher.Verify(ctx, pwd, s.dummyHash)
+	return hashError(err)
+}
+
+// Remember records the user's current password in the history, e.g. after creating the user
+func (s *PasswordService) Remember(u *user.User) error {
+	if s.historyCount <= 0 {
+		return nil
+	}
+	return s.history.Add(u.ID, u.PasswordHash, s.historyCount)
+}
+
+// Change sets a password chosen by the user, refusing recently used ones.
+// It clears any forced change and revokes every token of the user.
+func (s *PasswordService) Change(ctx context.Context, u *user.User, newPwd string) error {
+	hash, err := s.Prepare(ctx, u, newPwd)
+	if err != nil {
+		return err
+	}
+	return s.Set(ctx, u, hash)
+}
+
+// Prepare checks a password chosen by the user against the policy and the
+// history and returns its hash, for callers that must do more before Set
+func (s *PasswordService) Prepare(ctx context.Context, u *user.User, newPwd string) (string, error) {
+	hash, err := s.Hash(ctx, newPwd)
+	if err != nil {
+		return "", err
+	}
+	reused, err := s.recentlyUsed(ctx, u, newPwd)
+	if err != nil {
+		return "", err
+	}
+	if reused {
+		return "", errcode.ErrPasswordReused
+	}
+	return hash, nil
+}
+
+// Set stores a hash from Prepare, clears any forced change and revokes every
+// token of the user
+func (s *PasswordService) Set(ctx context.Context, u *user.User, hash string) error {
+	u.ChangePassword(hash)
+	return s.apply(ctx, u)
+}
+
+// Reset sets a temporary password chosen by an administrator and forces the
+// user to change it at next login. It revokes every token of the user.
+func (s *PasswordService) Reset(ctx context.Context, u *user.User, tempPwd string) error {
+	hash, err := s.Hash(ctx, tempPwd)
+	if err != nil {
+		return err
+	}
+
+	u.ResetPassword(hash)
+	return s.apply(ctx, u)
+}
+
+// Forget removes the user's history, e.g. when the user is deleted
+func (s *PasswordService) Forget(userID uint) error {
+	return s.history.DeleteByUser(userID)
+}
+
+func (s *PasswordService) apply(ctx context.Context, u *user.User) error {
+	if err := s.users.Save(ctx, u); err != nil {
+		return err
+	}
+	if err := s.Remember(u); err != nil {
+		return err
+	}
+	return s.tokens.RevokeAll(ctx, u.ID)
+}
+
+// recentlyUsed reports whether pwd matches the current password or one of the
+// last historyCount passwords
+func (s *PasswordService) recentlyUsed(ctx context.Context, u *user.User, pwd string) (bool, error) {
+	if s.historyCount <= 0 {
+		return false, nil
+	}
+	if ok, err := s.Verify(ctx, u, pwd); err != nil || ok {
+		return ok, err
+	}
+	hashes, err := s.history.Recent(u.ID, s.historyCount)
+	if err != nil {
+		return false, err
+	}
+	for _, h := range hashes {
+		ok, err := s.hasher.Verify(ctx, pwd, h)
+		if err != nil || ok {
+			return ok, hashError(err)
+		}
+	}
+	return false, nil
+}
+
+// hashError reports a saturated hashing pool as a retryable busy error
+func hashError(err error) error {
+	if errors.Is(err, password.ErrBusy) {
+		return errcode.ErrServerBusy
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/password"
+)
+
+// slowHash is an argon2id hash with enough passes to keep a hashing slot
+// busy for a few hundred milliseconds
+const slowHash = "$argon2id$v=19$m=8,t=30000,p=1$AAAAAAAAAAAAAAAAAAAAAA$AAAAAAAAAAAAAAAAAAAAAA"
+
+func TestSaturatedHasherIsServerBusy(t *testing.T) {
+	hasher, err := password.NewHasher(password.Params{Algorithm: password.Bcrypt, BcryptCost: 4, MaxConcurrent: 1, QueueTimeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := service.NewPasswordService(nil, nil, nil, hasher, password.DefaultPolicy(), 0)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = hasher.Verify(context.Background(), "", slowHash)
+	}()
+	t.Cleanup(func() { <-done })
+	// Wait for the slow hash to take the only slot: from then on a caller that
+	// already gave up is refused instead of served
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for {
+		if _, err := hasher.Verify(canceled, "", "x"); err != nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err = svc.Verify(context.Background(), &user.User{PasswordHash: slowHash}, "")
+	requireCode(t, err, errcode.ErrServerBusy)
+	_, err = svc.Hash(context.Background(), "Zq8#vLm2!pT9x")
+	requireCode(t, err, errcode.ErrServerBusy)
+	if status := errcode.ErrServerBusy.GetHTTPStatus(); status != http.StatusServiceUnavailable {
+		t.Fatalf("busy hasher answers %d, want 503", status)
+	}
+}
+
+func TestHashReportsPolicyAndLengthErrors(t *testing.T) {
+	hasher, err := password.NewHasher(password.Params{Algorithm: password.Bcrypt, BcryptCost: 4, MaxConcurrent: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := service.NewPasswordService(nil, nil, nil, hasher, password.DefaultPolicy(), 0)
+
+	// Policy violations carry the violated rules
+	_, err = svc.Hash(context.Background(), "admin123")
+	requireCode(t, err, errcode.ErrPasswordPolicy)
+	var e *errcode.Error
+	errors.As(err, &e)
+	verr, ok := e.Data.(*password.ValidationError)
+	if !ok || len(verr.Violations) == 0 || verr.Violations[len(verr.Violations)-1].Rule != password.RuleBreached {
+		t.Fatalf("error data = %#v, want the breached rule", e.Data)
+	}
+	if e.Message != verr.Error() {
+		t.Fatalf("message %q, want the violations %q", e.Message, verr.Error())
+	}
+
+	// 25 characters pass max_length, but their 75 bytes exceed bcrypt's limit
+	_, err = svc.Hash(context.Background(), "密码安全很重要啊真的是这样没错我们一起来保护账号吧好")
+	requireCode(t, err, errcode.ErrPasswordTooLong)
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/rbac"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/cache"
+	"go-ddd-scaffold/pkg/errcode"
+)
+
+const (
+	rolePermCachePrefix = "rbac:role:"
+	rolePermCacheTTL    = 5 * time.Minute
+)
+
+// adminPermissions mark a role as administrative: its members can manage
+// other users or roles
+var adminPermissions = []string{"user:write", "user:impersonate", "rbac:write"}
+
+// RBACAppService orchestrates roles, permissions and permission checks
+type RBACAppService struct {
+	repo   rbac.Repository
+	users  user.Repository
+	tokens *TokenService
+	perms  *cache.Typed[[]string]
+}
+
+// NewRBACAppService creates a new application service
+func NewRBACAppService(repo rbac.Repository, users user.Repository, tokens *TokenService, c cache.Cache) *RBACAppService {
+	perms := cache.NewTyped[[]string](c, cache.TypedOptions{
+		Prefix:      rolePermCachePrefix,
+		TTL:         rolePermCacheTTL,
+		NegativeTTL: rolePermCacheTTL,
+		Jitter:      0.1,
+	})
+	return &RBACAppService{repo: repo, users: users, tokens: tokens, perms: perms}
+}
+
+// SyncPermissions seeds the wildcard permission, the built-in roles and every
+// permission declared by modules. Default grants apply only to newly created permissions.
+func (s *RBACAppService) SyncPermissions(groups ...[]rbac.Permission) error {
+	if _, err := s.repo.EnsurePermission(&rbac.Permission{Code: rbac.Wildcard, Description: "All permissions"}); err != nil {
+		return err
+	}
+	builtin := []*rbac.Role{
+		rbac.NewRole(rbac.RoleAdmin, "Administrator", "Full access", []string{rbac.Wildcard}),
+		rbac.NewRole(rbac.RoleUser, "User", "Default role for new accounts", nil),
+	}
+	for _, role := range builtin {
+		if _, err := s.repo.FindRoleByCode(role.Code); err != nil {
+			if !errors.Is(err, rbac.ErrRoleNotFound) {
+				return err
+			}
+			if err := s.repo.SaveRole(role); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, group := range groups {
+		for i := range group {
+			p := group[i]
+			created, err := s.repo.EnsurePermission(&p)
+			if err != nil {
+				return err
+			}
+			if !created {
+				continue
+			}
+			for _, role := range p.DefaultRoles {
+				if err := s.repo.GrantPermission(role, p.Code); err != nil && !errors.Is(err, rbac.ErrRoleNotFound) {
+					return err
+				}
+			}
+		}
+	}
+	return s.invalidate(context.Background())
+}
+
+// HasPermission reports whether the role grants the permission (cached per role)
+func (s *RBACAppService) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	granted, err := s.rolePermissions(ctx, role)
+	if err != nil {
+		return false, err
+	}
+	return rbac.Match(granted, permission), nil
+}
+
+// IsAdminRole reports whether the role is administrative: the built-in admin
+// role or one granting any of adminPermissions
+func (s *RBACAppService) IsAdminRole(ctx context.Context, role string) (bool, error) {
+	if role == rbac.RoleAdmin {
+		return true, nil
+	}
+	for _, permission := range adminPermissions {
+		ok, err := s.HasPermission(ctx, role, permission)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// Authorize reports whether the caller may use the permission: the role must
+// grant it and, for API keys, one of the key's scopes must cover it as well
+func (s *RBACAppService) Authorize(ctx context.Context, claims *Claims, permission string) (bool, error) {
+	ok, err := s.HasPermission(ctx, claims.Role, permission)
+	if err != nil || !ok {
+		return false, err
+	}
+	if claims.IsAPIKey() {
+		return rbac.Match(claims.Scopes, permission), nil
+	}
+	return true, nil
+}
+
+// ListRoles returns all roles
+func (s *RBACAppService) ListRoles() ([]*dto.RoleResponse, error) {
+	roles, err := s.repo.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromRoleList(roles), nil
+}
+
+// ListPermissions returns all permissions
+func (s *RBACAppService) ListPermissions() ([]*dto.PermissionResponse, error) {
+	perms, err := s.repo.ListPermissions()
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromPermissionList(perms), nil
+}
+
+// CreateRole creates a new role
+func (s *RBACAppService) CreateRole(ctx context.Context, req *dto.CreateRoleRequest) (*dto.RoleResponse, error) {
+	if _, err := s.repo.FindRoleByCode(req.Code); err == nil {
+		return nil, errcode.ErrRoleExists
+	} else if !errors.Is(err, rbac.ErrRoleNotFound) {
+		return nil, err
+	}
+	if err := s.validatePermissions(req.Permissions); err != nil {
+		return nil, err
+	}
+
+	role := rbac.NewRole(req.Code, req.Name, req.Description, req.Permissions)
+	role.SetRequireMFA(req.RequireMFA)
+	if err := s.repo.SaveRole(role); err != nil {
+		return nil, err
+	}
+	if err := s.invalidate(ctx); err != nil {
+		return nil, err
+	}
+	resp := dto.FromRole(role)
+	auditChange(ctx, role.ID, nil, resp)
+	return resp, nil
+}
+
+// UpdateRole updates a role's info and permission set
+func (s *RBACAppService) UpdateRole(ctx context.Context, id uint, req *dto.UpdateRoleRequest) (*dto.RoleResponse, error) {
+	role, err := s.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+	before := dto.FromRole(role)
+
+	name, desc := "", ""
+	if req.Name != nil {
+		name = *req.Name
+	}
+	if req.Description != nil {
+		desc = *req.Description
+	}
+	role.UpdateInfo(name, desc)
+	if req.RequireMFA != nil {
+		role.SetRequireMFA(*req.RequireMFA)
+	}
+
+	if req.Permissions != nil {
+		if err := s.validatePermissions(*req.Permissions); err != nil {
+			return nil, err
+		}
+		role.SetPermissions(*req.Permissions)
+	}
+
+	if err := s.repo.SaveRole(role); err != nil {
+		return nil, err
+	}
+	if err := s.invalidate(ctx); err != nil {
+		return nil, err
+	}
+	resp := dto.FromRole(role)
+	auditChange(ctx, role.ID, before, resp)
+	return resp, nil
+}
+
+// DeleteRole deletes a custom role that no user holds
+func (s *RBACAppService) DeleteRole(ctx context.Context, id uint) error {
+	role, err := s.findRole(id)
+	if err != nil {
+		return err
+	}
+	if role.IsBuiltin() {
+		return errcode.ErrInvalidParams.WithMessage("built-in roles cannot be deleted")
+	}
+	// Roles are shared by all tenants
+	count, err := s.users.CountByRole(tenant.WithAllTenants(ctx), user.Role(role.Code))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errcode.ErrRoleInUse
+	}
+
+	if err := s.repo.DeleteRole(id); err != nil {
+		return err
+	}
+	auditChange(ctx, id, dto.FromRole(role), nil)
+	return s.invalidate(ctx)
+}
+
+// AssignRole sets a user's role and invalidates their access tokens so the
+// new role takes effect on the next refresh
+func (s *RBACAppService) AssignRole(ctx context.Context, userID uint, req *dto.AssignRoleRequest) error {
+	if _, err := s.repo.FindRoleByCode(req.Role); err != nil {
+		if errors.Is(err, rbac.ErrRoleNotFound) {
+			return errcode.ErrRoleNotFound
+		}
+		return err
+	}
+
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return errcode.ErrAccountNotFound
+		}
+		return err
+	}
+	if u.SuperAdmin && !isSuperAdmin(ctx) {
+		return errcode.ErrSuperAdminRequired
+	}
+	if u.Role == user.Role(req.Role) {
+		return nil
+	}
+
+	before := dto.FromUser(u)
+	u.Role = user.Role(req.Role)
+	if err := s.users.Save(ctx, u); err != nil {
+		return err
+	}
+	auditChange(ctx, u.ID, before, dto.FromUser(u))
+	return s.tokens.RevokeAccessTokens(ctx, u.ID)
+}
+
+// SyncMappedRole applies a role mapped from the groups of an external
+// identity source. The source is the truth for the roles it maps; users
+// without a matching group keep whatever role was assigned locally, and
+// super-admins are never changed.
+func (s *RBACAppService) SyncMappedRole(ctx context.Context, u *user.User, role string) error {
+	if u.Role == user.Role(role) || u.SuperAdmin {
+		return nil
+	}
+	if err := s.CheckMappedRole(role); err != nil {
+		return err
+	}
+	// Goes through AssignRole so tokens carrying the old role are revoked
+	if err := s.AssignRole(ctx, u.ID, &dto.AssignRoleRequest{Role: role}); err != nil {
+		return err
+	}
+	u.Role = user.Role(role)
+	return nil
+}
+
+// CheckMappedRole fails if a configured role mapping refers to a role that
+// does not exist
+func (s *RBACAppService) CheckMappedRole(role string) error {
+	if _, err := s.repo.FindRoleByCode(role); err != nil {
+		if errors.Is(err, rbac.ErrRoleNotFound) {
+			return fmt.Errorf("role mapping refers to unknown role %q: %w", role, errcode.ErrRoleNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *RBACAppService) findRole(id uint) (*rbac.Role, error) {
+	role, err := s.repo.FindRoleByID(id)
+	if err != nil {
+		if errors.Is(err, rbac.ErrRoleNotFound) {
+			return nil, errcode.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *RBACAppService) validatePermissions(codes []string) error {
+	if len(codes) == 0 {
+		return nil
+	}
+	perms, err := s.repo.ListPermissions()
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(perms))
+	for _, p := range perms {
+		known[p.Code] = true
+	}
+	for _, code := range codes {
+		if !known[code] {
+			return errcode.ErrInvalidParams.WithMessage("unknown permission: " + code)
+		}
+	}
+	return nil
+}
+
+// rolePermissions loads the role's permissions, caching them briefly.
+// Concurrent misses for the same role share one repository lookup.
+func (s *RBACAppService) rolePermissions(ctx context.Context, code string) ([]string, error) {
+	granted, err := s.perms.GetOrLoad(ctx, code, 0, func(context.Context) ([]string, error) {
+		role, err := s.repo.FindRoleByCode(code)
+		if errors.Is(err, rbac.ErrRoleNotFound) {
+			return nil, cache.ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return role.Permissions, nil
+	})
+	if errors.Is(err, cache.ErrNotFound) {
+		// Unknown roles grant nothing; the miss is cached as well
+		return nil, nil
+	}
+	return granted, err
+}
+
+func (s *RBACAppService) invalidate(ctx context.Context) error {
+	return s.perms.DeleteAll(ctx)
+}
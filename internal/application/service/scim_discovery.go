@@ -0,0 +1,173 @@
+package service
+
+import (
+	"net/http"
+
+	"go-ddd-scaffold/pkg/scim"
+)
+
+// scimDiscoveryPath is the location of the discovery endpoints
+const scimDiscoveryPath = "/scim/v2/"
+
+// ServiceProviderConfig describes the supported SCIM features
+func (s *SCIMAppService) ServiceProviderConfig() *scim.ServiceProviderConfig {
+	return &scim.ServiceProviderConfig{
+		Schemas:        []string{scim.SchemaServiceProviderConfig},
+		Patch:          scim.Supported{Supported: true},
+		Bulk:           scim.BulkSupport{},
+		Filter:         scim.FilterSupport{Supported: true, MaxResults: s.cfg.MaxResults},
+		ChangePassword: scim.Supported{Supported: true},
+		Sort:           scim.Supported{},
+		ETag:           scim.Supported{},
+		AuthenticationSchemes: []scim.AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Bearer token",
+			Description: "The token configured as scim.token, sent in the Authorization header",
+			Primary:     true,
+		}},
+		Meta: &scim.Meta{ResourceType: "ServiceProviderConfig", Location: scimDiscoveryPath + "ServiceProviderConfig"},
+	}
+}
+
+// ResourceTypes lists the resource types
+func (s *SCIMAppService) ResourceTypes() []*scim.ResourceType {
+	return []*scim.ResourceType{scimUserResourceType(), scimGroupResourceType()}
+}
+
+// ResourceType returns a resource type by name
+func (s *SCIMAppService) ResourceType(name string) (*scim.ResourceType, error) {
+	for _, t := range s.ResourceTypes() {
+		if t.ID == name {
+			return t, nil
+		}
+	}
+	return nil, scim.NewError(http.StatusNotFound, "", "unknown resource type "+name)
+}
+
+// Schemas lists the schemas of the resources
+func (s *SCIMAppService) Schemas() []*scim.Schema {
+	return []*scim.Schema{scimUserSchema(), scimGroupSchema()}
+}
+
+// Schema returns a schema by URN
+func (s *SCIMAppService) Schema(id string) (*scim.Schema, error) {
+	for _, schema := range s.Schemas() {
+		if schema.ID == id {
+			return schema, nil
+		}
+	}
+	return nil, scim.NewError(http.StatusNotFound, "", "unknown schema "+id)
+}
+
+func scimUserResourceType() *scim.ResourceType {
+	return &scim.ResourceType{
+		Schemas:     []string{scim.SchemaResourceType},
+		ID:          "User",
+		Name:        "User",
+		Endpoint:    "/Users",
+		Description: "User accounts of the provisioned tenant",
+		Schema:      scim.SchemaUser,
+		Meta:        &scim.Meta{ResourceType: "ResourceType", Location: scimDiscoveryPath + "ResourceTypes/User"},
+	}
+}
+
+func scimGroupResourceType() *scim.ResourceType {
+	return &scim.ResourceType{
+		Schemas:     []string{scim.SchemaResourceType},
+		ID:          "Group",
+		Name:        "Group",
+		Endpoint:    "/Groups",
+		Description: "Roles; a user is a member of exactly one",
+		Schema:      scim.SchemaGroup,
+		Meta:        &scim.Meta{ResourceType: "ResourceType", Location: scimDiscoveryPath + "ResourceTypes/Group"},
+	}
+}
+
+// scimUserSchema lists the User attributes that are stored; others are ignored
+func scimUserSchema() *scim.Schema {
+	return &scim.Schema{
+		Schemas:     []string{scim.SchemaSchema},
+		ID:          scim.SchemaUser,
+		Name:        "User",
+		Description: "User Account",
+		Attributes: []scim.Attribute{
+			scimAttribute("userName", "string", "Unique identifier of the user within the tenant", true, "readWrite", "server"),
+			{
+				Name: "name", Type: "complex", Description: "The user's name; stored as the nickname",
+				Mutability: "readWrite", Returned: "default", Uniqueness: "none",
+				SubAttributes: []scim.Attribute{
+					scimAttribute("formatted", "string", "Full name", false, "readWrite", "none"),
+					scimAttribute("givenName", "string", "Joined with familyName when formatted is absent; not returned", false, "writeOnly", "none"),
+					scimAttribute("familyName", "string", "Joined with givenName when formatted is absent; not returned", false, "writeOnly", "none"),
+				},
+			},
+			scimAttribute("displayName", "string", "Name shown in the application; stored as the nickname", false, "readWrite", "none"),
+			{
+				Name: "emails", Type: "complex", MultiValued: true, Description: "Only the primary address is stored and is considered verified",
+				Mutability: "readWrite", Returned: "default", Uniqueness: "none",
+				SubAttributes: []scim.Attribute{
+					scimAttribute("value", "string", "Email address", false, "readWrite", "none"),
+					scimAttribute("type", "string", "Always work when returned", false, "readWrite", "none"),
+					{Name: "primary", Type: "boolean", Mutability: "readWrite", Returned: "default", Uniqueness: "none"},
+				},
+			},
+			{
+				Name: "active", Type: "boolean", Description: "false disables the account and revokes its tokens",
+				Mutability: "readWrite", Returned: "default", Uniqueness: "none",
+			},
+			{
+				Name: "password", Type: "string", Description: "Checked against the password policy",
+				Mutability: "writeOnly", Returned: "never", Uniqueness: "none",
+			},
+			{
+				Name: "groups", Type: "complex", MultiValued: true, Description: "The user's role; change it through /Groups",
+				Mutability: "readOnly", Returned: "default", Uniqueness: "none",
+				SubAttributes: []scim.Attribute{
+					scimAttribute("value", "string", "Group id", false, "readOnly", "none"),
+					{Name: "$ref", Type: "reference", ReferenceType: []string{"Group"}, Mutability: "readOnly", Returned: "default", Uniqueness: "none"},
+					scimAttribute("display", "string", "Role code", false, "readOnly", "none"),
+				},
+			},
+		},
+		Meta: &scim.Meta{ResourceType: "Schema", Location: scimDiscoveryPath + "Schemas/" + scim.SchemaUser},
+	}
+}
+
+func scimGroupSchema() *scim.Schema {
+	return &scim.Schema{
+		Schemas:     []string{scim.SchemaSchema},
+		ID:          scim.SchemaGroup,
+		Name:        "Group",
+		Description: "Role",
+		Attributes: []scim.Attribute{
+			scimAttribute("displayName", "string", "Role code: letters and digits; cannot change", true, "readWrite", "server"),
+			{
+				Name: "members", Type: "complex", MultiValued: true, Description: "Users of the provisioned tenant holding the role",
+				Mutability: "readWrite", Returned: "default", Uniqueness: "none",
+				SubAttributes: []scim.Attribute{
+					scimAttribute("value", "string", "User id", false, "immutable", "none"),
+					{Name: "$ref", Type: "reference", ReferenceType: []string{"User"}, Mutability: "immutable", Returned: "default", Uniqueness: "none"},
+					scimAttribute("display", "string", "Username", false, "readOnly", "none"),
+				},
+			},
+		},
+		Meta: &scim.Meta{ResourceType: "Schema", Location: scimDiscoveryPath + "Schemas/" + scim.SchemaGroup},
+	}
+}
+
+// scimAttribute describes a single-valued, case-insensitive string attribute
+func scimAttribute(name, typ, description string, required bool, mutability, uniqueness string) scim.Attribute {
+	returned := "default"
+	if mutability == "writeOnly" {
+		returned = "never"
+	}
+	return scim.Attribute{
+		Name:        name,
+		Type:        typ,
+		Description: description,
+		Required:    required,
+		Mutability:  mutability,
+		Returned:    returned,
+		Uniqueness:  uniqueness,
+	}
+}
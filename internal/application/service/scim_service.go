@@ -0,0 +1,1215 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/rbac"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/scim"
+)
+
+const (
+	// scimProvider names the identity links that hold the externalId of
+	// provisioned users
+	scimProvider = "scim"
+	// scimActor is the audit actor name of provisioning requests
+	scimActor = "scim"
+)
+
+// SCIMAppService serves SCIM 2.0 provisioning for a central identity
+// provider. Users are the users of one tenant; groups are roles, and since a
+// user holds exactly one role, adding a user to a group moves them out of
+// their previous one. Users removed from a group fall back to the default
+// role. Deprovisioning disables accounts instead of deleting them.
+//
+// Roles are shared by every tenant, so the identity provider cannot grant or
+// revoke administrative roles, touch the built-in roles other than the
+// default one, or create and delete roles unless scim.manage_roles allows it.
+type SCIMAppService struct {
+	cfg        *config.SCIMConfig
+	tokenHash  [32]byte
+	users      user.Repository
+	identities user.IdentityRepository
+	roles      rbac.Repository
+	tenants    tenant.Repository
+	passwords  *PasswordService
+	tokens     *TokenService
+	rbac       *RBACAppService
+}
+
+// NewSCIMAppService creates a new application service
+func NewSCIMAppService(cfg *config.SCIMConfig, users user.Repository, identities user.IdentityRepository, roles rbac.Repository, tenants tenant.Repository, passwords *PasswordService, tokens *TokenService, rbacSvc *RBACAppService) *SCIMAppService {
+	return &SCIMAppService{
+		cfg:        cfg,
+		tokenHash:  sha256.Sum256([]byte(cfg.Token)),
+		users:      users,
+		identities: identities,
+		roles:      roles,
+		tenants:    tenants,
+		passwords:  passwords,
+		tokens:     tokens,
+		rbac:       rbacSvc,
+	}
+}
+
+// scimUserState is the writable part of a provisioned user
+type scimUserState struct {
+	userName   string
+	nickname   string
+	email      string
+	externalID string
+	active     bool
+	password   string // empty keeps the current password
+}
+
+// Authenticate checks the provisioning client's bearer token and returns ctx
+// scoped to the provisioned tenant
+func (s *SCIMAppService) Authenticate(ctx context.Context, token string) (context.Context, error) {
+	if !s.cfg.Enabled {
+		return nil, errcode.ErrSCIMDisabled
+	}
+	sum := sha256.Sum256([]byte(token))
+	if subtle.ConstantTimeCompare(sum[:], s.tokenHash[:]) != 1 {
+		return nil, errcode.ErrInvalidToken
+	}
+
+	t, err := s.tenants.FindByCode(s.cfg.Tenant)
+	if err != nil {
+		if errors.Is(err, tenant.ErrTenantNotFound) {
+			return nil, fmt.Errorf("scim.tenant %q does not exist", s.cfg.Tenant)
+		}
+		return nil, err
+	}
+	ctx = tenant.WithTenant(ctx, t.ID)
+	auditActor(ctx, t.ID, 0, scimActor)
+	return ctx, nil
+}
+
+// ListUsers returns a page of the users matching the filter
+func (s *SCIMAppService) ListUsers(ctx context.Context, q *dto.SCIMListQuery) (*scim.ListResponse, error) {
+	offset, limit := s.page(q)
+	criteria, matchable, err := s.userCriteria(ctx, q.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if !matchable {
+		return scim.NewListResponse([]*dto.SCIMUser{}, 0, 0, offset+1), nil
+	}
+
+	users, total, err := s.users.Search(ctx, criteria, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := s.userResources(users)
+	if err != nil {
+		return nil, err
+	}
+	return scim.NewListResponse(resources, len(resources), total, offset+1), nil
+}
+
+// GetUser returns a user
+func (s *SCIMAppService) GetUser(ctx context.Context, id string) (*dto.SCIMUser, error) {
+	u, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.userResource(u)
+}
+
+// CreateUser provisions a user with the default role. Without a password
+// the user signs in through SSO, LDAP or a passkey only.
+func (s *SCIMAppService) CreateUser(ctx context.Context, req *dto.SCIMUser) (*dto.SCIMUser, error) {
+	st := stateFromSCIMUser(req)
+	if err := validateSCIMUser(st); err != nil {
+		return nil, err
+	}
+	if _, err := s.users.FindByUsername(ctx, st.userName); err == nil {
+		return nil, errcode.ErrAccountExists
+	} else if !errors.Is(err, user.ErrUserNotFound) {
+		return nil, err
+	}
+	if err := s.checkExternalID(st.externalID, 0); err != nil {
+		return nil, err
+	}
+	if _, err := s.defaultRole(ctx); err != nil {
+		return nil, err
+	}
+
+	hash := ""
+	if st.password != "" {
+		var err error
+		// Hash applies the password policy
+		if hash, err = s.passwords.Hash(ctx, st.password); err != nil {
+			return nil, err
+		}
+	}
+	u := user.NewUser(st.userName, hash, user.Role(s.cfg.DefaultRole))
+	setSCIMProfile(u, st.nickname, st.email)
+	if !st.active {
+		u.Disable()
+	}
+	if err := s.users.Save(ctx, u); err != nil {
+		return nil, err
+	}
+	if hash != "" {
+		if err := s.passwords.Remember(u); err != nil {
+			return nil, err
+		}
+	}
+	if st.externalID != "" {
+		if err := s.identities.Save(user.NewIdentity(u.ID, scimProvider, st.externalID)); err != nil {
+			return nil, err
+		}
+	}
+
+	auditChange(ctx, u.ID, nil, dto.FromUser(u))
+	return s.userResource(u)
+}
+
+// ReplaceUser replaces a user's attributes (PUT)
+func (s *SCIMAppService) ReplaceUser(ctx context.Context, id string, req *dto.SCIMUser) (*dto.SCIMUser, error) {
+	u, err := s.findManagedUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.saveUser(ctx, u, stateFromSCIMUser(req))
+}
+
+// PatchUser applies PATCH operations to a user. Attributes that are not
+// stored, such as addresses or extension attributes, are ignored.
+func (s *SCIMAppService) PatchUser(ctx context.Context, id string, req *scim.PatchRequest) (*dto.SCIMUser, error) {
+	u, err := s.findManagedUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	link, err := s.externalLink(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	st := &scimUserState{userName: u.Username, nickname: u.Nickname, email: u.Email, active: u.IsActive()}
+	if link != nil {
+		st.externalID = link.Subject
+	}
+
+	for _, op := range req.Operations {
+		err := forEachPatchTarget(op, func(kind string, path *scim.Path, value json.RawMessage) error {
+			return patchSCIMUser(st, kind, path, value)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.saveUser(ctx, u, st)
+}
+
+// DeleteUser deprovisions a user: the account is disabled and all of its
+// tokens are revoked, but kept so an administrator can restore or delete it
+func (s *SCIMAppService) DeleteUser(ctx context.Context, id string) error {
+	u, err := s.findManagedUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if u.IsActive() {
+		before := dto.FromUser(u)
+		u.Disable()
+		if err := s.users.Save(ctx, u); err != nil {
+			return err
+		}
+		auditChange(ctx, u.ID, before, dto.FromUser(u))
+	}
+	return s.tokens.RevokeAll(ctx, u.ID)
+}
+
+// ListGroups returns a page of the groups matching the filter
+func (s *SCIMAppService) ListGroups(ctx context.Context, q *dto.SCIMListQuery) (*scim.ListResponse, error) {
+	roles, err := s.roles.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	if q.Filter != "" {
+		if roles, err = s.filterRoles(ctx, roles, q.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	offset, limit := s.page(q)
+	total := len(roles)
+	roles = roles[min(offset, total):min(offset+limit, total)]
+	resources := make([]*dto.SCIMGroup, len(roles))
+	for i, r := range roles {
+		if resources[i], err = s.groupResource(ctx, r, !excludesMembers(q.ExcludedAttributes)); err != nil {
+			return nil, err
+		}
+	}
+	return scim.NewListResponse(resources, len(resources), int64(total), offset+1), nil
+}
+
+// GetGroup returns a group with the members of the provisioned tenant
+func (s *SCIMAppService) GetGroup(ctx context.Context, id string, excludedAttributes string) (*dto.SCIMGroup, error) {
+	role, err := s.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.groupResource(ctx, role, !excludesMembers(excludedAttributes))
+}
+
+// CreateGroup creates a role without permissions, named by the display name,
+// and assigns it to the members. A super-admin grants its permissions.
+// Requires scim.manage_roles.
+func (s *SCIMAppService) CreateGroup(ctx context.Context, req *dto.SCIMGroup) (*dto.SCIMGroup, error) {
+	if !s.cfg.ManageRoles {
+		return nil, errcode.ErrPermissionDenied.WithMessage("roles are created in the admin API; set scim.manage_roles to let the identity provider create groups")
+	}
+	if !validRoleCode(req.DisplayName) {
+		return nil, scim.BadRequest(scim.ErrInvalidValue, "displayName is the role code: 1-50 letters and digits")
+	}
+	members, err := memberIDs(req.Members)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.rbac.CreateRole(ctx, &dto.CreateRoleRequest{Code: req.DisplayName, Name: req.DisplayName}); err != nil {
+		return nil, err
+	}
+	role, err := s.roles.FindRoleByCode(req.DisplayName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setMembers(ctx, role, members); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.groupResource(ctx, role, true)
+	if err != nil {
+		return nil, err
+	}
+	auditChange(ctx, role.ID, nil, resp)
+	return resp, nil
+}
+
+// ReplaceGroup replaces a group's members (PUT); the display name cannot change
+func (s *SCIMAppService) ReplaceGroup(ctx context.Context, id string, req *dto.SCIMGroup) (*dto.SCIMGroup, error) {
+	role, err := s.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.DisplayName != "" && req.DisplayName != role.Code {
+		return nil, scim.BadRequest(scim.ErrMutability, "displayName is the role code and cannot change")
+	}
+	members, err := memberIDs(req.Members)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateGroup(ctx, role, members)
+}
+
+// PatchGroup applies PATCH operations to a group's members
+func (s *SCIMAppService) PatchGroup(ctx context.Context, id string, req *scim.PatchRequest) (*dto.SCIMGroup, error) {
+	role, err := s.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.members(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[uint]bool, len(current))
+	for _, u := range current {
+		members[u.ID] = true
+	}
+
+	for _, op := range req.Operations {
+		err := forEachPatchTarget(op, func(kind string, path *scim.Path, value json.RawMessage) error {
+			return patchSCIMGroup(role, members, kind, path, value)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]uint, 0, len(members))
+	for id := range members {
+		ids = append(ids, id)
+	}
+	return s.updateGroup(ctx, role, ids)
+}
+
+// DeleteGroup moves the group's members in the provisioned tenant to the
+// default role, then deletes the role unless other tenants still use it.
+// Requires scim.manage_roles.
+func (s *SCIMAppService) DeleteGroup(ctx context.Context, id string) error {
+	if !s.cfg.ManageRoles {
+		return errcode.ErrPermissionDenied.WithMessage("roles are deleted in the admin API; set scim.manage_roles to let the identity provider delete groups")
+	}
+	role, err := s.findRole(id)
+	if err != nil {
+		return err
+	}
+	before, err := s.groupResource(ctx, role, true)
+	if err != nil {
+		return err
+	}
+	if role.Code == s.cfg.DefaultRole {
+		return scim.BadRequest(scim.ErrMutability, "the group of the default role cannot be deleted")
+	}
+	// Refuses built-in and administrative roles
+	if err := s.setMembers(ctx, role, nil); err != nil {
+		return err
+	}
+
+	count, err := s.users.CountByRole(tenant.WithAllTenants(ctx), user.Role(role.Code))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		if err := s.rbac.DeleteRole(ctx, role.ID); err != nil {
+			return err
+		}
+	}
+	auditChange(ctx, role.ID, before, nil)
+	return nil
+}
+
+func (s *SCIMAppService) updateGroup(ctx context.Context, role *rbac.Role, members []uint) (*dto.SCIMGroup, error) {
+	before, err := s.groupResource(ctx, role, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setMembers(ctx, role, members); err != nil {
+		return nil, err
+	}
+	after, err := s.groupResource(ctx, role, true)
+	if err != nil {
+		return nil, err
+	}
+	auditChange(ctx, role.ID, before, after)
+	return after, nil
+}
+
+// setMembers assigns the role to exactly the given users of the tenant.
+// Users leaving the group get the default role; users joining it leave their
+// previous group. Access tokens of every moved user are revoked.
+func (s *SCIMAppService) setMembers(ctx context.Context, role *rbac.Role, ids []uint) error {
+	if err := s.checkGroup(ctx, role); err != nil {
+		return err
+	}
+	current, err := s.members(ctx, role)
+	if err != nil {
+		return err
+	}
+	want := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+
+	var moved []*user.User
+	for _, u := range current {
+		if !want[u.ID] {
+			if role.Code == s.cfg.DefaultRole {
+				return scim.BadRequest(scim.ErrMutability, "members cannot be removed from the group of the default role; add them to another group instead")
+			}
+			moved = append(moved, u)
+		}
+		delete(want, u.ID)
+	}
+	var joined []*user.User
+	for id := range want {
+		u, err := s.users.FindByID(ctx, id)
+		if errors.Is(err, user.ErrUserNotFound) {
+			return scim.BadRequest(scim.ErrInvalidValue, fmt.Sprintf("member %d does not exist", id))
+		} else if err != nil {
+			return err
+		}
+		joined = append(joined, u)
+	}
+	// Checked up front so a refused member leaves the group unchanged
+	for _, u := range append(moved, joined...) {
+		if u.SuperAdmin {
+			return errcode.ErrSuperAdminRequired
+		}
+	}
+	for _, u := range joined {
+		admin, err := s.rbac.IsAdminRole(ctx, string(u.Role))
+		if err != nil {
+			return err
+		}
+		if admin {
+			return errcode.ErrPermissionDenied.WithMessage(fmt.Sprintf("member %d holds an administrative role, which cannot be changed through SCIM", u.ID))
+		}
+	}
+	if len(moved) > 0 {
+		if _, err := s.defaultRole(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, u := range moved {
+		if err := s.setRole(ctx, u, s.cfg.DefaultRole); err != nil {
+			return err
+		}
+	}
+	for _, u := range joined {
+		if err := s.setRole(ctx, u, role.Code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkGroup fails unless the identity provider may change the group's
+// members: administrative roles are granted in the admin API only, and of the
+// built-in roles only the default one takes members
+func (s *SCIMAppService) checkGroup(ctx context.Context, role *rbac.Role) error {
+	admin, err := s.rbac.IsAdminRole(ctx, role.Code)
+	if err != nil {
+		return err
+	}
+	if admin {
+		return errcode.ErrPermissionDenied.WithMessage("administrative roles cannot be managed through SCIM")
+	}
+	if role.IsBuiltin() && role.Code != s.cfg.DefaultRole {
+		return errcode.ErrPermissionDenied.WithMessage("built-in roles cannot be managed through SCIM")
+	}
+	return nil
+}
+
+// defaultRole returns the role of new users and of users leaving their group,
+// which must not be administrative
+func (s *SCIMAppService) defaultRole(ctx context.Context) (*rbac.Role, error) {
+	role, err := s.roles.FindRoleByCode(s.cfg.DefaultRole)
+	if err != nil {
+		return nil, fmt.Errorf("scim.default_role %q: %w", s.cfg.DefaultRole, err)
+	}
+	if err := s.checkGroup(ctx, role); err != nil {
+		return nil, fmt.Errorf("scim.default_role %q: %w", s.cfg.DefaultRole, err)
+	}
+	return role, nil
+}
+
+// setRole changes the user's role and revokes their access tokens, like
+// RBACAppService.AssignRole without recording an audit change per user
+func (s *SCIMAppService) setRole(ctx context.Context, u *user.User, role string) error {
+	if u.Role == user.Role(role) {
+		return nil
+	}
+	u.Role = user.Role(role)
+	if err := s.users.Save(ctx, u); err != nil {
+		return err
+	}
+	return s.tokens.RevokeAccessTokens(ctx, u.ID)
+}
+
+// saveUser applies the new state to the user. A new password must pass the
+// password policy and history like one the user chose. Deactivation revokes
+// every token of the user, and so does a new password.
+func (s *SCIMAppService) saveUser(ctx context.Context, u *user.User, st *scimUserState) (*dto.SCIMUser, error) {
+	if err := validateSCIMUser(st); err != nil {
+		return nil, err
+	}
+	if st.userName != u.Username {
+		if other, err := s.users.FindByUsername(ctx, st.userName); err == nil && other.ID != u.ID {
+			return nil, errcode.ErrAccountExists
+		} else if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+	}
+	if err := s.checkExternalID(st.externalID, u.ID); err != nil {
+		return nil, err
+	}
+	link, err := s.externalLink(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	hash := ""
+	if st.password != "" {
+		if hash, err = s.passwords.Prepare(ctx, u, st.password); err != nil {
+			return nil, err
+		}
+	}
+
+	before := dto.FromUser(u)
+	deactivated := u.IsActive() && !st.active
+	u.Username = st.userName
+	setSCIMProfile(u, st.nickname, st.email)
+	if st.active {
+		u.Enable()
+	} else {
+		u.Disable()
+	}
+	if hash != "" {
+		// Saves the user and revokes every token
+		err = s.passwords.Set(ctx, u, hash)
+	} else {
+		err = s.users.Save(ctx, u)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if link != nil && link.Subject != st.externalID {
+		if err := s.identities.Delete(link.ID); err != nil {
+			return nil, err
+		}
+	}
+	if st.externalID != "" && (link == nil || link.Subject != st.externalID) {
+		if err := s.identities.Save(user.NewIdentity(u.ID, scimProvider, st.externalID)); err != nil {
+			return nil, err
+		}
+	}
+	if deactivated && hash == "" {
+		if err := s.tokens.RevokeAll(ctx, u.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	auditChange(ctx, u.ID, before, dto.FromUser(u))
+	return s.userResource(u)
+}
+
+// checkExternalID fails if another user than userID is linked to externalID
+func (s *SCIMAppService) checkExternalID(externalID string, userID uint) error {
+	if externalID == "" {
+		return nil
+	}
+	identity, err := s.identities.Find(scimProvider, externalID)
+	switch {
+	case err == nil && identity.UserID != userID:
+		return errcode.ErrAccountExists.WithMessage("externalId is already in use")
+	case err != nil && !errors.Is(err, user.ErrIdentityNotFound):
+		return err
+	}
+	return nil
+}
+
+// externalLink returns the user's externalId link, or nil
+func (s *SCIMAppService) externalLink(userID uint) (*user.Identity, error) {
+	links, err := s.identities.ListByUsers(scimProvider, []uint{userID})
+	if err != nil || len(links) == 0 {
+		return nil, err
+	}
+	return links[0], nil
+}
+
+func (s *SCIMAppService) userResource(u *user.User) (*dto.SCIMUser, error) {
+	resources, err := s.userResources([]*user.User{u})
+	if err != nil {
+		return nil, err
+	}
+	return resources[0], nil
+}
+
+// userResources converts users, loading their externalIds and roles
+func (s *SCIMAppService) userResources(users []*user.User) ([]*dto.SCIMUser, error) {
+	ids := make([]uint, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	links, err := s.identities.ListByUsers(scimProvider, ids)
+	if err != nil {
+		return nil, err
+	}
+	externalIDs := make(map[uint]string, len(links))
+	for _, l := range links {
+		externalIDs[l.UserID] = l.Subject
+	}
+	roles, err := s.roles.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]*rbac.Role, len(roles))
+	for _, r := range roles {
+		byCode[r.Code] = r
+	}
+
+	result := make([]*dto.SCIMUser, len(users))
+	for i, u := range users {
+		result[i] = dto.FromUserSCIM(u, externalIDs[u.ID], byCode[string(u.Role)])
+	}
+	return result, nil
+}
+
+func (s *SCIMAppService) groupResource(ctx context.Context, role *rbac.Role, withMembers bool) (*dto.SCIMGroup, error) {
+	if !withMembers {
+		return dto.FromRoleSCIM(role, nil), nil
+	}
+	members, err := s.members(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromRoleSCIM(role, members), nil
+}
+
+// members returns the tenant's users holding the role
+func (s *SCIMAppService) members(ctx context.Context, role *rbac.Role) ([]*user.User, error) {
+	members, _, err := s.users.Search(ctx, []user.Criterion{{Field: user.FieldRole, Op: user.OpEq, Value: role.Code}}, 0, -1)
+	return members, err
+}
+
+func (s *SCIMAppService) findUser(ctx context.Context, id string) (*user.User, error) {
+	n, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, errcode.ErrAccountNotFound
+	}
+	u, err := s.users.FindByID(ctx, uint(n))
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, errcode.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// findManagedUser loads a user the identity provider may change; like tenant
+// administrators, it cannot change super-admins, and like group membership,
+// administrators are managed in the admin API only
+func (s *SCIMAppService) findManagedUser(ctx context.Context, id string) (*user.User, error) {
+	u, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.SuperAdmin {
+		return nil, errcode.ErrSuperAdminRequired
+	}
+	admin, err := s.rbac.IsAdminRole(ctx, string(u.Role))
+	if err != nil {
+		return nil, err
+	}
+	if admin {
+		return nil, errcode.ErrPermissionDenied.WithMessage("users holding an administrative role cannot be changed through SCIM")
+	}
+	return u, nil
+}
+
+func (s *SCIMAppService) findRole(id string) (*rbac.Role, error) {
+	n, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, errcode.ErrRoleNotFound
+	}
+	role, err := s.roles.FindRoleByID(uint(n))
+	if err != nil {
+		if errors.Is(err, rbac.ErrRoleNotFound) {
+			return nil, errcode.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+// page converts the 1-based startIndex and count to an offset and a limit
+// capped at max_results
+func (s *SCIMAppService) page(q *dto.SCIMListQuery) (offset, limit int) {
+	limit = s.cfg.MaxResults
+	if q.Count != nil && *q.Count < limit {
+		limit = max(*q.Count, 0)
+	}
+	if q.StartIndex > 1 {
+		offset = q.StartIndex - 1
+	}
+	return offset, limit
+}
+
+// userCriteria translates a filter into search criteria. Comparisons joined
+// by "and" are supported; matchable is false when the filter names an
+// externalId or group that does not exist, so nothing can match.
+func (s *SCIMAppService) userCriteria(ctx context.Context, filter string) (criteria []user.Criterion, matchable bool, err error) {
+	if filter == "" {
+		return nil, true, nil
+	}
+	f, err := scim.ParseFilter(filter)
+	if err != nil {
+		return nil, false, err
+	}
+	conds, ok := f.Conjunction()
+	if !ok {
+		return nil, false, scim.BadRequest(scim.ErrInvalidFilter, `only comparisons joined by "and" are supported`)
+	}
+
+	for _, c := range conds {
+		criterion, ok, err := s.userCriterion(ctx, c)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		criteria = append(criteria, criterion)
+	}
+	return criteria, true, nil
+}
+
+func (s *SCIMAppService) userCriterion(ctx context.Context, c *scim.Filter) (user.Criterion, bool, error) {
+	attr := strings.ToLower(c.Attr)
+	switch attr {
+	case "username":
+		return textCriterion(user.FieldUsername, c)
+	case "displayname", "name.formatted":
+		return textCriterion(user.FieldNickname, c)
+	case "emails", "emails.value":
+		return textCriterion(user.FieldEmail, c)
+	case "meta.created", "meta.lastmodified":
+		field := user.FieldCreatedAt
+		if attr == "meta.lastmodified" {
+			field = user.FieldUpdatedAt
+		}
+		text, _ := c.Value.(string)
+		at, err := time.Parse(time.RFC3339, text)
+		if err != nil || c.Op == scim.Pr || c.Op == scim.Co || c.Op == scim.Sw || c.Op == scim.Ew {
+			return user.Criterion{}, false, scim.BadRequest(scim.ErrInvalidFilter, c.Attr+" must be compared with an RFC 3339 date-time")
+		}
+		return user.Criterion{Field: field, Op: user.Operator(c.Op), Value: at}, true, nil
+	case "active":
+		active, ok := c.Value.(bool)
+		if !ok || (c.Op != scim.Eq && c.Op != scim.Ne) {
+			return user.Criterion{}, false, scim.BadRequest(scim.ErrInvalidFilter, "active supports eq and ne with a boolean")
+		}
+		status := user.StatusDisabled
+		if active == (c.Op == scim.Eq) {
+			status = user.StatusActive
+		}
+		return user.Criterion{Field: user.FieldStatus, Op: user.OpEq, Value: string(status)}, true, nil
+	}
+
+	// The remaining attributes identify a single resource
+	value, ok := c.Value.(string)
+	if !ok || c.Op != scim.Eq {
+		return user.Criterion{}, false, scim.BadRequest(scim.ErrInvalidFilter, fmt.Sprintf("unsupported filter on %s; supported attributes are userName, displayName, emails, active, meta.created and meta.lastModified, and eq on id, externalId and groups", c.Attr))
+	}
+	switch attr {
+	case "id":
+		id, err := strconv.ParseUint(value, 10, 32)
+		return user.Criterion{Field: user.FieldID, Op: user.OpEq, Value: uint(id)}, err == nil, nil
+	case "externalid":
+		identity, err := s.identities.Find(scimProvider, value)
+		if errors.Is(err, user.ErrIdentityNotFound) {
+			return user.Criterion{}, false, nil
+		} else if err != nil {
+			return user.Criterion{}, false, err
+		}
+		return user.Criterion{Field: user.FieldID, Op: user.OpEq, Value: identity.UserID}, true, nil
+	case "groups", "groups.value":
+		role, err := s.findRole(value)
+		if errors.Is(err, errcode.ErrRoleNotFound) {
+			return user.Criterion{}, false, nil
+		} else if err != nil {
+			return user.Criterion{}, false, err
+		}
+		return user.Criterion{Field: user.FieldRole, Op: user.OpEq, Value: role.Code}, true, nil
+	}
+	return user.Criterion{}, false, scim.BadRequest(scim.ErrInvalidFilter, "unsupported filter attribute: "+c.Attr)
+}
+
+func textCriterion(field user.Field, c *scim.Filter) (user.Criterion, bool, error) {
+	if _, ok := c.Value.(string); !ok && c.Op != scim.Pr {
+		return user.Criterion{}, false, scim.BadRequest(scim.ErrInvalidFilter, c.Attr+" must be compared with a string")
+	}
+	return user.Criterion{Field: field, Op: user.Operator(c.Op), Value: c.Value}, true, nil
+}
+
+// filterRoles keeps the roles matching the filter. Roles are few, so the
+// filter is evaluated in memory.
+func (s *SCIMAppService) filterRoles(ctx context.Context, roles []*rbac.Role, filter string) ([]*rbac.Role, error) {
+	f, err := scim.ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	conds, ok := f.Conjunction()
+	if !ok {
+		return nil, scim.BadRequest(scim.ErrInvalidFilter, `only comparisons joined by "and" are supported`)
+	}
+
+	result := roles[:0:0]
+	for _, role := range roles {
+		matched := true
+		for _, c := range conds {
+			if matched, err = s.roleMatches(ctx, role, c); err != nil {
+				return nil, err
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			result = append(result, role)
+		}
+	}
+	return result, nil
+}
+
+func (s *SCIMAppService) roleMatches(ctx context.Context, role *rbac.Role, c *scim.Filter) (bool, error) {
+	value, ok := c.Value.(string)
+	if !ok && c.Op != scim.Pr {
+		return false, scim.BadRequest(scim.ErrInvalidFilter, c.Attr+" must be compared with a string")
+	}
+	switch strings.ToLower(c.Attr) {
+	case "displayname":
+		return compareText(c.Op, role.Code, value), nil
+	case "id":
+		return compareText(c.Op, strconv.FormatUint(uint64(role.ID), 10), value), nil
+	case "externalid":
+		return compareText(c.Op, "", value), nil
+	case "members", "members.value":
+		if c.Op != scim.Eq {
+			return false, scim.BadRequest(scim.ErrInvalidFilter, "members supports eq only")
+		}
+		u, err := s.findUser(ctx, value)
+		if errors.Is(err, errcode.ErrAccountNotFound) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		return string(u.Role) == role.Code, nil
+	}
+	return false, scim.BadRequest(scim.ErrInvalidFilter, "unsupported filter attribute: "+c.Attr+"; supported attributes are displayName, id and members")
+}
+
+// compareText applies a comparison to strings, ignoring case
+func compareText(op scim.Operator, actual, value string) bool {
+	actual, value = strings.ToLower(actual), strings.ToLower(value)
+	switch op {
+	case scim.Eq:
+		return actual == value
+	case scim.Ne:
+		return actual != value
+	case scim.Co:
+		return strings.Contains(actual, value)
+	case scim.Sw:
+		return strings.HasPrefix(actual, value)
+	case scim.Ew:
+		return strings.HasSuffix(actual, value)
+	case scim.Pr:
+		return actual != ""
+	case scim.Gt:
+		return actual > value
+	case scim.Ge:
+		return actual >= value
+	case scim.Lt:
+		return actual < value
+	case scim.Le:
+		return actual <= value
+	}
+	return false
+}
+
+// forEachPatchTarget calls fn for the target of the operation, or for each
+// attribute of its value when the path is absent
+func forEachPatchTarget(op scim.PatchOperation, fn func(kind string, path *scim.Path, value json.RawMessage) error) error {
+	kind := strings.ToLower(op.Op)
+	switch kind {
+	case scim.OpAdd, scim.OpReplace, scim.OpRemove:
+	default:
+		return scim.BadRequest(scim.ErrInvalidSyntax, "unsupported PATCH op: "+op.Op)
+	}
+
+	if op.Path != "" {
+		path, err := scim.ParsePath(op.Path)
+		if err != nil {
+			return err
+		}
+		return fn(kind, path, op.Value)
+	}
+	if kind == scim.OpRemove {
+		return scim.BadRequest(scim.ErrNoTarget, "remove requires a path")
+	}
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &attrs); err != nil {
+		return scim.BadRequest(scim.ErrInvalidSyntax, "value must be an object when path is absent")
+	}
+	for name, value := range attrs {
+		path, err := scim.ParsePath(name)
+		if err != nil {
+			return err
+		}
+		if err := fn(kind, path, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func patchSCIMUser(st *scimUserState, kind string, path *scim.Path, value json.RawMessage) error {
+	remove := kind == scim.OpRemove
+	sub := strings.ToLower(path.Sub)
+	switch attr := strings.ToLower(path.Attr); attr {
+	case "username":
+		if remove {
+			return scim.BadRequest(scim.ErrMutability, "userName is required")
+		}
+		return decodeSCIMValue(path, value, &st.userName)
+	case "displayname":
+		if remove {
+			st.nickname = ""
+			return nil
+		}
+		return decodeSCIMValue(path, value, &st.nickname)
+	case "name":
+		switch {
+		case sub != "" && sub != "formatted":
+			// Only the formatted name is stored
+			return nil
+		case remove:
+			st.nickname = ""
+			return nil
+		case sub == "formatted":
+			return decodeSCIMValue(path, value, &st.nickname)
+		}
+		var name dto.SCIMName
+		if err := decodeSCIMValue(path, value, &name); err != nil {
+			return err
+		}
+		st.nickname = name.Value()
+		return nil
+	case "emails":
+		if remove {
+			st.email = ""
+			return nil
+		}
+		if path.Filter != nil || sub != "" {
+			// emails[type eq "work"].value: the single stored address
+			if sub != "value" {
+				return nil
+			}
+			return decodeSCIMValue(path, value, &st.email)
+		}
+		var emails []dto.SCIMEmail
+		if err := decodeSCIMValue(path, value, &emails); err != nil {
+			return err
+		}
+		st.email = dto.PrimaryEmail(emails)
+		return nil
+	case "active":
+		if remove {
+			return scim.BadRequest(scim.ErrMutability, "active cannot be removed")
+		}
+		return decodeSCIMBool(path, value, &st.active)
+	case "externalid":
+		if remove {
+			st.externalID = ""
+			return nil
+		}
+		return decodeSCIMValue(path, value, &st.externalID)
+	case "password":
+		if remove {
+			return scim.BadRequest(scim.ErrMutability, "password cannot be removed")
+		}
+		return decodeSCIMValue(path, value, &st.password)
+	case "groups":
+		return scim.BadRequest(scim.ErrMutability, "groups is read-only; change memberships through /Groups")
+	case "id", "meta", "schemas":
+		return scim.BadRequest(scim.ErrMutability, path.Attr+" is read-only")
+	}
+	// Attributes that are not stored (addresses, title, extensions...) are ignored
+	return nil
+}
+
+func patchSCIMGroup(role *rbac.Role, members map[uint]bool, kind string, path *scim.Path, value json.RawMessage) error {
+	switch strings.ToLower(path.Attr) {
+	case "displayname":
+		var name string
+		if kind == scim.OpRemove {
+			return scim.BadRequest(scim.ErrMutability, "displayName is required")
+		}
+		if err := decodeSCIMValue(path, value, &name); err != nil {
+			return err
+		}
+		if name != role.Code {
+			return scim.BadRequest(scim.ErrMutability, "displayName is the role code and cannot change")
+		}
+		return nil
+	case "members":
+	case "id", "meta", "schemas":
+		return scim.BadRequest(scim.ErrMutability, path.Attr+" is read-only")
+	default:
+		return nil
+	}
+
+	if path.Filter != nil {
+		// members[value eq "42"]
+		if kind != scim.OpRemove {
+			return scim.BadRequest(scim.ErrInvalidPath, "a filtered members path is only supported by remove")
+		}
+		for id := range members {
+			matched, err := memberMatches(path.Filter, strconv.FormatUint(uint64(id), 10))
+			if err != nil {
+				return err
+			}
+			if matched {
+				delete(members, id)
+			}
+		}
+		return nil
+	}
+
+	var refs []dto.SCIMMember
+	if len(value) > 0 && string(value) != "null" {
+		if err := decodeSCIMValue(path, value, &refs); err != nil {
+			return err
+		}
+	}
+	ids, err := memberIDs(refs)
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case scim.OpReplace:
+		clear(members)
+		fallthrough
+	case scim.OpAdd:
+		for _, id := range ids {
+			members[id] = true
+		}
+	case scim.OpRemove:
+		// Without a value every member is removed
+		if len(refs) == 0 {
+			clear(members)
+		}
+		for _, id := range ids {
+			delete(members, id)
+		}
+	}
+	return nil
+}
+
+// memberMatches evaluates a members value filter for one member ID
+func memberMatches(f *scim.Filter, id string) (bool, error) {
+	switch f.Op {
+	case scim.And, scim.Or:
+		left, err := memberMatches(f.Left, id)
+		if err != nil {
+			return false, err
+		}
+		right, err := memberMatches(f.Right, id)
+		if err != nil {
+			return false, err
+		}
+		if f.Op == scim.And {
+			return left && right, nil
+		}
+		return left || right, nil
+	case scim.Not:
+		matched, err := memberMatches(f.Left, id)
+		return !matched, err
+	case scim.Eq, scim.Ne:
+		value, ok := f.Value.(string)
+		if ok && strings.EqualFold(f.Attr, "value") {
+			return (value == id) == (f.Op == scim.Eq), nil
+		}
+	}
+	return false, scim.BadRequest(scim.ErrInvalidFilter, `member filters support eq and ne on "value"`)
+}
+
+func memberIDs(refs []dto.SCIMMember) ([]uint, error) {
+	ids := make([]uint, len(refs))
+	for i, ref := range refs {
+		id, err := strconv.ParseUint(ref.Value, 10, 32)
+		if err != nil {
+			return nil, scim.BadRequest(scim.ErrInvalidValue, fmt.Sprintf("invalid member %q", ref.Value))
+		}
+		ids[i] = uint(id)
+	}
+	return ids, nil
+}
+
+func decodeSCIMValue(path *scim.Path, value json.RawMessage, dst any) error {
+	if err := json.Unmarshal(value, dst); err != nil {
+		return scim.BadRequest(scim.ErrInvalidValue, "invalid value for "+path.Attr)
+	}
+	return nil
+}
+
+// decodeSCIMBool accepts booleans and, as some identity providers send them,
+// the strings "true" and "false"
+func decodeSCIMBool(path *scim.Path, value json.RawMessage, dst *bool) error {
+	if json.Unmarshal(value, dst) == nil {
+		return nil
+	}
+	var text string
+	if json.Unmarshal(value, &text) == nil {
+		if b, err := strconv.ParseBool(text); err == nil {
+			*dst = b
+			return nil
+		}
+	}
+	return scim.BadRequest(scim.ErrInvalidValue, path.Attr+" must be a boolean")
+}
+
+func stateFromSCIMUser(req *dto.SCIMUser) *scimUserState {
+	return &scimUserState{
+		userName:   req.UserName,
+		nickname:   req.DisplayNameValue(),
+		email:      dto.PrimaryEmail(req.Emails),
+		externalID: req.ExternalID,
+		active:     req.Active == nil || *req.Active,
+		password:   req.Password,
+	}
+}
+
+// validateSCIMUser applies the limits of the admin API to provisioned users
+func validateSCIMUser(st *scimUserState) error {
+	if n := utf8.RuneCountInString(st.userName); n < 3 || n > 50 {
+		return scim.BadRequest(scim.ErrInvalidValue, "userName must be 3-50 characters")
+	}
+	if utf8.RuneCountInString(st.nickname) > 100 {
+		return scim.BadRequest(scim.ErrInvalidValue, "displayName must be at most 100 characters")
+	}
+	if st.email != "" {
+		addr, err := mail.ParseAddress(st.email)
+		if err != nil || addr.Address != st.email || len(st.email) > 255 {
+			return scim.BadRequest(scim.ErrInvalidValue, "invalid email address")
+		}
+	}
+	if len(st.externalID) > 255 {
+		return scim.BadRequest(scim.ErrInvalidValue, "externalId must be at most 255 characters")
+	}
+	return nil
+}
+
+// setSCIMProfile replaces the profile with the provisioned one. Addresses come
+// from the identity provider, which is trusted to have verified them.
+func setSCIMProfile(u *user.User, nickname, email string) {
+	u.Nickname = nickname
+	if email != u.Email {
+		u.Email = email
+		u.EmailVerifiedAt = nil
+		if email != "" {
+			u.VerifyEmail(time.Now())
+		}
+	}
+}
+
+// validRoleCode mirrors the validation of CreateRoleRequest.Code
+func validRoleCode(code string) bool {
+	if code == "" || len(code) > 50 {
+		return false
+	}
+	for _, r := range code {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// excludesMembers reports whether the excludedAttributes parameter names members
+func excludesMembers(excluded string) bool {
+	for _, attr := range strings.Split(excluded, ",") {
+		attr = strings.TrimSpace(attr)
+		if i := strings.LastIndexByte(attr, ':'); i >= 0 {
+			attr = attr[i+1:]
+		}
+		if strings.EqualFold(attr, "members") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/container"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/scim"
+)
+
+const scimToken = "scim-test-token-0123456789abcdef0123"
+
+// newSCIMTest returns a container with SCIM enabled for the default tenant
+// and a context authenticated as the provisioning client
+func newSCIMTest(t *testing.T, manageRoles bool) (*container.Container, context.Context) {
+	t.Helper()
+	c := newTestContainer(t, func(cfg *config.Config) {
+		cfg.SCIM.Enabled = true
+		cfg.SCIM.Token = scimToken
+		cfg.SCIM.ManageRoles = manageRoles
+	})
+	ctx, err := c.SCIMService.Authenticate(context.Background(), scimToken)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	return c, ctx
+}
+
+// scimGroupID returns the SCIM id of the group of a role
+func scimGroupID(t *testing.T, c *container.Container, ctx context.Context, role string) string {
+	t.Helper()
+	list, err := c.SCIMService.ListGroups(ctx, &dto.SCIMListQuery{Filter: `displayName eq "` + role + `"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := list.Resources.([]*dto.SCIMGroup)
+	if len(groups) != 1 {
+		t.Fatalf("%d groups named %s", len(groups), role)
+	}
+	return groups[0].ID
+}
+
+func addMember(userID string) *scim.PatchRequest {
+	value, _ := json.Marshal([]dto.SCIMMember{{Value: userID}})
+	return &scim.PatchRequest{Operations: []scim.PatchOperation{{Op: "add", Path: "members", Value: value}}}
+}
+
+func TestSCIMRefusesAdministrativeAndBuiltinGroups(t *testing.T) {
+	c, ctx := newSCIMTest(t, false)
+	admin := tenant.WithTenant(context.Background(), tenant.DefaultID)
+
+	provisioned, err := c.SCIMService.CreateUser(ctx, &dto.SCIMUser{UserName: "frank"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if _, err := c.RBACService.CreateRole(admin, &dto.CreateRoleRequest{Code: "auditor", Name: "Auditor", Permissions: []string{"audit:read"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RBACService.CreateRole(admin, &dto.CreateRoleRequest{Code: "helpdesk", Name: "Helpdesk", Permissions: []string{"user:write"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The built-in admin role and roles that administer users are off limits
+	for _, role := range []string{"admin", "helpdesk"} {
+		_, err := c.SCIMService.PatchGroup(ctx, scimGroupID(t, c, ctx, role), addMember(provisioned.ID))
+		requireCode(t, err, errcode.ErrPermissionDenied)
+	}
+
+	// Ordinary roles take members, but not away from an administrative role
+	if _, err := c.SCIMService.PatchGroup(ctx, scimGroupID(t, c, ctx, "auditor"), addMember(provisioned.ID)); err != nil {
+		t.Fatalf("PatchGroup auditor: %v", err)
+	}
+	local, err := c.UserService.Create(admin, &dto.CreateUserRequest{Username: "grace", Password: "Zq8#vLm2!pT9x", Role: "helpdesk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.SCIMService.PatchGroup(ctx, scimGroupID(t, c, ctx, "auditor"), addMember(strconv.FormatUint(uint64(local.ID), 10)))
+	requireCode(t, err, errcode.ErrPermissionDenied)
+	if u, _ := c.UserService.GetByID(admin, local.ID); u == nil || u.Role != "helpdesk" {
+		t.Fatalf("administrator's role changed: %+v", u)
+	}
+}
+
+func TestSCIMRefusesToChangeAdministrators(t *testing.T) {
+	c, ctx := newSCIMTest(t, false)
+	admin := tenant.WithTenant(context.Background(), tenant.DefaultID)
+	if _, err := c.RBACService.CreateRole(admin, &dto.CreateRoleRequest{Code: "helpdesk", Name: "Helpdesk", Permissions: []string{"user:write"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, role := range []string{"admin", "helpdesk"} {
+		t.Run(role, func(t *testing.T) {
+			local, err := c.UserService.Create(admin, &dto.CreateUserRequest{Username: "ivan-" + role, Password: "Zq8#vLm2!pT9x", Role: role})
+			if err != nil {
+				t.Fatal(err)
+			}
+			id := strconv.FormatUint(uint64(local.ID), 10)
+			inactive := false
+
+			_, err = c.SCIMService.ReplaceUser(ctx, id, &dto.SCIMUser{UserName: "mallory", Emails: []dto.SCIMEmail{{Value: "mallory@example.com"}}, Active: &inactive})
+			requireCode(t, err, errcode.ErrPermissionDenied)
+			email, _ := json.Marshal("mallory@example.com")
+			_, err = c.SCIMService.PatchUser(ctx, id, &scim.PatchRequest{Operations: []scim.PatchOperation{{Op: "replace", Path: "emails", Value: email}}})
+			requireCode(t, err, errcode.ErrPermissionDenied)
+			err = c.SCIMService.DeleteUser(ctx, id)
+			requireCode(t, err, errcode.ErrPermissionDenied)
+
+			u, err := c.UserService.GetByID(admin, local.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Username != local.Username || u.Email != local.Email || u.Status != local.Status {
+				t.Fatalf("administrator changed: %+v, was %+v", u, local)
+			}
+		})
+	}
+}
+
+func TestSCIMGroupLifecycleRequiresManageRoles(t *testing.T) {
+	c, ctx := newSCIMTest(t, false)
+	_, err := c.SCIMService.CreateGroup(ctx, &dto.SCIMGroup{DisplayName: "engineers"})
+	requireCode(t, err, errcode.ErrPermissionDenied)
+	err = c.SCIMService.DeleteGroup(ctx, scimGroupID(t, c, ctx, "user"))
+	requireCode(t, err, errcode.ErrPermissionDenied)
+
+	c, ctx = newSCIMTest(t, true)
+	group, err := c.SCIMService.CreateGroup(ctx, &dto.SCIMGroup{DisplayName: "engineers"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if err := c.SCIMService.DeleteGroup(ctx, group.ID); err != nil {
+		t.Fatalf("DeleteGroup: %v", err)
+	}
+	// Even then the built-in and administrative roles stay
+	err = c.SCIMService.DeleteGroup(ctx, scimGroupID(t, c, ctx, "admin"))
+	requireCode(t, err, errcode.ErrPermissionDenied)
+}
+
+func TestSCIMPasswordsFollowThePolicy(t *testing.T) {
+	c, ctx := newSCIMTest(t, false)
+
+	_, err := c.SCIMService.CreateUser(ctx, &dto.SCIMUser{UserName: "heidi", Password: "123"})
+	requireCode(t, err, errcode.ErrPasswordPolicy)
+
+	u, err := c.SCIMService.CreateUser(ctx, &dto.SCIMUser{UserName: "heidi", Password: "Zq8#vLm2!pT9x"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	_, err = c.SCIMService.ReplaceUser(ctx, u.ID, &dto.SCIMUser{UserName: "heidi", Password: "password"})
+	requireCode(t, err, errcode.ErrPasswordPolicy)
+
+	weak, _ := json.Marshal("abc")
+	_, err = c.SCIMService.PatchUser(ctx, u.ID, &scim.PatchRequest{Operations: []scim.PatchOperation{{Op: "replace", Path: "password", Value: weak}}})
+	requireCode(t, err, errcode.ErrPasswordPolicy)
+
+	// The history applies as well
+	same, _ := json.Marshal("Zq8#vLm2!pT9x")
+	_, err = c.SCIMService.PatchUser(ctx, u.ID, &scim.PatchRequest{Operations: []scim.PatchOperation{{Op: "replace", Path: "password", Value: same}}})
+	requireCode(t, err, errcode.ErrPasswordReused)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/audit"
+	"go-ddd-scaffold/internal/domain/session"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/errcode"
+)
+
+// SessionAppService lists signed-in devices and signs them out remotely
+type SessionAppService struct {
+	repo   session.Repository
+	users  user.Repository
+	tokens *TokenService
+}
+
+// NewSessionAppService creates a new application service
+func NewSessionAppService(repo session.Repository, users user.Repository, tokens *TokenService) *SessionAppService {
+	return &SessionAppService{repo: repo, users: users, tokens: tokens}
+}
+
+// List returns the user's active sessions; currentID marks the caller's own.
+// The user must belong to the caller's tenant.
+func (s *SessionAppService) List(ctx context.Context, userID uint, currentID string) ([]*dto.SessionResponse, error) {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListActive(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromSessionList(items, currentID), nil
+}
+
+// Revoke signs one of the user's sessions out: its refresh token stops
+// working and its access tokens are rejected immediately
+func (s *SessionAppService) Revoke(ctx context.Context, userID, id uint) error {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return err
+	}
+	item, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, session.ErrSessionNotFound) {
+			return errcode.ErrSessionNotFound
+		}
+		return err
+	}
+	if item.UserID != userID {
+		return errcode.ErrSessionNotFound
+	}
+	auditEvent(ctx, audit.ActionSessionRevoke)
+	auditTarget(ctx, item.ID)
+	if !item.IsActive(time.Now()) {
+		return nil
+	}
+	return s.tokens.RevokeSession(ctx, item.FamilyID)
+}
+
+// RevokeOthers signs out every session of the user except currentID
+func (s *SessionAppService) RevokeOthers(ctx context.Context, userID uint, currentID string) error {
+	auditEvent(ctx, audit.ActionSessionRevokeAll)
+	items, err := s.repo.ListActive(userID, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.FamilyID == currentID {
+			continue
+		}
+		if err := s.tokens.RevokeSession(ctx, item.FamilyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeAll signs out every session of the user
+func (s *SessionAppService) RevokeAll(ctx context.Context, userID uint) error {
+	if err := s.checkUser(ctx, userID); err != nil {
+		return err
+	}
+	auditEvent(ctx, audit.ActionSessionRevokeAll)
+	return s.tokens.RevokeAll(ctx, userID)
+}
+
+// checkUser confirms the user is visible in the caller's tenant
+func (s *SessionAppService) checkUser(ctx context.Context, userID uint) error {
+	if _, err := s.users.FindByID(ctx, userID); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return errcode.ErrAccountNotFound
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/audit"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/cache"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+const (
+	ssoStatePrefix = "sso:state:"
+	ssoCodePrefix  = "sso:code:"
+
+	// ssoStateLifetime bounds the time the user may spend at the identity provider
+	ssoStateLifetime = 10 * time.Minute
+	// ssoCodeLifetime bounds the time between the callback and the frontend exchange
+	ssoCodeLifetime = time.Minute
+
+	maxUsernameLength = 50
+)
+
+// ssoState is what the login redirect remembers for the callback
+type ssoState struct {
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// ssoClaims are the ID token claims the service reads besides the configured
+// username and groups claims
+type ssoClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// SSOAppService signs users in through an OpenID Connect provider using the
+// authorization code flow with PKCE, then issues the service's own tokens
+type SSOAppService struct {
+	cfg        *config.SSOConfig
+	users      user.Repository
+	identities user.IdentityRepository
+	rbac       *RBACAppService
+	auth       *AuthAppService
+	cache      cache.Cache
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// NewSSOAppService creates a new application service. Provider discovery is
+// deferred to the first login so the service starts while the IdP is down.
+func NewSSOAppService(cfg *config.SSOConfig, users user.Repository, identities user.IdentityRepository, rbacSvc *RBACAppService, auth *AuthAppService, c cache.Cache) *SSOAppService {
+	return &SSOAppService{cfg: cfg, users: users, identities: identities, rbac: rbacSvc, auth: auth, cache: c}
+}
+
+// Enabled reports whether single sign-on is configured
+func (s *SSOAppService) Enabled() bool {
+	return s.cfg.Enabled
+}
+
+// FrontendURL returns the page the browser is sent back to after the callback
+func (s *SSOAppService) FrontendURL() string {
+	return s.cfg.FrontendURL
+}
+
+// RedirectURL returns the callback URL registered at the identity provider
+func (s *SSOAppService) RedirectURL() string {
+	return s.cfg.RedirectURL
+}
+
+// AuthURL starts a login and returns the identity provider URL to redirect to,
+// along with the state the caller must bind to the browser (see Callback)
+func (s *SSOAppService) AuthURL(ctx context.Context) (target, state string, err error) {
+	if !s.cfg.Enabled {
+		return "", "", errcode.ErrSSODisabled
+	}
+	oauth, _, err := s.client(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err = newOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := newOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	pending := ssoState{Nonce: nonce, Verifier: oauth2.GenerateVerifier()}
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.cache.Set(ctx, ssoStatePrefix+state, data, ssoStateLifetime); err != nil {
+		return "", "", err
+	}
+
+	target = oauth.AuthCodeURL(state,
+		oauth2.S256ChallengeOption(pending.Verifier),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	)
+	return target, state, nil
+}
+
+// Callback completes a login: it redeems the authorization code, verifies the
+// ID token, provisions or links the local user and maps IdP groups to a role.
+// The resulting tokens are parked under a short-lived one-time code so they
+// never appear in a URL; the frontend redeems it through Exchange.
+//
+// boundState is the state remembered by the browser that started the login
+// (a cookie set next to the AuthURL redirect). It must match state, otherwise
+// anyone could finish their own login and send the callback URL to a victim,
+// who would then be signed in as the attacker.
+func (s *SSOAppService) Callback(ctx context.Context, code, state, boundState string, client ClientInfo) (string, error) {
+	exchangeCode, err := s.callback(ctx, code, state, boundState, client)
+	if err != nil {
+		auditEvent(ctx, audit.ActionLoginFailure)
+	}
+	return exchangeCode, err
+}
+
+func (s *SSOAppService) callback(ctx context.Context, code, state, boundState string, client ClientInfo) (string, error) {
+	if !s.cfg.Enabled {
+		return "", errcode.ErrSSODisabled
+	}
+	if boundState == "" || subtle.ConstantTimeCompare([]byte(state), []byte(boundState)) != 1 {
+		return "", errcode.ErrSSOFailed.WithMessage("login was not started from this browser, please try again")
+	}
+	pending, err := s.takeState(ctx, state)
+	if err != nil {
+		return "", err
+	}
+	oauth, provider, err := s.client(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	tok, err := oauth.Exchange(ctx, code, oauth2.VerifierOption(pending.Verifier))
+	if err != nil {
+		logger.Warnf("sso code exchange failed: %v", err)
+		return "", errcode.ErrSSOFailed.WithMessage("authorization code exchange failed")
+	}
+	rawIDToken, ok := tok.Extra("id_token").(string)
+	if !ok {
+		return "", errcode.ErrSSOFailed.WithMessage("identity provider returned no id_token")
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: s.cfg.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		logger.Warnf("sso id_token rejected: %v", err)
+		return "", errcode.ErrSSOFailed.WithMessage("invalid id_token")
+	}
+	if idToken.Nonce != pending.Nonce {
+		return "", errcode.ErrSSOFailed.WithMessage("id_token nonce mismatch")
+	}
+
+	var claims ssoClaims
+	var raw map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return "", err
+	}
+	if err := idToken.Claims(&raw); err != nil {
+		return "", err
+	}
+
+	u, err := s.resolveUser(ctx, idToken.Issuer, idToken.Subject, &claims, raw)
+	if err != nil {
+		return "", err
+	}
+	tokens, err := s.auth.SignIn(ctx, u, client)
+	if err != nil {
+		return "", err
+	}
+
+	exchangeCode, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return "", err
+	}
+	if err := s.cache.Set(ctx, ssoCodePrefix+exchangeCode, data, ssoCodeLifetime); err != nil {
+		return "", err
+	}
+	logger.Infof("sso login user_id=%d username=%s subject=%s", u.ID, u.Username, idToken.Subject)
+	return exchangeCode, nil
+}
+
+// Exchange redeems the one-time code handed to the frontend after the callback
+func (s *SSOAppService) Exchange(ctx context.Context, code string) (*dto.TokenResponse, error) {
+	data, err := s.cache.Take(ctx, ssoCodePrefix+code)
+	if err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, errcode.ErrSSOFailed.WithMessage("sign-in code is invalid or expired")
+		}
+		return nil, err
+	}
+
+	var tokens dto.TokenResponse
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// resolveUser finds the user linked to the IdP account, linking an existing
+// user by verified email or provisioning a new one on first login. Linking and
+// provisioning happen in the default tenant; an existing link is followed to
+// whichever tenant its user belongs to.
+func (s *SSOAppService) resolveUser(ctx context.Context, issuer, subject string, claims *ssoClaims, raw map[string]any) (*user.User, error) {
+	role, matched := s.mapRole(stringsClaim(raw[s.cfg.GroupsClaim]))
+
+	identity, err := s.identities.Find(issuer, subject)
+	switch {
+	case err == nil:
+		u, err := s.users.FindByID(tenant.WithAllTenants(ctx), identity.UserID)
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				return nil, errcode.ErrAccountNotFound
+			}
+			return nil, err
+		}
+		if matched {
+			if err := s.rbac.SyncMappedRole(tenant.WithTenant(ctx, u.TenantID), u, role); err != nil {
+				return nil, err
+			}
+		}
+		return u, nil
+	case !errors.Is(err, user.ErrIdentityNotFound):
+		return nil, err
+	}
+
+	u, err := s.linkOrProvision(tenant.WithTenant(ctx, tenant.DefaultID), claims, raw, role, matched)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.identities.Save(user.NewIdentity(u.ID, issuer, subject)); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *SSOAppService) linkOrProvision(ctx context.Context, claims *ssoClaims, raw map[string]any, role string, matched bool) (*user.User, error) {
+	if s.cfg.LinkByEmail && claims.Email != "" && claims.EmailVerified {
+		u, err := s.users.FindByEmail(ctx, claims.Email)
+		if err == nil {
+			if err := s.checkLinkable(ctx, u); err != nil {
+				return nil, err
+			}
+			if matched {
+				if err := s.rbac.SyncMappedRole(ctx, u, role); err != nil {
+					return nil, err
+				}
+			}
+			return u, nil
+		}
+		if !errors.Is(err, user.ErrUserNotFound) {
+			return nil, err
+		}
+	}
+
+	if !s.cfg.AutoProvision {
+		return nil, errcode.ErrSSOFailed.WithMessage("no local account is linked to this identity")
+	}
+	if role == "" {
+		return nil, errcode.ErrSSOFailed.WithMessage("no role is mapped for this identity")
+	}
+	if err := s.rbac.CheckMappedRole(role); err != nil {
+		return nil, err
+	}
+
+	username := s.username(claims, raw)
+	if username == "" || len(username) > maxUsernameLength {
+		return nil, errcode.ErrSSOFailed.WithMessage("identity provider returned no usable username")
+	}
+	// A local account with the same name is never taken over implicitly
+	if _, err := s.users.FindByUsername(ctx, username); err == nil {
+		return nil, errcode.ErrAccountExists
+	} else if !errors.Is(err, user.ErrUserNotFound) {
+		return nil, err
+	}
+
+	// No local password: the user signs in through the IdP only
+	u := user.NewUser(username, "", user.Role(role))
+	u.UpdateProfile(claims.Name, claims.Email)
+	if claims.EmailVerified {
+		u.VerifyEmail(time.Now())
+	}
+	if err := s.users.Save(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// checkLinkable refuses to link an identity to a local user by email unless
+// the user has proved control of the same address. Administrators are never
+// linked this way: whoever controls a matching IdP account would take over
+// the most privileged users without either side consenting.
+func (s *SSOAppService) checkLinkable(ctx context.Context, u *user.User) error {
+	if u.ServiceAccount {
+		return errcode.ErrSSOFailed.WithMessage("service accounts cannot use single sign-on")
+	}
+	if !u.IsEmailVerified() {
+		return errcode.ErrSSOFailed.WithMessage("the local account's email is not verified")
+	}
+	if u.SuperAdmin {
+		return errcode.ErrSSOFailed.WithMessage("administrator accounts are not linked by email")
+	}
+	admin, err := s.rbac.IsAdminRole(ctx, string(u.Role))
+	if err != nil {
+		return err
+	}
+	if admin {
+		return errcode.ErrSSOFailed.WithMessage("administrator accounts are not linked by email")
+	}
+	return nil
+}
+
+// mapRole returns the role of the first mapping whose group the user is in,
+// or the default role with matched set to false
+func (s *SSOAppService) mapRole(groups []string) (role string, matched bool) {
+	for _, m := range s.cfg.RoleMappings {
+		if slices.Contains(groups, m.Group) {
+			return m.Role, true
+		}
+	}
+	return s.cfg.DefaultRole, false
+}
+
+// username reads the configured username claim, falling back to email and subject
+func (s *SSOAppService) username(claims *ssoClaims, raw map[string]any) string {
+	if v, ok := raw[s.cfg.UsernameClaim].(string); ok && v != "" {
+		return v
+	}
+	if claims.Email != "" {
+		return claims.Email
+	}
+	v, _ := raw["sub"].(string)
+	return v
+}
+
+// takeState atomically loads and deletes the state saved by AuthURL, so each
+// is used once even by concurrent callbacks
+func (s *SSOAppService) takeState(ctx context.Context, state string) (*ssoState, error) {
+	if state == "" {
+		return nil, errcode.ErrSSOFailed.WithMessage("missing state")
+	}
+	data, err := s.cache.Take(ctx, ssoStatePrefix+state)
+	if err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, errcode.ErrSSOFailed.WithMessage("login expired, please try again")
+		}
+		return nil, err
+	}
+
+	var pending ssoState
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// client returns the OAuth2 client, discovering the provider on first use
+func (s *SSOAppService) client(ctx context.Context) (*oauth2.Config, *oidc.Provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider == nil {
+		// Discovery outlives the request that triggered it
+		provider, err := oidc.NewProvider(context.WithoutCancel(ctx), s.cfg.Issuer)
+		if err != nil {
+			logger.Errorf("sso provider discovery failed issuer=%s: %v", s.cfg.Issuer, err)
+			return nil, nil, errcode.ErrSSOFailed.WithMessage("identity provider is unavailable")
+		}
+		s.provider = provider
+	}
+
+	scopes := []string{oidc.ScopeOpenID}
+	for _, scope := range s.cfg.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return &oauth2.Config{
+		ClientID:     s.cfg.ClientID,
+		ClientSecret: s.cfg.ClientSecret,
+		RedirectURL:  s.cfg.RedirectURL,
+		Endpoint:     s.provider.Endpoint(),
+		Scopes:       scopes,
+	}, s.provider, nil
+}
+
+// stringsClaim accepts a claim holding either a list of strings or a single string
+func stringsClaim(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,273 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/mailer/mailertest"
+	"go-ddd-scaffold/pkg/oidctest"
+)
+
+const ssoRedirectURL = "http://localhost:8080/api/v1/auth/oidc/callback"
+
+// newSSOTest starts a mock identity provider signing in alice (group "admins"
+// maps to the admin role) and a container pointed at it
+func newSSOTest(t *testing.T) (*service.SSOAppService, *service.TokenService, *oidctest.Server) {
+	t.Helper()
+	idp, err := oidctest.Start(oidctest.Options{
+		AutoLogin: &oidctest.User{Username: "alice", Email: "alice@example.com", Groups: []string{"admins"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(idp.Close)
+
+	c := newTestContainer(t, func(cfg *config.Config) {
+		cfg.SSO.Enabled = true
+		cfg.SSO.Issuer = idp.Issuer
+		cfg.SSO.ClientID = idp.ClientID
+		cfg.SSO.RedirectURL = ssoRedirectURL
+		cfg.SSO.RoleMappings = []config.SSORoleMapping{{Group: "admins", Role: "admin"}}
+	})
+	return c.SSOService, c.TokenService, idp
+}
+
+// authorize follows AuthURL to the identity provider and returns the code
+// and state it sends back to the callback
+func authorize(t *testing.T, svc *service.SSOAppService) (code, state string) {
+	t.Helper()
+	target, boundState, err := svc.AuthURL(context.Background())
+	if err != nil {
+		t.Fatalf("AuthURL: %v", err)
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := u.Query(); q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256" || q.Get("state") != boundState {
+		t.Fatalf("authorization URL lacks PKCE or state: %s", target)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize returned %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if callback.Query().Get("error") != "" {
+		t.Fatalf("authorize failed: %s", callback.Query().Get("error_description"))
+	}
+	return callback.Query().Get("code"), callback.Query().Get("state")
+}
+
+func TestSSOLogin(t *testing.T) {
+	svc, tokens, _ := newSSOTest(t)
+	ctx := context.Background()
+
+	code, state := authorize(t, svc)
+	exchangeCode, err := svc.Callback(ctx, code, state, state, service.ClientInfo{IP: "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	resp, err := svc.Exchange(ctx, exchangeCode)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	claims, err := tokens.Parse(ctx, resp.Token)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if claims.Username != "alice" || claims.Role != "admin" {
+		t.Fatalf("signed in as %s with role %s, want alice with admin", claims.Username, claims.Role)
+	}
+
+	// The sign-in code is single-use
+	_, err = svc.Exchange(ctx, exchangeCode)
+	requireCode(t, err, errcode.ErrSSOFailed)
+}
+
+func TestSSOCallbackRequiresBrowserBinding(t *testing.T) {
+	svc, _, _ := newSSOTest(t)
+	ctx := context.Background()
+
+	// An attacker's callback URL opened in a victim's browser carries no
+	// matching state cookie
+	code, state := authorize(t, svc)
+	_, victimState := authorize(t, svc)
+	for _, bound := range []string{"", victimState} {
+		_, err := svc.Callback(ctx, code, state, bound, service.ClientInfo{})
+		requireCode(t, err, errcode.ErrSSOFailed)
+	}
+
+	// The rejected attempts did not consume the state
+	if _, err := svc.Callback(ctx, code, state, state, service.ClientInfo{}); err != nil {
+		t.Fatalf("Callback from the starting browser: %v", err)
+	}
+}
+
+func TestSSOCallbackRejectsUnknownAndReusedState(t *testing.T) {
+	svc, _, _ := newSSOTest(t)
+	ctx := context.Background()
+
+	code, _ := authorize(t, svc)
+	_, err := svc.Callback(ctx, code, "forged", "forged", service.ClientInfo{})
+	requireCode(t, err, errcode.ErrSSOFailed)
+
+	code, state := authorize(t, svc)
+	if _, err := svc.Callback(ctx, code, state, state, service.ClientInfo{}); err != nil {
+		t.Fatalf("first Callback: %v", err)
+	}
+	code2, _ := authorize(t, svc)
+	_, err = svc.Callback(ctx, code2, state, state, service.ClientInfo{})
+	requireCode(t, err, errcode.ErrSSOFailed)
+}
+
+func TestSSOCallbackEnforcesPKCE(t *testing.T) {
+	svc, _, _ := newSSOTest(t)
+	ctx := context.Background()
+
+	// A code injected into another login is redeemed with that login's
+	// verifier, which does not match the code's challenge
+	stolenCode, _ := authorize(t, svc)
+	_, state := authorize(t, svc)
+	_, err := svc.Callback(ctx, stolenCode, state, state, service.ClientInfo{})
+	requireCode(t, err, errcode.ErrSSOFailed)
+}
+
+func TestSSOStateAndCodeAreTakenOnce(t *testing.T) {
+	svc, _, idp := newSSOTest(t)
+	ctx := context.Background()
+	idp.SetAutoLogin(&oidctest.User{Username: "bob", Groups: []string{"admins"}})
+
+	code, state := authorize(t, svc)
+	exchangeCode, err := svc.Callback(ctx, code, state, state, service.ClientInfo{})
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var redeemed atomic.Int32
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.Exchange(ctx, exchangeCode); err == nil {
+				redeemed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := redeemed.Load(); n != 1 {
+		t.Fatalf("sign-in code redeemed %d times, want 1", n)
+	}
+}
+
+func TestSSOLinkByEmail(t *testing.T) {
+	smtp, err := mailertest.Start(mailertest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(smtp.Close)
+	idp, err := oidctest.Start(oidctest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(idp.Close)
+	c := newTestContainer(t, func(cfg *config.Config) {
+		cfg.Mail.Driver = "smtp"
+		cfg.Mail.SMTP.Host = smtp.Host
+		cfg.Mail.SMTP.Port = smtp.Port
+		cfg.Mail.SMTP.Encryption = "none"
+		cfg.SSO.Enabled = true
+		cfg.SSO.Issuer = idp.Issuer
+		cfg.SSO.ClientID = idp.ClientID
+		cfg.SSO.RedirectURL = ssoRedirectURL
+		cfg.SSO.LinkByEmail = true
+	})
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+	if _, err := c.RBACService.CreateRole(ctx, &dto.CreateRoleRequest{Code: "helpdesk", Name: "Helpdesk", Permissions: []string{"user:write"}}); err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+
+	// local creates a user whose email is verified through the mailed link
+	// unless unverified is set
+	local := func(t *testing.T, username, role string, unverified bool) uint {
+		t.Helper()
+		email := username + "@example.com"
+		u, err := c.UserService.Create(ctx, &dto.CreateUserRequest{Username: username, Password: testPassword, Email: email, Role: role})
+		if err != nil {
+			t.Fatalf("Create %s: %v", username, err)
+		}
+		verifyToken := mailedToken(t, smtp, email, "Verify", "verify_token")
+		if !unverified {
+			if err := c.EmailService.VerifyEmail(context.Background(), &dto.VerifyEmailRequest{Token: verifyToken}); err != nil {
+				t.Fatalf("VerifyEmail: %v", err)
+			}
+		}
+		return u.ID
+	}
+	// signIn signs in at the IdP as username under a different IdP username,
+	// so only the email can match the local account
+	signIn := func(t *testing.T, username string, unverified bool) (*service.Claims, error) {
+		t.Helper()
+		idp.SetAutoLogin(&oidctest.User{Username: "idp-" + username, Email: username + "@example.com", EmailUnverified: unverified})
+		code, state := authorize(t, c.SSOService)
+		exchangeCode, err := c.SSOService.Callback(context.Background(), code, state, state, service.ClientInfo{})
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.SSOService.Exchange(context.Background(), exchangeCode)
+		if err != nil {
+			t.Fatalf("Exchange: %v", err)
+		}
+		return c.TokenService.Parse(context.Background(), resp.Token)
+	}
+
+	daveID := local(t, "dave", "", false)
+	claims, err := signIn(t, "dave", false)
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if claims.UserID != daveID {
+		t.Fatalf("signed in as user %d (%s), want dave (%d)", claims.UserID, claims.Username, daveID)
+	}
+
+	// An address the IdP did not verify is not matched: a new account is provisioned
+	erinID := local(t, "erin", "", false)
+	claims, err = signIn(t, "erin", true)
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if claims.UserID == erinID {
+		t.Fatal("linked to erin by an address the identity provider did not verify")
+	}
+
+	for name, tc := range map[string]struct {
+		username   string
+		role       string
+		unverified bool
+	}{
+		"unverified local address": {username: "fred", unverified: true},
+		"admin":                    {username: "gina", role: "admin"},
+		"admin-like role":          {username: "hank", role: "helpdesk"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			local(t, tc.username, tc.role, tc.unverified)
+			_, err := signIn(t, tc.username, false)
+			requireCode(t, err, errcode.ErrSSOFailed)
+		})
+	}
+}
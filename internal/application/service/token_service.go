@@ -0,0 +1,495 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/audit"
+	"go-ddd-scaffold/internal/domain/session"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/token"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/jwtkeys"
+	"go-ddd-scaffold/pkg/logger"
+	"go-ddd-scaffold/pkg/tokenblacklist"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func init() {
+	// Millisecond iat so a revocation watermark set in the same second as a
+	// login does not spare tokens issued just before it
+	jwt.TimePrecision = time.Millisecond
+}
+
+// Token restrictions, each limiting a token to the routes that accept it
+const (
+	// RestrictionPasswordChange: the password must be changed (after an admin reset)
+	RestrictionPasswordChange = "password_change"
+	// RestrictionMFAPending: the password was verified, a second factor is still due
+	RestrictionMFAPending = "mfa_pending"
+	// RestrictionMFAEnroll: the user's role requires 2FA but none is enrolled
+	RestrictionMFAEnroll = "mfa_enroll"
+)
+
+const (
+	// mfaChallengeLifetime bounds the time between password and second factor
+	mfaChallengeLifetime = 5 * time.Minute
+
+	// sessionTouchInterval throttles last-seen updates of active sessions
+	sessionTouchInterval = time.Minute
+
+	// sessionRevocationPrefix namespaces revoked session IDs in the blacklist
+	sessionRevocationPrefix = "sid:"
+
+	maxUserAgentLength = 255
+)
+
+// ClientInfo describes the device a login comes from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// Claims are the access token claims
+type Claims struct {
+	UserID      uint   `json:"user_id"`
+	Username    string `json:"username"`
+	Role        string `json:"role"`
+	TenantID    uint   `json:"tenant_id"`
+	SuperAdmin  bool   `json:"super_admin,omitempty"` // may manage tenants and act across them
+	Restriction string `json:"rst,omitempty"`         // limits the token to routes that explicitly accept it
+	SessionID   string `json:"sid,omitempty"`         // refresh token family started at login
+	Actor       *Actor `json:"act,omitempty"`         // administrator impersonating the subject
+	jwt.RegisteredClaims
+
+	// Set only for requests authenticated with an API key; never serialized
+	APIKeyID uint     `json:"-"`
+	Scopes   []string `json:"-"`
+}
+
+// IsAPIKey reports whether the claims come from an API key rather than a JWT
+func (c *Claims) IsAPIKey() bool {
+	return c.APIKeyID != 0
+}
+
+// IsImpersonated reports whether an administrator acts as the subject; nil
+// claims are not
+func (c *Claims) IsImpersonated() bool {
+	return c != nil && c.Actor != nil
+}
+
+// Actor is the RFC 8693 "act" claim: who really acts when a token
+// impersonates its subject
+type Actor struct {
+	Subject  string `json:"sub"`
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+}
+
+type claimsContextKey struct{}
+
+// Context returns ctx carrying the claims and scoped to the tenant they belong
+// to. Tokens issued before tenancy carry no tenant and belong to the default tenant.
+func (c *Claims) Context(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, claimsContextKey{}, c)
+	if c.TenantID == 0 {
+		return tenant.WithTenant(ctx, tenant.DefaultID)
+	}
+	return tenant.WithTenant(ctx, c.TenantID)
+}
+
+// isSuperAdmin reports whether the caller whose claims ctx carries is a super-admin
+func isSuperAdmin(ctx context.Context) bool {
+	c, _ := ctx.Value(claimsContextKey{}).(*Claims)
+	return c != nil && c.SuperAdmin
+}
+
+// RestrictionError returns the error for using a restricted token on an ordinary route
+func RestrictionError(restriction string) error {
+	switch restriction {
+	case RestrictionPasswordChange:
+		return errcode.ErrPasswordChangeRequired
+	case RestrictionMFAEnroll:
+		return errcode.ErrMFAEnrollRequired
+	case RestrictionMFAPending:
+		return errcode.ErrInvalidToken
+	default:
+		return errcode.ErrPermissionDenied
+	}
+}
+
+// TokenService issues, validates and revokes access and refresh tokens
+type TokenService struct {
+	cfg           *config.JWTConfig
+	keys          *jwtkeys.KeySet
+	parser        *jwt.Parser
+	blacklist     tokenblacklist.Blacklist
+	refreshTokens token.Repository
+	sessions      session.Repository
+
+	impersonationLifetime time.Duration
+}
+
+// NewTokenService creates a new token service. Access tokens are signed with
+// the key set's signing key and verified against any key in the set;
+// impersonation tokens expire after impersonationLifetime.
+func NewTokenService(cfg *config.JWTConfig, keys *jwtkeys.KeySet, blacklist tokenblacklist.Blacklist, refreshTokens token.Repository, sessions session.Repository, impersonationLifetime time.Duration) *TokenService {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(keys.Algorithms()),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithAudience(cfg.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	return &TokenService{cfg: cfg, keys: keys, parser: parser, blacklist: blacklist, refreshTokens: refreshTokens, sessions: sessions,
+		impersonationLifetime: impersonationLifetime}
+}
+
+// JWKS returns the public verification keys for other services
+func (s *TokenService) JWKS() jwtkeys.JWKS {
+	return s.keys.JWKS()
+}
+
+// IssuePair signs an access token and creates a refresh token in the given family.
+// An empty familyID starts a new family and session (i.e. a new login) for the
+// client; otherwise the family's session is extended.
+// A non-empty restriction limits the access token to the routes that accept it.
+func (s *TokenService) IssuePair(u *user.User, familyID, restriction string, client ClientInfo) (*dto.TokenResponse, error) {
+	now := time.Now()
+	refreshExpiresAt := now.Add(time.Duration(s.cfg.RefreshHours) * time.Hour)
+	if familyID == "" {
+		familyID = uuid.NewString()
+		if err := s.refreshTokens.DeleteExpired(u.ID, now); err != nil {
+			logger.Warnf("failed to purge expired refresh tokens user_id=%d: %v", u.ID, err)
+		}
+		if err := s.sessions.DeleteExpired(u.ID, now); err != nil {
+			logger.Warnf("failed to purge expired sessions user_id=%d: %v", u.ID, err)
+		}
+		userAgent := client.UserAgent
+		if len(userAgent) > maxUserAgentLength {
+			userAgent = userAgent[:maxUserAgentLength]
+		}
+		if err := s.sessions.Create(session.NewSession(u.ID, familyID, userAgent, client.IP, now, refreshExpiresAt)); err != nil {
+			return nil, err
+		}
+	} else if err := s.sessions.Extend(familyID, now, refreshExpiresAt); err != nil {
+		return nil, err
+	}
+
+	accessToken, expiresAt, err := s.issueAccess(u, nil, now, familyID, restriction, s.accessLifetime())
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	rt := token.NewRefreshToken(u.ID, familyID, hashOpaqueToken(raw), refreshExpiresAt)
+	if err := s.refreshTokens.Create(rt); err != nil {
+		return nil, err
+	}
+
+	return &dto.TokenResponse{
+		Token:            accessToken,
+		ExpiresAt:        expiresAt.Unix(),
+		RefreshToken:     raw,
+		Restriction:      restriction,
+		RefreshExpiresAt: refreshExpiresAt.Unix(),
+	}, nil
+}
+
+// IssueMFAChallenge signs a short-lived token, without a refresh token, that
+// only the second-factor verification endpoint accepts
+func (s *TokenService) IssueMFAChallenge(u *user.User) (*dto.TokenResponse, error) {
+	accessToken, expiresAt, err := s.issueAccess(u, nil, time.Now(), "", RestrictionMFAPending, mfaChallengeLifetime)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TokenResponse{
+		Token:       accessToken,
+		ExpiresAt:   expiresAt.Unix(),
+		Restriction: RestrictionMFAPending,
+	}, nil
+}
+
+// IssueImpersonation signs an access token for u carrying actor as its "act"
+// claim. There is no refresh token: the impersonation ends after the
+// impersonation lifetime at the latest. It gets a session of u of its own,
+// marked with the actor, so it shows in the user's session list and can be
+// stopped like any other.
+func (s *TokenService) IssueImpersonation(u *user.User, actor *Actor, client ClientInfo) (*dto.TokenResponse, error) {
+	now := time.Now()
+	lifetime := s.impersonationLifetime
+	sessionID := uuid.NewString()
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	sess := session.NewSession(u.ID, sessionID, userAgent, client.IP, now, now.Add(lifetime))
+	sess.ImpersonatorID = actor.UserID
+	if err := s.sessions.Create(sess); err != nil {
+		return nil, err
+	}
+
+	accessToken, expiresAt, err := s.issueAccess(u, actor, now, sessionID, "", lifetime)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TokenResponse{Token: accessToken, ExpiresAt: expiresAt.Unix()}, nil
+}
+
+// ConsumeRefreshToken validates and rotates out a refresh token.
+// Presenting an already rotated token revokes its whole family.
+func (s *TokenService) ConsumeRefreshToken(ctx context.Context, raw string) (*token.RefreshToken, error) {
+	rt, err := s.refreshTokens.FindByHash(hashOpaqueToken(raw))
+	if err != nil {
+		if errors.Is(err, token.ErrRefreshTokenNotFound) {
+			return nil, errcode.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if rt.IsRevoked() {
+		// The session was ended on purpose; a device that was signed out
+		// remotely will still try its token, which is not a leak
+		return nil, errcode.ErrTokenRevoked
+	}
+	if rt.IsConsumed() {
+		s.handleReuse(ctx, rt)
+		return nil, errcode.ErrTokenRevoked
+	}
+	if rt.IsExpired(now) {
+		return nil, errcode.ErrTokenExpired
+	}
+
+	ok, err := s.refreshTokens.MarkUsed(rt.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// Lost a race against another request presenting the same token
+		s.handleReuse(ctx, rt)
+		return nil, errcode.ErrTokenRevoked
+	}
+	return rt, nil
+}
+
+// Parse validates the access token signature, issuer, audience, expiry and
+// revocation state (of the token, of its session and, when impersonating, of
+// the administrator's tokens), and marks the session as seen
+func (s *TokenService) Parse(ctx context.Context, tokenStr string) (*Claims, error) {
+	claims := &Claims{}
+	t, err := s.parser.ParseWithClaims(tokenStr, claims, s.verificationKey)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errcode.ErrTokenExpired
+		}
+		return nil, errcode.ErrInvalidToken
+	}
+	if !t.Valid || claims.ID == "" || claims.IssuedAt == nil {
+		return nil, errcode.ErrInvalidToken
+	}
+
+	issuedAt := exactIssuedAt(tokenStr, claims.IssuedAt.Time)
+	if s.blacklist.IsBlacklisted(ctx, claims.ID) ||
+		s.blacklist.IsRevokedBefore(ctx, claims.Subject, issuedAt) {
+		return nil, errcode.ErrTokenRevoked
+	}
+	// Signing the administrator out everywhere (or disabling them) also ends
+	// their impersonations
+	if claims.Actor != nil && s.blacklist.IsRevokedBefore(ctx, claims.Actor.Subject, issuedAt) {
+		return nil, errcode.ErrTokenRevoked
+	}
+	if claims.SessionID != "" {
+		if s.blacklist.IsBlacklisted(ctx, sessionRevocationPrefix+claims.SessionID) {
+			return nil, errcode.ErrTokenRevoked
+		}
+		if err := s.sessions.Touch(claims.SessionID, time.Now(), sessionTouchInterval); err != nil {
+			logger.Warnf("failed to update session last seen sid=%s: %v", claims.SessionID, err)
+		}
+	}
+	return claims, nil
+}
+
+// Revoke blacklists a single access token until it would have expired anyway
+func (s *TokenService) Revoke(ctx context.Context, claims *Claims) error {
+	ttl := time.Until(claims.ExpiresAt.Time)
+	return s.blacklist.Add(ctx, claims.ID, ttl)
+}
+
+// RevokeRefreshToken ends the session of a raw refresh token owned by the user
+func (s *TokenService) RevokeRefreshToken(ctx context.Context, raw string, userID uint) error {
+	rt, err := s.refreshTokens.FindByHash(hashOpaqueToken(raw))
+	if err != nil {
+		if errors.Is(err, token.ErrRefreshTokenNotFound) {
+			return nil
+		}
+		return err
+	}
+	if rt.UserID != userID {
+		return nil
+	}
+	return s.RevokeSession(ctx, rt.FamilyID)
+}
+
+// RevokeSession ends a session: its refresh family is revoked and access
+// tokens carrying its ID are rejected from now on
+func (s *TokenService) RevokeSession(ctx context.Context, familyID string) error {
+	now := time.Now()
+	if err := s.refreshTokens.RevokeFamily(familyID, now); err != nil {
+		return err
+	}
+	if err := s.sessions.Revoke(familyID, now); err != nil {
+		return err
+	}
+	return s.blacklist.Add(ctx, sessionRevocationPrefix+familyID, s.revocationLifetime())
+}
+
+// RevokeAll ends every session and invalidates every access and refresh token
+// issued to the user up to now
+func (s *TokenService) RevokeAll(ctx context.Context, userID uint) error {
+	now := time.Now()
+	if err := s.refreshTokens.RevokeByUser(userID, now); err != nil {
+		return err
+	}
+	if err := s.sessions.RevokeByUser(userID, now); err != nil {
+		return err
+	}
+	return s.RevokeAccessTokens(ctx, userID)
+}
+
+// RevokeAccessTokens invalidates the user's outstanding access tokens but keeps
+// refresh tokens, so clients pick up changed claims (e.g. role) on next refresh
+func (s *TokenService) RevokeAccessTokens(ctx context.Context, userID uint) error {
+	subject := strconv.FormatUint(uint64(userID), 10)
+	return s.blacklist.RevokeBefore(ctx, subject, time.Now(), s.revocationLifetime())
+}
+
+// handleReuse revokes a leaked family together with the user's outstanding access tokens
+func (s *TokenService) handleReuse(ctx context.Context, rt *token.RefreshToken) {
+	logger.Warnf("refresh token reuse detected user_id=%d family=%s", rt.UserID, rt.FamilyID)
+	auditActor(ctx, 0, rt.UserID, "")
+	auditEvent(ctx, audit.ActionRefreshTokenReuse)
+	if err := s.RevokeSession(ctx, rt.FamilyID); err != nil {
+		logger.Errorf("failed to revoke refresh token family %s: %v", rt.FamilyID, err)
+	}
+	subject := strconv.FormatUint(uint64(rt.UserID), 10)
+	if err := s.blacklist.RevokeBefore(ctx, subject, time.Now(), s.revocationLifetime()); err != nil {
+		logger.Errorf("failed to revoke access tokens user_id=%d: %v", rt.UserID, err)
+	}
+}
+
+func (s *TokenService) issueAccess(u *user.User, actor *Actor, now time.Time, sessionID, restriction string, lifetime time.Duration) (string, time.Time, error) {
+	expiresAt := now.Add(lifetime)
+	claims := &Claims{
+		UserID:      u.ID,
+		Username:    u.Username,
+		Role:        string(u.Role),
+		TenantID:    u.TenantID,
+		SuperAdmin:  u.SuperAdmin,
+		Restriction: restriction,
+		SessionID:   sessionID,
+		Actor:       actor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   strconv.FormatUint(uint64(u.ID), 10),
+			Issuer:    s.cfg.Issuer,
+			Audience:  jwt.ClaimStrings{s.cfg.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	key := s.keys.Signing()
+	t := jwt.NewWithClaims(key.Method, claims)
+	t.Header["kid"] = key.ID
+	tokenStr, err := t.SignedString(key.Private)
+	return tokenStr, expiresAt, err
+}
+
+// verificationKey selects the key named by the kid header. The algorithm must
+// be the one that key was issued for, so a public key can never be used as an
+// HMAC secret.
+func (s *TokenService) verificationKey(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	key, ok := s.keys.Lookup(kid)
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.Public, nil
+}
+
+// exactIssuedAt re-reads iat from a verified token's payload. jwt decodes
+// NumericDate through a float64, so .123 may come back as .122999… and be
+// truncated a millisecond early, making a token issued in the same millisecond
+// as a revocation watermark look older than it.
+func exactIssuedAt(tokenStr string, fallback time.Time) time.Time {
+	parts := strings.Split(tokenStr, ".")
+	if len(parts) != 3 {
+		return fallback
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fallback
+	}
+	var raw struct {
+		IssuedAt json.Number `json:"iat"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return fallback
+	}
+
+	secStr, fracStr, _ := strings.Cut(raw.IssuedAt.String(), ".")
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return fallback
+	}
+	ms, err := strconv.ParseInt((fracStr + "000")[:3], 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return time.UnixMilli(sec*1000 + ms)
+}
+
+func (s *TokenService) accessLifetime() time.Duration {
+	return time.Duration(s.cfg.AccessMinutes) * time.Minute
+}
+
+// revocationLifetime is how long a session or watermark revocation must be
+// kept: until every token it covers has expired, impersonation tokens
+// included, which may outlive ordinary access tokens
+func (s *TokenService) revocationLifetime() time.Duration {
+	return max(s.accessLifetime(), s.impersonationLifetime)
+}
+
+// newOpaqueToken returns 32 random bytes, base64url encoded
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashOpaqueToken returns the hex SHA-256 of a raw token for storage and lookup
+func hashOpaqueToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,231 @@
+package service_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/container"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/pkg/cache/redistest"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/errcode"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newImpersonationTest wires a container whose revocations live in an
+// in-process Redis, so the test can move past the access token lifetime,
+// and returns an impersonation token of a fresh user issued to the admin
+func newImpersonationTest(t *testing.T) (*container.Container, *redistest.Server, string, *service.Claims) {
+	t.Helper()
+	redis, err := redistest.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(redis.Close)
+	c := newTestContainer(t, func(cfg *config.Config) {
+		cfg.Cache.Driver = "redis"
+		cfg.Cache.Redis.Addrs = []string{redis.Addr()}
+		cfg.JWT.AccessMinutes = 15
+		cfg.Security.ImpersonationMinutes = 30
+	})
+
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+	target, err := c.UserService.Create(ctx, &dto.CreateUserRequest{Username: "dave", Password: "Zq8#vLm2!pT9x"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	admin := &service.Claims{UserID: 1, Username: "admin", TenantID: tenant.DefaultID}
+	resp, err := c.ImpersonationService.Start(ctx, admin, target.ID, service.ClientInfo{IP: "192.0.2.20"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	claims, err := c.TokenService.Parse(ctx, resp.Token)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// Watermarks have millisecond precision and spare tokens issued in the same millisecond
+	time.Sleep(2 * time.Millisecond)
+	return c, redis, resp.Token, claims
+}
+
+func TestRevokedImpersonationSessionStaysRevoked(t *testing.T) {
+	c, redis, raw, claims := newImpersonationTest(t)
+	ctx := context.Background()
+
+	if err := c.TokenService.RevokeSession(ctx, claims.SessionID); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+	// Past the access token lifetime but within the impersonation's
+	redis.FastForward(20 * time.Minute)
+	_, err := c.TokenService.Parse(ctx, raw)
+	requireCode(t, err, errcode.ErrTokenRevoked)
+}
+
+func TestSigningTheAdminOutEndsImpersonationForItsLifetime(t *testing.T) {
+	c, redis, raw, _ := newImpersonationTest(t)
+	ctx := context.Background()
+
+	if err := c.TokenService.RevokeAll(ctx, 1); err != nil {
+		t.Fatalf("RevokeAll: %v", err)
+	}
+	redis.FastForward(20 * time.Minute)
+	_, err := c.TokenService.Parse(ctx, raw)
+	requireCode(t, err, errcode.ErrTokenRevoked)
+}
+
+// signingKeyFiles writes a key pair as PKCS#8 and PKIX PEM files
+func signingKeyFiles(t *testing.T, key crypto.Signer) (privateFile, publicFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateFile, publicFile = filepath.Join(dir, "key.pem"), filepath.Join(dir, "pub.pem")
+	if err := os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privateFile, publicFile
+}
+
+// accessToken signs in a fresh user and returns the access token
+func accessToken(t *testing.T, c *container.Container, username string) string {
+	t.Helper()
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+	if _, err := c.UserService.Create(ctx, &dto.CreateUserRequest{Username: username, Password: testPassword}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.AuthService.Login(context.Background(), &dto.LoginRequest{Username: username, Password: testPassword}, service.ClientInfo{IP: testClientIP})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return resp.Token
+}
+
+// forge signs claims with any method, kid and key
+func forge(t *testing.T, claims *service.Claims, method jwt.SigningMethod, kid string, key any) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		tok.Header["kid"] = kid
+	}
+	raw, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestParseChecksKeyIDAndAlgorithm(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPrivate, rsaPublic := signingKeyFiles(t, rsaKey)
+	_, ecPublic := signingKeyFiles(t, ecKey)
+	c := newTestContainer(t, func(cfg *config.Config) {
+		cfg.JWT.Keys = []config.JWTKeyConfig{
+			{ID: "rs", PrivateKeyFile: rsaPrivate},
+			{ID: "es", PublicKeyFile: ecPublic},
+		}
+	})
+	ctx := context.Background()
+
+	claims, err := c.TokenService.Parse(ctx, accessToken(t, c, "lena"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// The forged tokens below differ from a valid one only in how they are signed
+	if _, err := c.TokenService.Parse(ctx, forge(t, claims, jwt.SigningMethodRS256, "rs", rsaKey)); err != nil {
+		t.Fatalf("Parse re-signed token: %v", err)
+	}
+
+	publicPEM, err := os.ReadFile(rsaPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, raw := range map[string]string{
+		"unknown kid": forge(t, claims, jwt.SigningMethodRS256, "other", rsaKey),
+		"missing kid": forge(t, claims, jwt.SigningMethodRS256, "", rsaKey),
+		// The public key is no secret: using it as an HMAC key must not verify
+		"hs256 with the public key": forge(t, claims, jwt.SigningMethodHS256, "rs", publicPEM),
+		// Another key's algorithm under this key's kid
+		"es256 under an rs256 kid": forge(t, claims, jwt.SigningMethodES256, "rs", ecKey),
+		"none":                     forge(t, claims, jwt.SigningMethodNone, "rs", jwt.UnsafeAllowNoneSignatureType),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.TokenService.Parse(ctx, raw)
+			requireCode(t, err, errcode.ErrInvalidToken)
+		})
+	}
+}
+
+func TestParseAcrossKeyRotation(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldPrivate, oldPublic := signingKeyFiles(t, oldKey)
+	newPrivate, _ := signingKeyFiles(t, newKey)
+	dbPath := filepath.Join(t.TempDir(), "rotation.db")
+	withKeys := func(keys ...config.JWTKeyConfig) *container.Container {
+		return newTestContainer(t, func(cfg *config.Config) {
+			cfg.Database.Path = dbPath
+			cfg.JWT.Keys = keys
+		})
+	}
+	ctx := context.Background()
+
+	before := withKeys(config.JWTKeyConfig{ID: "2026-01", PrivateKeyFile: oldPrivate})
+	raw := accessToken(t, before, "mona")
+
+	// The new key signs; the old one is kept to verify tokens still in flight
+	during := withKeys(config.JWTKeyConfig{ID: "2026-07", PrivateKeyFile: newPrivate}, config.JWTKeyConfig{ID: "2026-01", PublicKeyFile: oldPublic})
+	if _, err := during.TokenService.Parse(ctx, raw); err != nil {
+		t.Fatalf("Parse with the retired key: %v", err)
+	}
+	fresh := accessToken(t, during, "nina")
+	tok, _, err := jwt.NewParser().ParseUnverified(fresh, jwt.MapClaims{})
+	if err != nil || tok.Header["kid"] != "2026-07" {
+		t.Fatalf("new token has kid %v, want 2026-07", tok.Header["kid"])
+	}
+	if jwks := during.TokenService.JWKS(); len(jwks.Keys) != 2 {
+		t.Fatalf("JWKS has %d keys during rotation, want 2", len(jwks.Keys))
+	}
+
+	// Once the old key is dropped its tokens are no longer accepted
+	after := withKeys(config.JWTKeyConfig{ID: "2026-07", PrivateKeyFile: newPrivate})
+	_, err = after.TokenService.Parse(ctx, raw)
+	requireCode(t, err, errcode.ErrInvalidToken)
+	if _, err := after.TokenService.Parse(ctx, fresh); err != nil {
+		t.Fatalf("Parse with the new key: %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/domain/apikey"
+	"go-ddd-scaffold/internal/domain/passkey"
+	"go-ddd-scaffold/internal/domain/rbac"
+	"go-ddd-scaffold/internal/domain/user"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+)
+
+// UserAppService orchestrates user administration
+type UserAppService struct {
+	repo       user.Repository
+	roles      rbac.Repository
+	tokens     *TokenService
+	passwords  *PasswordService
+	mfa        *MFAAppService
+	keys       apikey.Repository
+	passkeys   passkey.Repository
+	identities user.IdentityRepository
+	emails     *EmailAppService
+}
+
+// NewUserAppService creates a new application service
+func NewUserAppService(repo user.Repository, roles rbac.Repository, tokens *TokenService, passwords *PasswordService, mfa *MFAAppService, keys apikey.Repository, passkeys passkey.Repository, identities user.IdentityRepository, emails *EmailAppService) *UserAppService {
+	return &UserAppService{repo: repo, roles: roles, tokens: tokens, passwords: passwords, mfa: mfa, keys: keys, passkeys: passkeys, identities: identities, emails: emails}
+}
+
+// Create creates a new user and mails a verification link to their address
+func (s *UserAppService) Create(ctx context.Context, req *dto.CreateUserRequest) (*dto.UserResponse, error) {
+	if err := requireTenant(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindByUsername(ctx, req.Username); err == nil {
+		return nil, errcode.ErrAccountExists
+	} else if !errors.Is(err, user.ErrUserNotFound) {
+		return nil, err
+	}
+
+	role := user.Role(req.Role)
+	if role == "" {
+		role = user.RoleUser
+	}
+	if err := s.checkRole(role); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.passwords.Hash(ctx, req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := user.NewUser(req.Username, hash, role)
+	entity.UpdateProfile(req.Nickname, req.Email)
+	if err := s.repo.Save(ctx, entity); err != nil {
+		return nil, err
+	}
+	if err := s.passwords.Remember(entity); err != nil {
+		return nil, err
+	}
+	s.verifyEmail(ctx, entity)
+
+	resp := dto.FromUser(entity)
+	auditChange(ctx, entity.ID, nil, resp)
+	return resp, nil
+}
+
+// CreateServiceAccount creates a machine identity that cannot sign in with a
+// password; administrators issue API keys for it instead
+func (s *UserAppService) CreateServiceAccount(ctx context.Context, req *dto.CreateServiceAccountRequest) (*dto.UserResponse, error) {
+	if err := requireTenant(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindByUsername(ctx, req.Username); err == nil {
+		return nil, errcode.ErrAccountExists
+	} else if !errors.Is(err, user.ErrUserNotFound) {
+		return nil, err
+	}
+
+	role := user.Role(req.Role)
+	if role == "" {
+		role = user.RoleUser
+	}
+	if err := s.checkRole(role); err != nil {
+		return nil, err
+	}
+
+	entity := user.NewServiceAccount(req.Username, role)
+	entity.UpdateProfile(req.Nickname, "")
+	if err := s.repo.Save(ctx, entity); err != nil {
+		return nil, err
+	}
+
+	resp := dto.FromUser(entity)
+	auditChange(ctx, entity.ID, nil, resp)
+	return resp, nil
+}
+
+// GetByID returns a user by ID
+func (s *UserAppService) GetByID(ctx context.Context, id uint) (*dto.UserResponse, error) {
+	entity, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromUser(entity), nil
+}
+
+// List returns paginated users
+func (s *UserAppService) List(ctx context.Context, req *dto.QueryUserRequest) ([]*dto.UserResponse, int64, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	entities, total, err := s.repo.List(ctx, req.Page, req.PageSize, req.Keyword, user.Status(req.Status))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return dto.FromUserList(entities), total, nil
+}
+
+// Update updates a user's profile; a changed email address is verified again
+func (s *UserAppService) Update(ctx context.Context, id uint, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	entity, err := s.findManaged(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := dto.FromUser(entity)
+
+	nickname, email := "", ""
+	if req.Nickname != nil {
+		nickname = *req.Nickname
+	}
+	if req.Email != nil {
+		email = *req.Email
+	}
+	entity.UpdateProfile(nickname, email)
+
+	if err := s.repo.Save(ctx, entity); err != nil {
+		return nil, err
+	}
+	if entity.Email != before.Email {
+		s.verifyEmail(ctx, entity)
+	}
+
+	resp := dto.FromUser(entity)
+	auditChange(ctx, entity.ID, before, resp)
+	return resp, nil
+}
+
+// ResetPassword sets a temporary password that the user must change at next
+// login, and signs the user out everywhere
+func (s *UserAppService) ResetPassword(ctx context.Context, id uint, req *dto.ResetPasswordRequest) error {
+	entity, err := s.findManaged(ctx, id)
+	if err != nil {
+		return err
+	}
+	if entity.ServiceAccount {
+		return errcode.ErrInvalidParams.WithMessage("service accounts have no password")
+	}
+	return s.passwords.Reset(ctx, entity, req.Password)
+}
+
+// ResetMFA removes the user's second factor (e.g. a lost device). If the
+// user's role requires 2FA they are asked to enroll again at next login.
+func (s *UserAppService) ResetMFA(ctx context.Context, id uint) error {
+	if _, err := s.findManaged(ctx, id); err != nil {
+		return err
+	}
+	return s.mfa.Reset(id)
+}
+
+// Disable blocks the user from signing in and revokes all of their tokens
+func (s *UserAppService) Disable(ctx context.Context, actorID, id uint) error {
+	if actorID == id {
+		return errcode.ErrInvalidParams.WithMessage("cannot disable your own account")
+	}
+	entity, err := s.findManaged(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !entity.IsActive() {
+		return nil
+	}
+
+	before := dto.FromUser(entity)
+	entity.Disable()
+	if err := s.repo.Save(ctx, entity); err != nil {
+		return err
+	}
+	auditChange(ctx, entity.ID, before, dto.FromUser(entity))
+	return s.tokens.RevokeAll(ctx, entity.ID)
+}
+
+// Enable allows a disabled user to sign in again
+func (s *UserAppService) Enable(ctx context.Context, id uint) error {
+	entity, err := s.findManaged(ctx, id)
+	if err != nil {
+		return err
+	}
+	if entity.IsActive() {
+		return nil
+	}
+
+	before := dto.FromUser(entity)
+	entity.Enable()
+	if err := s.repo.Save(ctx, entity); err != nil {
+		return err
+	}
+	auditChange(ctx, entity.ID, before, dto.FromUser(entity))
+	return nil
+}
+
+// Delete deletes a user, unlinks their SSO identities and revokes all of their
+// tokens, API keys and passkeys. Every way to sign in is revoked before the
+// user row goes, and the first error aborts: a failure midway leaves a user
+// that can be deleted again, never credentials that outlive their owner.
+func (s *UserAppService) Delete(ctx context.Context, actorID, id uint) error {
+	if actorID == id {
+		return errcode.ErrInvalidParams.WithMessage("cannot delete your own account")
+	}
+	entity, err := s.findManaged(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokens.RevokeAll(ctx, id); err != nil {
+		return err
+	}
+	if err := s.keys.DeleteByUser(id); err != nil {
+		return err
+	}
+	if err := s.passkeys.DeleteByUser(id); err != nil {
+		return err
+	}
+	if err := s.identities.DeleteByUser(id); err != nil {
+		return err
+	}
+	if err := s.emails.Forget(id); err != nil {
+		return err
+	}
+	if err := s.mfa.Reset(id); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	auditChange(ctx, id, dto.FromUser(entity), nil)
+	return s.passwords.Forget(id)
+}
+
+// verifyEmail mails a verification link; the user was saved either way, so
+// a failure is only logged and the link can be requested again
+func (s *UserAppService) verifyEmail(ctx context.Context, u *user.User) {
+	if err := s.emails.SendVerification(ctx, u); err != nil {
+		logger.Warnf("failed to send verification mail to user %d: %v", u.ID, err)
+	}
+}
+
+func (s *UserAppService) find(ctx context.Context, id uint) (*user.User, error) {
+	entity, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, errcode.ErrAccountNotFound
+		}
+		return nil, err
+	}
+	return entity, nil
+}
+
+// findManaged loads a user the caller may administer. Super-admin accounts
+// can only be changed by super-admins, so an administrator of their tenant
+// cannot take one over.
+func (s *UserAppService) findManaged(ctx context.Context, id uint) (*user.User, error) {
+	entity, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entity.SuperAdmin && !isSuperAdmin(ctx) {
+		return nil, errcode.ErrSuperAdminRequired
+	}
+	return entity, nil
+}
+
+func (s *UserAppService) checkRole(role user.Role) error {
+	if _, err := s.roles.FindRoleByCode(string(role)); err != nil {
+		if errors.Is(err, rbac.ErrRoleNotFound) {
+			return errcode.ErrRoleNotFound
+		}
+		return err
+	}
+	return nil
+}
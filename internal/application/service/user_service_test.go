@@ -0,0 +1,44 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/domain/tenant"
+)
+
+func TestDeleteUserRevokesEveryCredential(t *testing.T) {
+	c := newTestContainer(t, nil)
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+
+	u, err := c.UserService.Create(ctx, &dto.CreateUserRequest{Username: "ivan", Password: "Zq8#vLm2!pT9x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := c.APIKeyService.Create(ctx, u.ID, &dto.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"example:read"}})
+	if err != nil {
+		t.Fatalf("Create API key: %v", err)
+	}
+	session, err := c.AuthService.Login(context.Background(), &dto.LoginRequest{Username: "ivan", Password: "Zq8#vLm2!pT9x"}, service.ClientInfo{IP: "192.0.2.40"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	// Watermarks have millisecond precision and spare tokens issued in the same millisecond
+	time.Sleep(2 * time.Millisecond)
+
+	if err := c.UserService.Delete(ctx, 0, u.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := c.APIKeyService.Authenticate(context.Background(), key.Key); err == nil {
+		t.Fatal("the deleted user's API key still authenticates")
+	}
+	if _, err := c.TokenService.Parse(context.Background(), session.Token); err == nil {
+		t.Fatal("the deleted user's access token is still accepted")
+	}
+	if _, err := c.AuthService.Refresh(context.Background(), &dto.RefreshTokenRequest{RefreshToken: session.RefreshToken}); err == nil {
+		t.Fatal("the deleted user's refresh token still works")
+	}
+}
@@ -1,20 +1,60 @@
 package container
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/domain/audit"
+	"go-ddd-scaffold/internal/domain/example"
+	"go-ddd-scaffold/internal/domain/rbac"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
 	"go-ddd-scaffold/internal/infrastructure/persistence/database"
+	"go-ddd-scaffold/pkg/cache"
+	"go-ddd-scaffold/pkg/captcha"
 	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/directory"
+	"go-ddd-scaffold/pkg/jwtkeys"
+	"go-ddd-scaffold/pkg/lockout"
 	"go-ddd-scaffold/pkg/logger"
+	"go-ddd-scaffold/pkg/mailer"
+	"go-ddd-scaffold/pkg/password"
+	"go-ddd-scaffold/pkg/tokenblacklist"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
 )
 
 // Container manages dependency injection
 // In DDD architecture, Container assembles cross-layer dependencies
 type Container struct {
-	Config *config.Config
-	DB     *database.DB
+	Config    *config.Config
+	DB        *database.DB
+	Cache     cache.Cache
+	Blacklist tokenblacklist.Blacklist
 
 	// Application services
-	ExampleService *service.ExampleAppService
+	AuthService          *service.AuthAppService
+	TokenService         *service.TokenService
+	MFAService           *service.MFAAppService
+	PasskeyService       *service.PasskeyAppService
+	APIKeyService        *service.APIKeyAppService
+	SessionService       *service.SessionAppService
+	ImpersonationService *service.ImpersonationAppService
+	TenantService        *service.TenantAppService
+	AuditService         *service.AuditAppService
+	EmailService         *service.EmailAppService
+	SSOService           *service.SSOAppService
+	SCIMService          *service.SCIMAppService
+	RBACService          *service.RBACAppService
+	UserService          *service.UserAppService
+	ExampleService       *service.ExampleAppService
 	// GEN:SERVICE_REGISTER - Code generator appends services here, do not remove
 }
 
@@ -29,31 +69,369 @@ func New(cfg *config.Config) (*Container, error) {
 	}
 	c.DB = db
 
+	c.Cache, err = newCache(&cfg.Cache)
+	if err != nil {
+		return nil, err
+	}
+	c.Blacklist = tokenblacklist.New(c.Cache)
+
 	// 2. Auto-migrate
 	if err := db.AutoMigrate(
+		&database.TenantModel{},
 		&database.UserModel{},
+		&database.RefreshTokenModel{},
+		&database.ActionTokenModel{},
+		&database.SessionModel{},
+		&database.PasswordHistoryModel{},
+		&database.TOTPCredentialModel{},
+		&database.RecoveryCodeModel{},
+		&database.PasskeyModel{},
+		&database.APIKeyModel{},
+		&database.UserIdentityModel{},
+		&database.RoleModel{},
+		&database.PermissionModel{},
+		&database.AuditLogModel{},
 		&database.ExampleModel{},
 		// GEN:MODEL_MIGRATE - Code generator appends models here, do not remove
 	); err != nil {
 		return nil, err
 	}
 
-	// Seed default admin user
-	database.EnsureDefaultAdmin(db.GormDB())
+	hasher, err := newPasswordHasher(&cfg.Security.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Seed default tenant and admin user
+	database.EnsureDefaultTenant(db.GormDB())
+	database.EnsureDefaultAdmin(db.GormDB(), hasher)
 
 	// 3. Create repositories (infra -> domain interface)
+	tenantRepo := database.NewTenantRepository(db)
+	userRepo := database.NewUserRepository(db)
+	refreshTokenRepo := database.NewRefreshTokenRepository(db)
+	actionTokenRepo := database.NewActionTokenRepository(db)
+	sessionRepo := database.NewSessionRepository(db)
+	passwordHistoryRepo := database.NewPasswordHistoryRepository(db)
+	mfaRepo := database.NewMFARepository(db)
+	passkeyRepo := database.NewPasskeyRepository(db)
+	apiKeyRepo := database.NewAPIKeyRepository(db)
+	identityRepo := database.NewUserIdentityRepository(db)
+	rbacRepo := database.NewRBACRepository(db)
+	auditRepo := database.NewAuditRepository(db)
 	exampleRepo := database.NewExampleRepository(db)
 
 	// 4. Create application services (inject repos)
+	signingKeys, err := loadSigningKeys(&cfg.JWT)
+	if err != nil {
+		return nil, err
+	}
+	c.TokenService = service.NewTokenService(&cfg.JWT, signingKeys, c.Blacklist, refreshTokenRepo, sessionRepo,
+		time.Duration(cfg.Security.ImpersonationMinutes)*time.Minute)
+	policy, err := newPasswordPolicy(&cfg.Password)
+	if err != nil {
+		return nil, err
+	}
+	passwords := service.NewPasswordService(userRepo, passwordHistoryRepo, c.TokenService,
+		hasher, policy, cfg.Security.PasswordHistory)
+	throttle := service.NewLoginThrottle(tenantRepo,
+		newLoginLockout(c.Cache, "account:", cfg.Security.Login.MaxAccountFailures, &cfg.Security.Login),
+		newLoginLockout(c.Cache, "ip:", cfg.Security.Login.MaxIPFailures, &cfg.Security.Login),
+	)
+	c.MFAService = service.NewMFAAppService(mfaRepo, userRepo, rbacRepo, throttle, cfg.App.Name)
+	c.RBACService = service.NewRBACAppService(rbacRepo, userRepo, c.TokenService, c.Cache)
+	authenticator, err := newAuthenticator(cfg, userRepo, tenantRepo, identityRepo, passwords, c.RBACService)
+	if err != nil {
+		return nil, err
+	}
+	c.AuthService = service.NewAuthAppService(userRepo, tenantRepo, c.TokenService, passwords, c.MFAService, authenticator, throttle,
+		captcha.New(c.Cache, captcha.Options{TTL: time.Duration(cfg.Security.Login.CaptchaTTL) * time.Second}),
+		cfg.Security.Login.CaptchaAfter,
+	)
+	relyingParty, err := newRelyingParty(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.PasskeyService = service.NewPasskeyAppService(relyingParty, passkeyRepo, userRepo, c.AuthService, c.Cache)
+	mail, templates, err := newMailer(&cfg.Mail)
+	if err != nil {
+		return nil, err
+	}
+	c.EmailService = service.NewEmailAppService(userRepo, tenantRepo, actionTokenRepo, passwords, mail, templates, c.Cache,
+		cfg.App.Name, cfg.Mail.LinkBaseURL,
+		time.Duration(cfg.Security.EmailVerificationHours)*time.Hour,
+		time.Duration(cfg.Security.PasswordResetMinutes)*time.Minute,
+	)
+	c.TenantService = service.NewTenantAppService(tenantRepo, userRepo, passwords)
+	c.AuditService = service.NewAuditAppService(auditRepo)
+	c.SessionService = service.NewSessionAppService(sessionRepo, userRepo, c.TokenService)
+	c.ImpersonationService = service.NewImpersonationAppService(userRepo, c.RBACService, c.TokenService)
+	c.APIKeyService = service.NewAPIKeyAppService(apiKeyRepo, userRepo, c.RBACService)
+	c.SSOService = service.NewSSOAppService(&cfg.SSO, userRepo, identityRepo, c.RBACService, c.AuthService, c.Cache)
+	c.SCIMService = service.NewSCIMAppService(&cfg.SCIM, userRepo, identityRepo, rbacRepo, tenantRepo, passwords, c.TokenService, c.RBACService)
+	c.UserService = service.NewUserAppService(userRepo, rbacRepo, c.TokenService, passwords, c.MFAService, apiKeyRepo, passkeyRepo, identityRepo, c.EmailService)
 	c.ExampleService = service.NewExampleAppService(exampleRepo)
 	// GEN:SERVICE_INIT - Code generator appends initialization here, do not remove
 
+	// 5. Seed permissions declared by modules
+	if err := c.RBACService.SyncPermissions(
+		rbac.Permissions,
+		user.Permissions,
+		audit.Permissions,
+		example.Permissions,
+		// GEN:PERMISSION_REGISTER - Code generator appends permissions here, do not remove
+	); err != nil {
+		return nil, err
+	}
+
 	logger.Info("DI container initialized")
 	return c, nil
 }
 
-// Close releases all resources
+// newLoginLockout builds a lockout manager for one login throttling dimension
+func newLoginLockout(c cache.Cache, scope string, threshold int, cfg *config.LoginSecurityConfig) *lockout.Manager {
+	return lockout.NewWithOptions(c, lockout.Options{
+		Scope:       scope,
+		Threshold:   threshold,
+		Window:      time.Duration(cfg.FailureWindow) * time.Minute,
+		Duration:    time.Duration(cfg.LockoutMinutes) * time.Minute,
+		MaxDuration: time.Duration(cfg.MaxLockoutMinutes) * time.Minute,
+	})
+}
+
+// newAuthenticator chains the password login backends in the configured order
+func newAuthenticator(cfg *config.Config, users user.Repository, tenants tenant.Repository, identities user.IdentityRepository, passwords *service.PasswordService, rbacSvc *service.RBACAppService) (service.Authenticator, error) {
+	var chain service.Authenticators
+	for _, name := range cfg.Security.Authenticators {
+		switch name {
+		case "local":
+			chain = append(chain, service.NewLocalAuthenticator(users, tenants, passwords))
+		case "ldap":
+			dir, err := newDirectory(&cfg.LDAP)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, service.NewLDAPAuthenticator(&cfg.LDAP, dir, users, identities, rbacSvc))
+		}
+	}
+	return chain, nil
+}
+
+// newDirectory builds the LDAP client and its TLS settings
+func newDirectory(cfg *config.LDAPConfig) (*directory.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("ldap.ca_file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ldap.ca_file: no certificates found in %s", cfg.CAFile)
+		}
+	}
+	dir, err := directory.New(directory.Config{
+		URL:               cfg.URL,
+		StartTLS:          cfg.StartTLS,
+		TLS:               tlsConfig,
+		Timeout:           time.Duration(cfg.Timeout) * time.Second,
+		BindDN:            cfg.BindDN,
+		BindPassword:      cfg.BindPassword,
+		BaseDN:            cfg.BaseDN,
+		UserFilter:        cfg.UserFilter,
+		UsernameAttribute: cfg.UsernameAttribute,
+		NameAttribute:     cfg.NameAttribute,
+		EmailAttribute:    cfg.EmailAttribute,
+		GroupAttribute:    cfg.GroupAttribute,
+		GroupBaseDN:       cfg.GroupBaseDN,
+		GroupFilter:       cfg.GroupFilter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ldap: %w", err)
+	}
+	return dir, nil
+}
+
+// newRelyingParty builds the WebAuthn relying party, or nil when passkeys are disabled
+func newRelyingParty(cfg *config.Config) (*webauthn.WebAuthn, error) {
+	if !cfg.WebAuthn.Enabled {
+		return nil, nil
+	}
+	displayName := cfg.WebAuthn.RPDisplayName
+	if displayName == "" {
+		displayName = cfg.App.Name
+	}
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    time.Duration(cfg.WebAuthn.Timeout) * time.Second,
+		TimeoutUVD: time.Duration(cfg.WebAuthn.Timeout) * time.Second,
+	}
+	rp, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.WebAuthn.RPID,
+		RPDisplayName: displayName,
+		RPOrigins:     cfg.WebAuthn.RPOrigins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.UserVerificationRequirement(cfg.WebAuthn.UserVerification),
+		},
+		Timeouts: webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("webauthn: %w", err)
+	}
+	return rp, nil
+}
+
+// newMailer builds the configured mail transport and the message templates
+func newMailer(cfg *config.MailConfig) (mailer.Mailer, *mailer.Templates, error) {
+	templates, err := mailer.LoadTemplates(cfg.TemplateDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("mail.template_dir: %w", err)
+	}
+	var m mailer.Mailer
+	switch cfg.Driver {
+	case "smtp":
+		m, err = mailer.NewSMTP(mailer.SMTPConfig{
+			Host:       cfg.SMTP.Host,
+			Port:       cfg.SMTP.Port,
+			Username:   cfg.SMTP.Username,
+			Password:   cfg.SMTP.Password,
+			From:       cfg.From,
+			Encryption: cfg.SMTP.Encryption,
+			Timeout:    time.Duration(cfg.SMTP.Timeout) * time.Second,
+		})
+	default:
+		m, err = mailer.NewLog(cfg.From, cfg.Dir)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("mail: %w", err)
+	}
+	return m, templates, nil
+}
+
+// newCache builds the configured cache and checks that Redis is reachable.
+// two_level puts a memory cache in front of Redis and invalidates the local
+// copies of every replica over Redis pub/sub.
+func newCache(cfg *config.CacheConfig) (cache.Cache, error) {
+	ttl := time.Duration(cfg.DefaultTTL) * time.Second
+	if cfg.Driver == "memory" {
+		return cache.NewMemoryCache(ttl, 0), nil
+	}
+
+	r := cfg.Redis
+	rc, err := cache.NewRedisCache(cache.RedisOptions{
+		Mode:             r.Mode,
+		Addrs:            r.Addrs,
+		MasterName:       r.MasterName,
+		Username:         r.Username,
+		Password:         r.Password,
+		SentinelUsername: r.SentinelUsername,
+		SentinelPassword: r.SentinelPassword,
+		DB:               r.DB,
+		KeyPrefix:        r.KeyPrefix,
+		TLS:              r.TLS,
+		PoolSize:         r.PoolSize,
+		DialTimeout:      time.Duration(r.DialTimeout) * time.Second,
+		ReadTimeout:      time.Duration(r.ReadTimeout) * time.Second,
+		WriteTimeout:     time.Duration(r.WriteTimeout) * time.Second,
+	}, ttl)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := rc.Ping(ctx); err != nil {
+		rc.Close()
+		return nil, fmt.Errorf("cache.redis: %w", err)
+	}
+	logger.Infof("cache: %s, redis %s at %s", cfg.Driver, r.Mode, strings.Join(r.Addrs, ", "))
+	if cfg.Driver != "two_level" {
+		return rc, nil
+	}
+
+	localTTL := time.Duration(cfg.LocalTTL) * time.Second
+	tc, err := cache.NewTwoLevelCache(cache.NewMemoryCache(localTTL, 0), rc, cache.TwoLevelOptions{
+		L1TTL:       localTTL,
+		Invalidator: cache.NewRedisInvalidator(rc.Client(), r.KeyPrefix+cfg.InvalidationChannel),
+	})
+	if err != nil {
+		rc.Close()
+		return nil, fmt.Errorf("cache: %w", err)
+	}
+	return tc, nil
+}
+
+// newPasswordHasher builds the password hasher and its worker pool
+func newPasswordHasher(cfg *config.PasswordHashConfig) (*password.Hasher, error) {
+	hasher, err := password.NewHasher(password.Params{
+		Algorithm:     cfg.Algorithm,
+		Memory:        cfg.Argon2Memory,
+		Iterations:    cfg.Argon2Iterations,
+		Parallelism:   cfg.Argon2Parallelism,
+		BcryptCost:    cfg.BcryptCost,
+		MaxConcurrent: cfg.MaxConcurrent,
+		QueueTimeout:  time.Duration(cfg.QueueTimeout) * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("security.password_hash: %w", err)
+	}
+	return hasher, nil
+}
+
+// newPasswordPolicy builds the password policy and opens its breached password list
+func newPasswordPolicy(cfg *config.PasswordConfig) (*password.Policy, error) {
+	policy := &password.Policy{
+		MinLength:      cfg.MinLength,
+		MaxLength:      cfg.MaxLength,
+		RequireUpper:   cfg.RequireUpper,
+		RequireLower:   cfg.RequireLower,
+		RequireNumber:  cfg.RequireNumber,
+		RequireSpecial: cfg.RequireSpecial,
+		MinStrength:    cfg.MinStrength,
+	}
+	switch cfg.BreachedList {
+	case "":
+	case "builtin":
+		policy.Breached = password.BuiltinBreachedList()
+	default:
+		list, err := password.OpenBreachedList(cfg.BreachedList)
+		if err != nil {
+			return nil, fmt.Errorf("password.breached_list: %w", err)
+		}
+		policy.Breached = list
+	}
+	return policy, nil
+}
+
+// loadSigningKeys builds the JWT key set from the configured PEM files, or
+// from the HS256 secret when no keys are configured
+func loadSigningKeys(cfg *config.JWTConfig) (*jwtkeys.KeySet, error) {
+	if len(cfg.Keys) == 0 {
+		logger.Info("jwt.keys not configured, signing with the shared HS256 secret")
+		return jwtkeys.NewKeySet(jwtkeys.NewHMAC(cfg.Secret))
+	}
+	keys := make([]*jwtkeys.Key, len(cfg.Keys))
+	for i, k := range cfg.Keys {
+		key, err := jwtkeys.LoadPEM(k.ID, k.PrivateKeyFile, k.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load jwt.keys[%d]: %w", i, err)
+		}
+		keys[i] = key
+	}
+	return jwtkeys.NewKeySet(keys...)
+}
+
+// Close flushes the audit log, waits for outgoing mail and releases all resources
 func (c *Container) Close() {
+	if c.AuditService != nil {
+		c.AuditService.Close()
+	}
+	if c.EmailService != nil {
+		c.EmailService.Close()
+	}
+	if c.Cache != nil {
+		c.Cache.Close()
+	}
 	if c.DB != nil {
 		c.DB.Close()
 	}
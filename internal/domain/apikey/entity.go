@@ -0,0 +1,53 @@
+package apikey
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAPIKeyNotFound is returned by repositories when no key matches
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// APIKey is a named, scoped credential for machine clients.
+// Only a hash of the secret is stored; Prefix identifies the key in listings.
+type APIKey struct {
+	ID         uint
+	UserID     uint
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// NewAPIKey creates a new key (factory method); a nil expiresAt never expires
+func NewAPIKey(userID uint, name, prefix, keyHash string, scopes []string, expiresAt *time.Time) *APIKey {
+	return &APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// IsExpired reports whether the key's lifetime has ended
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// IsRevoked reports whether the key was revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Revoke marks the key as revoked
+func (k *APIKey) Revoke(at time.Time) {
+	if k.RevokedAt == nil {
+		k.RevokedAt = &at
+	}
+}
@@ -0,0 +1,24 @@
+package apikey
+
+import "time"
+
+// Repository defines the API key repository interface
+type Repository interface {
+	// FindByID finds a key, returns ErrAPIKeyNotFound if absent
+	FindByID(id uint) (*APIKey, error)
+
+	// FindByHash finds a key by the hash of its secret, returns ErrAPIKeyNotFound if absent
+	FindByHash(hash string) (*APIKey, error)
+
+	// ListByUser returns the user's keys, newest first
+	ListByUser(userID uint) ([]*APIKey, error)
+
+	// Save creates or updates
+	Save(k *APIKey) error
+
+	// Touch records a use of the key, writing at most once per interval
+	Touch(id uint, at time.Time, interval time.Duration) error
+
+	// DeleteByUser removes all of the user's keys
+	DeleteByUser(userID uint) error
+}
@@ -0,0 +1,19 @@
+package audit
+
+import "context"
+
+type contextKey struct{}
+
+// WithEntry returns a context carrying the audit entry of the current request.
+// Code handling the request fills in the action, actor, target and changes it
+// knows about; the entry is written once the request completes.
+func WithEntry(ctx context.Context, e *Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, e)
+}
+
+// FromContext returns the audit entry of the current request, or nil outside
+// audited requests
+func FromContext(ctx context.Context) *Entry {
+	e, _ := ctx.Value(contextKey{}).(*Entry)
+	return e
+}
@@ -0,0 +1,170 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Actions of authentication events. Other mutating requests are recorded as
+// "<METHOD> <route>", e.g. "DELETE /api/v1/examples/:id".
+const (
+	ActionLoginSuccess      = "login.success"
+	ActionLoginFailure      = "login.failure"
+	ActionLoginLocked       = "login.locked"
+	ActionMFAChallenge      = "login.mfa_challenge" // password accepted, second factor pending
+	ActionLogout            = "token.revoke"
+	ActionSessionRevoke     = "session.revoke"
+	ActionSessionRevokeAll  = "session.revoke_all"
+	ActionAPIKeyRevoke      = "api_key.revoke"
+	ActionPasskeyRegister   = "passkey.register"
+	ActionPasskeyDelete     = "passkey.delete"
+	ActionRefreshTokenReuse = "token.reuse_detected"
+	ActionEmailVerified     = "email.verified"
+	ActionPasswordForgot    = "password.reset_requested"
+	ActionPasswordReset     = "password.reset"
+	ActionImpersonateStart  = "impersonation.start"
+	ActionImpersonateStop   = "impersonation.stop"
+)
+
+// Redacted replaces the value of sensitive fields in recorded changes
+const Redacted = "[REDACTED]"
+
+// Entry is one audit record: who did what to which entity, from where and
+// with what result
+type Entry struct {
+	ID               uint
+	TenantID         uint
+	ActorID          uint   // 0 when the caller is not known, e.g. a login with an unknown username
+	ActorName        string // username, or the attempted username of a failed login
+	ImpersonatorID   uint   // administrator who made the request while impersonating the actor; 0 otherwise
+	ImpersonatorName string
+	Action           string
+	Method           string
+	Route            string // route pattern, e.g. /api/v1/examples/:id
+	TargetID         string
+	Status           int // HTTP status of the response
+	IP               string
+	RequestID        string
+	Changes          Changes
+	CreatedAt        time.Time
+}
+
+// Change is the old and new value of one field. Old is absent for created
+// fields and New for removed ones.
+type Change struct {
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+// Changes maps field names to their change
+type Changes map[string]Change
+
+// Diff returns the fields that differ between two JSON-serializable
+// representations of an entity. Pass nil before for a creation and nil after
+// for a deletion. Sensitive fields are redacted.
+func Diff(before, after any) Changes {
+	prev, next := toFields(before), toFields(after)
+	changes := Changes{}
+	for k, v := range prev {
+		if nv, ok := next[k]; !ok || !reflect.DeepEqual(v, nv) {
+			changes[k] = Change{Old: v, New: nv}
+		}
+	}
+	for k, v := range next {
+		if _, ok := prev[k]; !ok {
+			changes[k] = Change{New: v}
+		}
+	}
+	return changes.Redact()
+}
+
+// FromBody records the fields of a JSON request body as new values, for
+// requests whose handler did not record a diff
+func FromBody(body []byte) Changes {
+	var fields map[string]any
+	if json.Unmarshal(body, &fields) != nil || len(fields) == 0 {
+		return nil
+	}
+	changes := make(Changes, len(fields))
+	for k, v := range fields {
+		changes[k] = Change{New: v}
+	}
+	return changes.Redact()
+}
+
+// Redact masks the values of fields holding secrets, such as passwords,
+// tokens and keys, at any depth of nested objects and arrays. Flags like
+// must_change_password are kept.
+func (c Changes) Redact() Changes {
+	for k, v := range c {
+		if IsSensitive(k) {
+			c[k] = Change{Old: mask(v.Old), New: mask(v.New)}
+		} else {
+			c[k] = Change{Old: redact(v.Old), New: redact(v.New)}
+		}
+	}
+	return c
+}
+
+// IsSensitive reports whether a field name denotes a secret
+func IsSensitive(field string) bool {
+	f := strings.ToLower(field)
+	for _, s := range []string{"password", "secret", "token", "recovery_code"} {
+		if strings.Contains(f, s) {
+			return true
+		}
+	}
+	return f == "key" || strings.HasSuffix(f, "_key") || f == "otp" || f == "qr_code"
+}
+
+// redact masks sensitive fields inside decoded JSON in place. An object
+// naming a sensitive attribute in "path" has its "value" masked too, as in
+// SCIM PATCH operations: {"op":"replace","path":"password","value":"..."}.
+func redact(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		sensitivePath := false
+		for k, fv := range t {
+			if p, ok := fv.(string); ok && strings.EqualFold(k, "path") && IsSensitive(p) {
+				sensitivePath = true
+			}
+		}
+		for k, fv := range t {
+			if IsSensitive(k) || (sensitivePath && strings.EqualFold(k, "value")) {
+				t[k] = mask(fv)
+			} else {
+				t[k] = redact(fv)
+			}
+		}
+	case []any:
+		for i := range t {
+			t[i] = redact(t[i])
+		}
+	}
+	return v
+}
+
+func mask(v any) any {
+	if _, flag := v.(bool); flag || v == nil {
+		return v
+	}
+	return Redacted
+}
+
+// toFields flattens a value into its top-level JSON fields
+func toFields(v any) map[string]any {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]any
+	if json.Unmarshal(data, &fields) != nil {
+		return nil
+	}
+	return fields
+}
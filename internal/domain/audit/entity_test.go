@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFromBodyRedactsNestedSecrets(t *testing.T) {
+	body := `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{"op": "replace", "path": "password", "value": "Hunter2-Secret!"},
+			{"op": "replace", "value": {"password": "Other-Secret-9", "displayName": "Alice"}},
+			{"op": "add", "Path": "urn:ietf:params:scim:schemas:core:2.0:User:password", "Value": "Third-Secret-7"},
+			{"op": "replace", "path": "active", "value": false}
+		],
+		"profile": {"nickname": "al", "api_keys": [{"name": "ci", "key": "sk_live_123"}]},
+		"must_change_password": true
+	}`
+
+	changes := FromBody([]byte(body))
+	data, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := string(data)
+	for _, secret := range []string{"Hunter2-Secret!", "Other-Secret-9", "Third-Secret-7", "sk_live_123"} {
+		if strings.Contains(recorded, secret) {
+			t.Errorf("secret %q recorded: %s", secret, recorded)
+		}
+	}
+	for _, kept := range []string{`"displayName":"Alice"`, `"path":"active","value":false`, `"nickname":"al"`, `"name":"ci"`} {
+		if !strings.Contains(recorded, kept) {
+			t.Errorf("%s missing from %s", kept, recorded)
+		}
+	}
+	if v := changes["must_change_password"].New; v != true {
+		t.Errorf("must_change_password = %v, want the flag kept", v)
+	}
+}
+
+func TestDiffRedactsNestedSecrets(t *testing.T) {
+	type settings struct {
+		SMTPPassword string `json:"smtp_password"`
+		Host         string `json:"host"`
+	}
+	type entity struct {
+		Name     string   `json:"name"`
+		Settings settings `json:"settings"`
+	}
+
+	changes := Diff(
+		&entity{Name: "a", Settings: settings{SMTPPassword: "old-secret", Host: "a.example"}},
+		&entity{Name: "a", Settings: settings{SMTPPassword: "new-secret", Host: "b.example"}},
+	)
+	change, ok := changes["settings"]
+	if !ok || len(changes) != 1 {
+		t.Fatalf("changes = %v, want only settings", changes)
+	}
+	old, next := change.Old.(map[string]any), change.New.(map[string]any)
+	if old["smtp_password"] != Redacted || next["smtp_password"] != Redacted {
+		t.Errorf("nested password not redacted: %v", change)
+	}
+	if next["host"] != "b.example" {
+		t.Errorf("host = %v, want b.example", next["host"])
+	}
+}
+
+func TestIsSensitive(t *testing.T) {
+	for field, want := range map[string]bool{
+		"password":             true,
+		"new_password":         true,
+		"client_secret":        true,
+		"refresh_token":        true,
+		"key":                  true,
+		"private_key":          true,
+		"otp":                  true,
+		"must_change_password": true,
+		"username":             false,
+		"keyboard":             false,
+		"path":                 false,
+	} {
+		if got := IsSensitive(field); got != want {
+			t.Errorf("IsSensitive(%q) = %v, want %v", field, got, want)
+		}
+	}
+}
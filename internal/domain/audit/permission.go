@@ -0,0 +1,9 @@
+package audit
+
+import "go-ddd-scaffold/internal/domain/rbac"
+
+// Permissions declares the permissions guarded by the audit log API.
+// No role is granted them by default, so only administrators hold them.
+var Permissions = []rbac.Permission{
+	{Code: "audit:read", Description: "View audit logs"},
+}
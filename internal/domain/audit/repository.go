@@ -0,0 +1,27 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// Filter narrows an audit log query; zero fields match everything
+type Filter struct {
+	ActorID        uint
+	ImpersonatorID uint
+	Action         string
+	Method         string
+	TargetID       string
+	From           *time.Time
+	To             *time.Time
+}
+
+// Repository defines the audit log repository interface. Entries are
+// tenant-scoped and never updated.
+type Repository interface {
+	// SaveBatch appends entries; each must carry its tenant
+	SaveBatch(ctx context.Context, entries []*Entry) error
+
+	// List returns matching entries of the context's tenant, newest first
+	List(ctx context.Context, filter Filter, page, pageSize int) ([]*Entry, int64, error)
+}
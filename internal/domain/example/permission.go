@@ -0,0 +1,10 @@
+package example
+
+import "go-ddd-scaffold/internal/domain/rbac"
+
+// Permissions declares the permissions guarded by the example module
+var Permissions = []rbac.Permission{
+	{Code: "example:read", Description: "View examples", DefaultRoles: []string{"user"}},
+	{Code: "example:write", Description: "Create and update examples", DefaultRoles: []string{"user"}},
+	{Code: "example:delete", Description: "Delete examples"},
+}
@@ -1,16 +1,19 @@
 package example
 
-// Repository defines the example repository interface
+import "context"
+
+// Repository defines the example repository interface. Examples are
+// tenant-aware: every method only sees the tenant carried by ctx.
 type Repository interface {
 	// FindByID finds by ID
-	FindByID(id uint) (*Example, error)
+	FindByID(ctx context.Context, id uint) (*Example, error)
 
 	// List returns paginated results
-	List(page, pageSize int, keyword string, status Status) ([]*Example, int64, error)
+	List(ctx context.Context, page, pageSize int, keyword string, status Status) ([]*Example, int64, error)
 
 	// Save creates or updates
-	Save(entity *Example) error
+	Save(ctx context.Context, entity *Example) error
 
 	// Delete deletes by ID
-	Delete(id uint) error
+	Delete(ctx context.Context, id uint) error
 }
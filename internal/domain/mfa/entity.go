@@ -0,0 +1,45 @@
+package mfa
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTOTPNotFound is returned by repositories when the user has no TOTP credential
+var ErrTOTPNotFound = errors.New("totp credential not found")
+
+// TOTPCredential is a user's authenticator app secret
+type TOTPCredential struct {
+	ID           uint
+	UserID       uint
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NewTOTPCredential creates an unconfirmed credential (factory method)
+func NewTOTPCredential(userID uint, secret string) *TOTPCredential {
+	return &TOTPCredential{UserID: userID, Secret: secret}
+}
+
+// IsEnabled reports whether enrollment was confirmed with a first code
+func (c *TOTPCredential) IsEnabled() bool {
+	return c.ConfirmedAt != nil
+}
+
+// Confirm enables the credential, recording the step of the confirming code
+func (c *TOTPCredential) Confirm(at time.Time, step int64) {
+	c.ConfirmedAt = &at
+	c.LastUsedStep = step
+}
+
+// RecoveryCode is a hashed single-use fallback for a lost authenticator
+type RecoveryCode struct {
+	ID        uint
+	UserID    uint
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
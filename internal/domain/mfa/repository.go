@@ -0,0 +1,28 @@
+package mfa
+
+import "time"
+
+// Repository defines the MFA credential repository interface
+type Repository interface {
+	// FindTOTP finds the user's credential, returns ErrTOTPNotFound if absent
+	FindTOTP(userID uint) (*TOTPCredential, error)
+
+	// SaveTOTP creates or updates the user's credential
+	SaveTOTP(c *TOTPCredential) error
+
+	// MarkStepUsed atomically advances the last used step; false means the
+	// step was already used (replay) or the credential is gone
+	MarkStepUsed(userID uint, step int64) (bool, error)
+
+	// DeleteTOTP removes the user's credential and recovery codes
+	DeleteTOTP(userID uint) error
+
+	// ReplaceRecoveryCodes discards the user's recovery codes and stores new ones
+	ReplaceRecoveryCodes(userID uint, hashes []string) error
+
+	// UseRecoveryCode atomically marks an unused code as used; false if none matched
+	UseRecoveryCode(userID uint, hash string, at time.Time) (bool, error)
+
+	// CountRecoveryCodes counts the user's unused recovery codes
+	CountRecoveryCodes(userID uint) (int64, error)
+}
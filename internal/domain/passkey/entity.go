@@ -0,0 +1,50 @@
+package passkey
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrPasskeyNotFound is returned by repositories when no passkey matches
+var ErrPasskeyNotFound = errors.New("passkey not found")
+
+// Passkey is a WebAuthn credential registered by a user for passwordless
+// login. Only the public key is stored; the private key never leaves the
+// user's authenticator.
+type Passkey struct {
+	ID              uint
+	UserID          uint
+	Name            string
+	CredentialID    []byte
+	PublicKey       []byte // COSE-encoded
+	AttestationType string
+	Transports      []string
+	AAGUID          []byte // authenticator model, all zeros when not disclosed
+	SignCount       uint32
+	BackupEligible  bool // the credential may be synced to other devices
+	BackupState     bool // the credential is currently synced
+	LastUsedAt      *time.Time
+	CreatedAt       time.Time
+}
+
+// NewPasskey creates a new passkey (factory method)
+func NewPasskey(userID uint, name string, credentialID, publicKey []byte) *Passkey {
+	return &Passkey{
+		UserID:       userID,
+		Name:         name,
+		CredentialID: credentialID,
+		PublicKey:    publicKey,
+	}
+}
+
+// Rename changes the label the user recognizes the passkey by
+func (p *Passkey) Rename(name string) {
+	p.Name = name
+}
+
+// RecordUse stores the state reported by the authenticator at a login
+func (p *Passkey) RecordUse(at time.Time, signCount uint32, backupState bool) {
+	p.SignCount = signCount
+	p.BackupState = backupState
+	p.LastUsedAt = &at
+}
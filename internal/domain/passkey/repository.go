@@ -0,0 +1,22 @@
+package passkey
+
+// Repository defines the passkey repository interface
+type Repository interface {
+	// FindByID finds a passkey, returns ErrPasskeyNotFound if absent
+	FindByID(id uint) (*Passkey, error)
+
+	// FindByCredentialID finds a passkey by its WebAuthn credential ID, returns ErrPasskeyNotFound if absent
+	FindByCredentialID(credentialID []byte) (*Passkey, error)
+
+	// ListByUser returns the user's passkeys, oldest first
+	ListByUser(userID uint) ([]*Passkey, error)
+
+	// Save creates or updates
+	Save(p *Passkey) error
+
+	// Delete removes a passkey
+	Delete(id uint) error
+
+	// DeleteByUser removes all of the user's passkeys
+	DeleteByUser(userID uint) error
+}
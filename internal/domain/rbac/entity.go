@@ -0,0 +1,90 @@
+package rbac
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrRoleNotFound is returned by repositories when no role matches
+var ErrRoleNotFound = errors.New("role not found")
+
+// Wildcard grants every permission
+const Wildcard = "*"
+
+// Built-in role codes, matching user.RoleAdmin and user.RoleUser
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Permission is a named capability, e.g. "example:write"
+type Permission struct {
+	ID          uint
+	Code        string
+	Description string
+
+	// DefaultRoles are granted this permission when it is first seeded (not persisted)
+	DefaultRoles []string
+}
+
+// Role is the aggregate root for a set of granted permissions
+type Role struct {
+	ID          uint
+	Code        string
+	Name        string
+	Description string
+	RequireMFA  bool // members must enroll a second factor before using the API
+	Permissions []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewRole creates a new Role (factory method)
+func NewRole(code, name, description string, permissions []string) *Role {
+	return &Role{
+		Code:        code,
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+	}
+}
+
+// IsBuiltin reports whether the role is seeded by the system and must not be deleted
+func (r *Role) IsBuiltin() bool {
+	return r.Code == RoleAdmin || r.Code == RoleUser
+}
+
+// UpdateInfo updates basic info
+func (r *Role) UpdateInfo(name, description string) {
+	if name != "" {
+		r.Name = name
+	}
+	if description != "" {
+		r.Description = description
+	}
+}
+
+// SetRequireMFA sets whether members must use two-factor authentication
+func (r *Role) SetRequireMFA(required bool) {
+	r.RequireMFA = required
+}
+
+// SetPermissions replaces the granted permissions
+func (r *Role) SetPermissions(permissions []string) {
+	r.Permissions = permissions
+}
+
+// Can reports whether the role grants the permission
+func (r *Role) Can(permission string) bool {
+	return Match(r.Permissions, permission)
+}
+
+// Match reports whether the granted set covers the permission
+func Match(granted []string, permission string) bool {
+	for _, g := range granted {
+		if g == Wildcard || g == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+package rbac
+
+// Permissions declares the permissions guarded by the RBAC admin API
+var Permissions = []Permission{
+	{Code: "rbac:read", Description: "View roles and permissions"},
+	{Code: "rbac:write", Description: "Manage roles and role assignments"},
+}
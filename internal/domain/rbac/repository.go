@@ -0,0 +1,28 @@
+package rbac
+
+// Repository defines the role and permission repository interface
+type Repository interface {
+	// ListRoles returns all roles with their permissions
+	ListRoles() ([]*Role, error)
+
+	// FindRoleByID finds by ID, returns ErrRoleNotFound if absent
+	FindRoleByID(id uint) (*Role, error)
+
+	// FindRoleByCode finds by code, returns ErrRoleNotFound if absent
+	FindRoleByCode(code string) (*Role, error)
+
+	// SaveRole creates or updates the role and replaces its permission set
+	SaveRole(role *Role) error
+
+	// DeleteRole deletes by ID
+	DeleteRole(id uint) error
+
+	// ListPermissions returns all known permissions
+	ListPermissions() ([]*Permission, error)
+
+	// EnsurePermission creates the permission if missing and reports whether it was created
+	EnsurePermission(p *Permission) (bool, error)
+
+	// GrantPermission adds a permission to a role, no-op if already granted
+	GrantPermission(roleCode, permissionCode string) error
+}
@@ -0,0 +1,41 @@
+package session
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrSessionNotFound is returned by repositories when no session matches
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session is a signed-in device. It lives as long as the refresh token family
+// started at login, whose ID it shares and which access tokens carry as "sid".
+type Session struct {
+	ID             uint
+	UserID         uint
+	FamilyID       string
+	UserAgent      string
+	IP             string
+	CreatedAt      time.Time
+	LastSeenAt     time.Time
+	ExpiresAt      time.Time // moves forward with every refresh
+	RevokedAt      *time.Time
+	ImpersonatorID uint // administrator signed in as the user; 0 for the user's own sessions
+}
+
+// NewSession creates a new session (factory method)
+func NewSession(userID uint, familyID, userAgent, ip string, now, expiresAt time.Time) *Session {
+	return &Session{
+		UserID:     userID,
+		FamilyID:   familyID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}
+}
+
+// IsActive reports whether the session is neither revoked nor expired
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,30 @@
+package session
+
+import "time"
+
+// Repository defines the session repository interface
+type Repository interface {
+	// FindByID finds by ID, returns ErrSessionNotFound if absent
+	FindByID(id uint) (*Session, error)
+
+	// ListActive returns the user's sessions that are neither revoked nor expired, newest first
+	ListActive(userID uint, now time.Time) ([]*Session, error)
+
+	// Create persists a new session
+	Create(entity *Session) error
+
+	// Touch updates last_seen_at unless it was already updated within interval
+	Touch(familyID string, at time.Time, interval time.Duration) error
+
+	// Extend records a refresh: the session is seen now and expires later
+	Extend(familyID string, at, expiresAt time.Time) error
+
+	// Revoke revokes the session of a token family
+	Revoke(familyID string, at time.Time) error
+
+	// RevokeByUser revokes every session of the user
+	RevokeByUser(userID uint, at time.Time) error
+
+	// DeleteExpired removes the user's sessions that expired before the given time
+	DeleteExpired(userID uint, before time.Time) error
+}
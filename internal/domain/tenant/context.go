@@ -0,0 +1,52 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrNoTenant is returned when tenant-aware data is accessed without a tenant
+	// in the context; scoping fails closed rather than exposing every tenant
+	ErrNoTenant = errors.New("no tenant in context")
+
+	// ErrCrossTenant is returned when writing a row that belongs to another tenant
+	ErrCrossTenant = errors.New("row belongs to another tenant")
+)
+
+type contextKey int
+
+const (
+	tenantKey contextKey = iota
+	allTenantsKey
+)
+
+// WithTenant returns a context scoped to one tenant. Repositories of
+// tenant-aware data only see and write that tenant's rows.
+func WithTenant(ctx context.Context, id uint) context.Context {
+	ctx = context.WithValue(ctx, allTenantsKey, false)
+	return context.WithValue(ctx, tenantKey, id)
+}
+
+// WithAllTenants returns a context that lifts tenant scoping, for super-admin
+// and system operations that deliberately work across tenants. Rows created
+// under it must carry their tenant explicitly.
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsKey, true)
+}
+
+// FromContext returns the tenant the context is scoped to; there is none
+// while scoping is lifted
+func FromContext(ctx context.Context) (uint, bool) {
+	if IsAllTenants(ctx) {
+		return 0, false
+	}
+	id, ok := ctx.Value(tenantKey).(uint)
+	return id, ok && id != 0
+}
+
+// IsAllTenants reports whether the context lifts tenant scoping
+func IsAllTenants(ctx context.Context) bool {
+	all, _ := ctx.Value(allTenantsKey).(bool)
+	return all
+}
@@ -0,0 +1,41 @@
+package tenant
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTenantNotFound is returned by repositories when no tenant matches
+var ErrTenantNotFound = errors.New("tenant not found")
+
+const (
+	// DefaultID is the tenant that existing data and single-tenant deployments belong to
+	DefaultID uint = 1
+	// DefaultCode is the code of the default tenant
+	DefaultCode = "default"
+)
+
+// Tenant is an isolated organisation; users and tenant-aware business data
+// belong to exactly one tenant and are invisible to the others
+type Tenant struct {
+	ID        uint
+	Code      string // stable identifier users sign in with
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewTenant creates a new Tenant (factory method)
+func NewTenant(code, name string) *Tenant {
+	if name == "" {
+		name = code
+	}
+	return &Tenant{Code: code, Name: name}
+}
+
+// UpdateInfo updates basic info
+func (t *Tenant) UpdateInfo(name string) {
+	if name != "" {
+		t.Name = name
+	}
+}
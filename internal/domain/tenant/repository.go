@@ -0,0 +1,17 @@
+package tenant
+
+// Repository defines the tenant repository interface. Tenants themselves are
+// not tenant-scoped.
+type Repository interface {
+	// FindByID finds by ID, returns ErrTenantNotFound if absent
+	FindByID(id uint) (*Tenant, error)
+
+	// FindByCode finds by code, returns ErrTenantNotFound if absent
+	FindByCode(code string) (*Tenant, error)
+
+	// List returns all tenants ordered by ID
+	List() ([]*Tenant, error)
+
+	// Save creates or updates
+	Save(entity *Tenant) error
+}
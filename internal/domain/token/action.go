@@ -0,0 +1,52 @@
+package token
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrActionTokenNotFound is returned by repositories when no token matches
+var ErrActionTokenNotFound = errors.New("action token not found")
+
+// Purpose is what an action token authorizes
+type Purpose string
+
+const (
+	PurposeEmailVerification Purpose = "email_verification"
+	PurposePasswordReset     Purpose = "password_reset"
+)
+
+// ActionToken is an opaque, single-use, expiring token mailed to a user to
+// confirm an action. Only the hash of the raw value is persisted, together
+// with the address it was sent to.
+type ActionToken struct {
+	ID        uint
+	UserID    uint
+	Purpose   Purpose
+	TokenHash string
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// NewActionToken creates a new action token (factory method)
+func NewActionToken(userID uint, purpose Purpose, tokenHash, email string, expiresAt time.Time) *ActionToken {
+	return &ActionToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		Email:     email,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// IsExpired reports whether the token is past its expiry
+func (t *ActionToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// IsUsed reports whether the token was already redeemed or superseded
+func (t *ActionToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
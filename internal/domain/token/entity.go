@@ -0,0 +1,48 @@
+package token
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrRefreshTokenNotFound is returned by repositories when no token matches
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+// RefreshToken is an opaque, single-use token belonging to a rotation family.
+// Only the hash of the raw value is persisted.
+type RefreshToken struct {
+	ID        uint
+	UserID    uint
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// NewRefreshToken creates a new refresh token (factory method)
+func NewRefreshToken(userID uint, familyID, tokenHash string, expiresAt time.Time) *RefreshToken {
+	return &RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// IsExpired reports whether the token is past its expiry
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// IsRevoked reports whether the token's family was ended (logout or sign-out)
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsConsumed reports whether the token was already rotated or revoked.
+// Presenting a consumed token again indicates the family was leaked.
+func (t *RefreshToken) IsConsumed() bool {
+	return t.UsedAt != nil || t.RevokedAt != nil
+}
@@ -0,0 +1,46 @@
+package token
+
+import "time"
+
+// Repository defines the refresh token repository interface
+type Repository interface {
+	// FindByHash finds by token hash, returns ErrRefreshTokenNotFound if absent
+	FindByHash(hash string) (*RefreshToken, error)
+
+	// Create persists a new token
+	Create(entity *RefreshToken) error
+
+	// MarkUsed atomically consumes the token; returns false if it was already consumed
+	MarkUsed(id uint, at time.Time) (bool, error)
+
+	// RevokeFamily revokes every token in the family
+	RevokeFamily(familyID string, at time.Time) error
+
+	// RevokeByUser revokes every token of the user
+	RevokeByUser(userID uint, at time.Time) error
+
+	// DeleteExpired removes the user's tokens that expired before the given time
+	DeleteExpired(userID uint, before time.Time) error
+}
+
+// ActionRepository defines the action token repository interface
+type ActionRepository interface {
+	// FindByHash finds by token hash, returns ErrActionTokenNotFound if absent
+	FindByHash(hash string) (*ActionToken, error)
+
+	// Create persists a new token
+	Create(entity *ActionToken) error
+
+	// MarkUsed atomically redeems the token; returns false if it was already used
+	MarkUsed(id uint, at time.Time) (bool, error)
+
+	// InvalidateByUser marks the user's unused tokens for the purpose as used,
+	// e.g. when a newer one is issued
+	InvalidateByUser(userID uint, purpose Purpose, at time.Time) error
+
+	// DeleteByUser removes the user's tokens
+	DeleteByUser(userID uint) error
+
+	// DeleteExpired removes the user's tokens that expired before the given time
+	DeleteExpired(userID uint, before time.Time) error
+}
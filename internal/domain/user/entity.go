@@ -0,0 +1,137 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUserNotFound is returned by repositories when no user matches
+var ErrUserNotFound = errors.New("user not found")
+
+// User is the aggregate root for accounts
+type User struct {
+	ID                 uint
+	TenantID           uint
+	Username           string
+	PasswordHash       string
+	Nickname           string
+	Email              string
+	EmailVerifiedAt    *time.Time // when the owner proved control of Email; nil until then
+	Role               Role
+	Status             Status
+	MustChangePassword bool
+	ServiceAccount     bool // machine identity: no password, authenticates with API keys only
+	SuperAdmin         bool // may manage tenants and work across them
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// Role is a value object naming the user's role
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+)
+
+// String returns string representation
+func (r Role) String() string {
+	return string(r)
+}
+
+// Status is a value object
+type Status string
+
+const (
+	StatusActive   Status = "active"
+	StatusDisabled Status = "disabled"
+)
+
+// IsValid checks if status is valid
+func (s Status) IsValid() bool {
+	return s == StatusActive || s == StatusDisabled
+}
+
+// String returns string representation
+func (s Status) String() string {
+	return string(s)
+}
+
+// NewUser creates a new User (factory method)
+func NewUser(username, passwordHash string, role Role) *User {
+	if role == "" {
+		role = RoleUser
+	}
+	return &User{
+		Username:     username,
+		PasswordHash: passwordHash,
+		Role:         role,
+		Status:       StatusActive,
+	}
+}
+
+// NewServiceAccount creates a machine identity without a password (factory method)
+func NewServiceAccount(username string, role Role) *User {
+	u := NewUser(username, "", role)
+	u.ServiceAccount = true
+	return u
+}
+
+// IsAdmin reports whether the user has the admin role
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsActive reports whether the user may sign in
+func (u *User) IsActive() bool {
+	return u.Status != StatusDisabled
+}
+
+// Enable sets status to active
+func (u *User) Enable() {
+	u.Status = StatusActive
+}
+
+// Disable sets status to disabled
+func (u *User) Disable() {
+	u.Status = StatusDisabled
+}
+
+// UpdateProfile updates nickname and email. A new email address is unverified.
+func (u *User) UpdateProfile(nickname, email string) {
+	if nickname != "" {
+		u.Nickname = nickname
+	}
+	if email != "" && email != u.Email {
+		u.Email = email
+		u.EmailVerifiedAt = nil
+	}
+}
+
+// IsEmailVerified reports whether the user proved control of the current email
+func (u *User) IsEmailVerified() bool {
+	return u.Email != "" && u.EmailVerifiedAt != nil
+}
+
+// VerifyEmail marks the current email address as verified
+func (u *User) VerifyEmail(at time.Time) {
+	u.EmailVerifiedAt = &at
+}
+
+// ChangePassword replaces the password hash with one chosen by the user
+func (u *User) ChangePassword(passwordHash string) {
+	u.PasswordHash = passwordHash
+	u.MustChangePassword = false
+}
+
+// UpgradePasswordHash replaces the hash with a new hash of the same password,
+// e.g. made with stronger parameters
+func (u *User) UpgradePasswordHash(passwordHash string) {
+	u.PasswordHash = passwordHash
+}
+
+// ResetPassword sets a temporary password that must be changed at next login
+func (u *User) ResetPassword(passwordHash string) {
+	u.PasswordHash = passwordHash
+	u.MustChangePassword = true
+}
@@ -0,0 +1,23 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrIdentityNotFound is returned by repositories when no external identity matches
+var ErrIdentityNotFound = errors.New("identity not found")
+
+// Identity links a user to an account at an external identity provider
+type Identity struct {
+	ID        uint
+	UserID    uint
+	Provider  string // issuer URL of the identity provider
+	Subject   string // the provider's stable user identifier ("sub")
+	CreatedAt time.Time
+}
+
+// NewIdentity creates a new link (factory method)
+func NewIdentity(userID uint, provider, subject string) *Identity {
+	return &Identity{UserID: userID, Provider: provider, Subject: subject}
+}
@@ -0,0 +1,11 @@
+package user
+
+import "go-ddd-scaffold/internal/domain/rbac"
+
+// Permissions declares the permissions guarded by the user management API.
+// No role is granted them by default, so only administrators hold them.
+var Permissions = []rbac.Permission{
+	{Code: "user:read", Description: "View users"},
+	{Code: "user:write", Description: "Create, update, disable and delete users"},
+	{Code: "user:impersonate", Description: "Sign in as another user for support"},
+}
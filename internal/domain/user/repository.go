@@ -0,0 +1,101 @@
+package user
+
+import "context"
+
+// Field names a user attribute that searches can compare
+type Field string
+
+const (
+	FieldID        Field = "id"
+	FieldUsername  Field = "username"
+	FieldNickname  Field = "nickname"
+	FieldEmail     Field = "email"
+	FieldRole      Field = "role"
+	FieldStatus    Field = "status"
+	FieldCreatedAt Field = "created_at"
+	FieldUpdatedAt Field = "updated_at"
+)
+
+// Operator compares a field with a criterion's value
+type Operator string
+
+const (
+	OpEq         Operator = "eq"
+	OpNe         Operator = "ne"
+	OpContains   Operator = "co"
+	OpStartsWith Operator = "sw"
+	OpEndsWith   Operator = "ew"
+	OpPresent    Operator = "pr" // has a non-empty value; Value is ignored
+	OpGt         Operator = "gt"
+	OpGe         Operator = "ge"
+	OpLt         Operator = "lt"
+	OpLe         Operator = "le"
+)
+
+// Criterion restricts a search to users whose field compares to Value.
+// Username, nickname and email comparisons ignore case.
+type Criterion struct {
+	Field Field
+	Op    Operator
+	Value any
+}
+
+// Repository defines the user repository interface. Users are tenant-aware:
+// every method only sees the tenant carried by ctx.
+type Repository interface {
+	// FindByID finds by ID, returns ErrUserNotFound if absent
+	FindByID(ctx context.Context, id uint) (*User, error)
+
+	// FindByUsername finds by username, returns ErrUserNotFound if absent
+	FindByUsername(ctx context.Context, username string) (*User, error)
+
+	// FindByEmail finds by email, preferring a user who verified it; returns
+	// ErrUserNotFound if absent
+	FindByEmail(ctx context.Context, email string) (*User, error)
+
+	// List returns paginated results
+	List(ctx context.Context, page, pageSize int, keyword string, status Status) ([]*User, int64, error)
+
+	// Search returns the users matching every criterion ordered by ID, and
+	// their total; a negative limit returns all of them
+	Search(ctx context.Context, criteria []Criterion, offset, limit int) ([]*User, int64, error)
+
+	// CountByRole counts users assigned the role
+	CountByRole(ctx context.Context, role Role) (int64, error)
+
+	// Save creates or updates; new users join the tenant carried by ctx
+	Save(ctx context.Context, entity *User) error
+
+	// Delete deletes by ID
+	Delete(ctx context.Context, id uint) error
+}
+
+// PasswordHistoryRepository keeps the hashes of previously used passwords
+type PasswordHistoryRepository interface {
+	// Recent returns the newest password hashes of the user, newest first
+	Recent(userID uint, limit int) ([]string, error)
+
+	// Add records a password hash and prunes all but the newest keep entries
+	Add(userID uint, passwordHash string, keep int) error
+
+	// DeleteByUser removes the user's history
+	DeleteByUser(userID uint) error
+}
+
+// IdentityRepository stores links to external identity provider accounts
+type IdentityRepository interface {
+	// Find finds the link for a provider account, returns ErrIdentityNotFound if absent
+	Find(provider, subject string) (*Identity, error)
+
+	// ListByUsers returns the users' links to a provider
+	ListByUsers(provider string, userIDs []uint) ([]*Identity, error)
+
+	// Save creates a link
+	Save(identity *Identity) error
+
+	// Delete removes a link
+	Delete(id uint) error
+
+	// DeleteByUser removes the user's links
+	DeleteByUser(userID uint) error
+}
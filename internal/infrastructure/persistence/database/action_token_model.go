@@ -0,0 +1,52 @@
+package database
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/token"
+)
+
+// ActionTokenModel is the GORM model for mailed single-use tokens
+type ActionTokenModel struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	Purpose   string    `gorm:"size:32;not null"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	Email     string    `gorm:"size:255;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TableName overrides the table name
+func (ActionTokenModel) TableName() string {
+	return "action_tokens"
+}
+
+// ToDomain converts to domain entity
+func (m *ActionTokenModel) ToDomain() *token.ActionToken {
+	return &token.ActionToken{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Purpose:   token.Purpose(m.Purpose),
+		TokenHash: m.TokenHash,
+		Email:     m.Email,
+		ExpiresAt: m.ExpiresAt,
+		UsedAt:    m.UsedAt,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// ActionTokenFromDomain converts from domain entity
+func ActionTokenFromDomain(t *token.ActionToken) *ActionTokenModel {
+	return &ActionTokenModel{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Purpose:   string(t.Purpose),
+		TokenHash: t.TokenHash,
+		Email:     t.Email,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/token"
+
+	"gorm.io/gorm"
+)
+
+// ActionTokenRepository implements token.ActionRepository
+type ActionTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewActionTokenRepository creates a new repository
+func NewActionTokenRepository(database *DB) token.ActionRepository {
+	return &ActionTokenRepository{db: database.GormDB()}
+}
+
+// FindByHash finds by token hash
+func (r *ActionTokenRepository) FindByHash(hash string) (*token.ActionToken, error) {
+	var model ActionTokenModel
+	if err := r.db.Where("token_hash = ?", hash).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, token.ErrActionTokenNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// Create persists a new token
+func (r *ActionTokenRepository) Create(entity *token.ActionToken) error {
+	model := ActionTokenFromDomain(entity)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+	entity.ID = model.ID
+	entity.CreatedAt = model.CreatedAt
+	return nil
+}
+
+// MarkUsed redeems the token only if nobody else did first
+func (r *ActionTokenRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&ActionTokenModel{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateByUser marks the user's unused tokens for the purpose as used
+func (r *ActionTokenRepository) InvalidateByUser(userID uint, purpose token.Purpose, at time.Time) error {
+	return r.db.Model(&ActionTokenModel{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, string(purpose)).
+		Update("used_at", at).Error
+}
+
+// DeleteByUser removes the user's tokens
+func (r *ActionTokenRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&ActionTokenModel{}).Error
+}
+
+// DeleteExpired removes the user's expired tokens
+func (r *ActionTokenRepository) DeleteExpired(userID uint, before time.Time) error {
+	return r.db.Where("user_id = ? AND expires_at < ?", userID, before).
+		Delete(&ActionTokenModel{}).Error
+}
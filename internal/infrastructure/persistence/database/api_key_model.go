@@ -0,0 +1,63 @@
+package database
+
+import (
+	"strings"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/apikey"
+)
+
+// APIKeyModel is the GORM model for API keys
+type APIKeyModel struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	Name       string `gorm:"size:100;not null"`
+	Prefix     string `gorm:"size:20;not null"`
+	KeyHash    string `gorm:"size:64;not null;uniqueIndex"`
+	Scopes     string `gorm:"size:1000;not null"` // comma-separated permission codes
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// TableName overrides the table name
+func (APIKeyModel) TableName() string {
+	return "api_keys"
+}
+
+// ToDomain converts to domain entity
+func (m *APIKeyModel) ToDomain() *apikey.APIKey {
+	var scopes []string
+	if m.Scopes != "" {
+		scopes = strings.Split(m.Scopes, ",")
+	}
+	return &apikey.APIKey{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		Name:       m.Name,
+		Prefix:     m.Prefix,
+		KeyHash:    m.KeyHash,
+		Scopes:     scopes,
+		ExpiresAt:  m.ExpiresAt,
+		LastUsedAt: m.LastUsedAt,
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+// APIKeyFromDomain converts from domain entity
+func APIKeyFromDomain(k *apikey.APIKey) *APIKeyModel {
+	return &APIKeyModel{
+		ID:         k.ID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		KeyHash:    k.KeyHash,
+		Scopes:     strings.Join(k.Scopes, ","),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
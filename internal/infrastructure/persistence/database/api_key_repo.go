@@ -0,0 +1,85 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/apikey"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository implements apikey.Repository
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new repository
+func NewAPIKeyRepository(database *DB) apikey.Repository {
+	return &APIKeyRepository{db: database.GormDB()}
+}
+
+// FindByID finds by ID
+func (r *APIKeyRepository) FindByID(id uint) (*apikey.APIKey, error) {
+	var model APIKeyModel
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, translateAPIKeyError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// FindByHash finds by the hash of the key secret
+func (r *APIKeyRepository) FindByHash(hash string) (*apikey.APIKey, error) {
+	var model APIKeyModel
+	if err := r.db.Where("key_hash = ?", hash).First(&model).Error; err != nil {
+		return nil, translateAPIKeyError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// ListByUser returns the user's keys, newest first
+func (r *APIKeyRepository) ListByUser(userID uint) ([]*apikey.APIKey, error) {
+	var models []APIKeyModel
+	if err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	entities := make([]*apikey.APIKey, len(models))
+	for i := range models {
+		entities[i] = models[i].ToDomain()
+	}
+	return entities, nil
+}
+
+// Save creates or updates
+func (r *APIKeyRepository) Save(k *apikey.APIKey) error {
+	model := APIKeyFromDomain(k)
+	if model.ID == 0 {
+		if err := r.db.Create(model).Error; err != nil {
+			return err
+		}
+		k.ID = model.ID
+		k.CreatedAt = model.CreatedAt
+		return nil
+	}
+	return r.db.Save(model).Error
+}
+
+// Touch updates last_used_at unless it was already updated within interval,
+// so busy keys do not cost a write per request
+func (r *APIKeyRepository) Touch(id uint, at time.Time, interval time.Duration) error {
+	return r.db.Model(&APIKeyModel{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at.Add(-interval)).
+		Update("last_used_at", at).Error
+}
+
+// DeleteByUser removes all of the user's keys
+func (r *APIKeyRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&APIKeyModel{}).Error
+}
+
+func translateAPIKeyError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apikey.ErrAPIKeyNotFound
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/audit"
+)
+
+// AuditLogModel is the GORM model for audit log entries
+type AuditLogModel struct {
+	ID               uint      `gorm:"primaryKey"`
+	TenantID         uint      `gorm:"not null;index:idx_audit_logs_tenant_created"`
+	ActorID          uint      `gorm:"index"`
+	ActorName        string    `gorm:"size:100"`
+	ImpersonatorID   uint      `gorm:"index"`
+	ImpersonatorName string    `gorm:"size:100"`
+	Action           string    `gorm:"size:100;index"`
+	Method           string    `gorm:"size:10"`
+	Route            string    `gorm:"size:255"`
+	TargetID         string    `gorm:"size:64;index"`
+	Status           int       `gorm:"not null"`
+	IP               string    `gorm:"size:45"`
+	RequestID        string    `gorm:"size:64"`
+	Changes          string    `gorm:"type:text"` // JSON object of field -> {old, new}
+	CreatedAt        time.Time `gorm:"index:idx_audit_logs_tenant_created"`
+}
+
+// TableName overrides the table name
+func (AuditLogModel) TableName() string {
+	return "audit_logs"
+}
+
+// ToDomain converts to domain entity
+func (m *AuditLogModel) ToDomain() *audit.Entry {
+	e := &audit.Entry{
+		ID:               m.ID,
+		TenantID:         m.TenantID,
+		ActorID:          m.ActorID,
+		ActorName:        m.ActorName,
+		ImpersonatorID:   m.ImpersonatorID,
+		ImpersonatorName: m.ImpersonatorName,
+		Action:           m.Action,
+		Method:           m.Method,
+		Route:            m.Route,
+		TargetID:         m.TargetID,
+		Status:           m.Status,
+		IP:               m.IP,
+		RequestID:        m.RequestID,
+		CreatedAt:        m.CreatedAt,
+	}
+	if m.Changes != "" {
+		_ = json.Unmarshal([]byte(m.Changes), &e.Changes)
+	}
+	return e
+}
+
+// AuditLogFromDomain converts from domain entity
+func AuditLogFromDomain(e *audit.Entry) *AuditLogModel {
+	m := &AuditLogModel{
+		ID:               e.ID,
+		TenantID:         e.TenantID,
+		ActorID:          e.ActorID,
+		ActorName:        e.ActorName,
+		ImpersonatorID:   e.ImpersonatorID,
+		ImpersonatorName: e.ImpersonatorName,
+		Action:           e.Action,
+		Method:           e.Method,
+		Route:            e.Route,
+		TargetID:         e.TargetID,
+		Status:           e.Status,
+		IP:               e.IP,
+		RequestID:        e.RequestID,
+		CreatedAt:        e.CreatedAt,
+	}
+	if len(e.Changes) > 0 {
+		if data, err := json.Marshal(e.Changes); err == nil {
+			m.Changes = string(data)
+		}
+	}
+	return m
+}
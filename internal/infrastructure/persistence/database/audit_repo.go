@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/audit"
+	"go-ddd-scaffold/pkg/querybuilder"
+
+	"gorm.io/gorm"
+)
+
+// auditBatchSize caps the rows of one INSERT
+const auditBatchSize = 100
+
+// AuditRepository implements audit.Repository
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new repository
+func NewAuditRepository(database *DB) audit.Repository {
+	return &AuditRepository{db: database.GormDB()}
+}
+
+// auditQuery maps audit.Filter to query conditions
+type auditQuery struct {
+	ActorID        uint       `search:"type:exact;column:actor_id"`
+	ImpersonatorID uint       `search:"type:exact;column:impersonator_id"`
+	Action         string     `search:"type:exact;column:action"`
+	Method         string     `search:"type:exact;column:method"`
+	TargetID       string     `search:"type:exact;column:target_id"`
+	From           *time.Time `search:"type:gte;column:created_at"`
+	To             *time.Time `search:"type:lt;column:created_at"`
+}
+
+// SaveBatch appends entries
+func (r *AuditRepository) SaveBatch(ctx context.Context, entries []*audit.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	models := make([]*AuditLogModel, len(entries))
+	for i, e := range entries {
+		models[i] = AuditLogFromDomain(e)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(models, auditBatchSize).Error
+}
+
+// List returns matching entries, newest first
+func (r *AuditRepository) List(ctx context.Context, filter audit.Filter, page, pageSize int) ([]*audit.Entry, int64, error) {
+	var models []AuditLogModel
+	var total int64
+
+	query := querybuilder.Apply(r.db.WithContext(ctx).Model(&AuditLogModel{}), &auditQuery{
+		ActorID:        filter.ActorID,
+		ImpersonatorID: filter.ImpersonatorID,
+		Action:         filter.Action,
+		Method:         filter.Method,
+		TargetID:       filter.TargetID,
+		From:           filter.From,
+		To:             filter.To,
+	})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	entities := make([]*audit.Entry, len(models))
+	for i := range models {
+		entities[i] = models[i].ToDomain()
+	}
+	return entities, total, nil
+}
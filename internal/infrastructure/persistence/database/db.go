@@ -57,6 +57,11 @@ func NewDB(cfg *config.DatabaseConfig) (*DB, error) {
 		return nil, fmt.Errorf("failed to connect database: %w", err)
 	}
 
+	// Confine tenant-aware models to the tenant of each statement's context
+	if err := registerTenantScope(db); err != nil {
+		return nil, fmt.Errorf("failed to register tenant scope: %w", err)
+	}
+
 	// SQLite WAL mode
 	if cfg.Type == "sqlite" {
 		db.Exec("PRAGMA journal_mode=WAL")
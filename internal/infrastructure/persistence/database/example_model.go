@@ -6,9 +6,11 @@ import (
 	"go-ddd-scaffold/internal/domain/example"
 )
 
-// ExampleModel is the GORM model (infrastructure layer)
+// ExampleModel is the GORM model (infrastructure layer). The TenantID field
+// makes it tenant-aware: it is filled in and filtered on automatically.
 type ExampleModel struct {
 	ID          uint   `gorm:"primaryKey"`
+	TenantID    uint   `gorm:"not null;default:1;index"`
 	Name        string `gorm:"size:100;not null;index"`
 	Description string `gorm:"size:500"`
 	Status      string `gorm:"size:20;default:active"`
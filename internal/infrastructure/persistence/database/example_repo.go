@@ -1,6 +1,8 @@
 package database
 
 import (
+	"context"
+
 	"go-ddd-scaffold/internal/domain/example"
 
 	"gorm.io/gorm"
@@ -17,20 +19,20 @@ func NewExampleRepository(database *DB) example.Repository {
 }
 
 // FindByID finds by ID
-func (r *ExampleRepository) FindByID(id uint) (*example.Example, error) {
+func (r *ExampleRepository) FindByID(ctx context.Context, id uint) (*example.Example, error) {
 	var model ExampleModel
-	if err := r.db.First(&model, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&model, id).Error; err != nil {
 		return nil, err
 	}
 	return model.ToDomain(), nil
 }
 
 // List returns paginated results
-func (r *ExampleRepository) List(page, pageSize int, keyword string, status example.Status) ([]*example.Example, int64, error) {
+func (r *ExampleRepository) List(ctx context.Context, page, pageSize int, keyword string, status example.Status) ([]*example.Example, int64, error) {
 	var models []ExampleModel
 	var total int64
 
-	query := r.db.Model(&ExampleModel{})
+	query := r.db.WithContext(ctx).Model(&ExampleModel{})
 
 	if keyword != "" {
 		query = query.Where("name LIKE ? OR description LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
@@ -57,10 +59,10 @@ func (r *ExampleRepository) List(page, pageSize int, keyword string, status exam
 }
 
 // Save creates or updates
-func (r *ExampleRepository) Save(entity *example.Example) error {
+func (r *ExampleRepository) Save(ctx context.Context, entity *example.Example) error {
 	model := FromDomain(entity)
 	if model.ID == 0 {
-		if err := r.db.Create(model).Error; err != nil {
+		if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
 			return err
 		}
 		entity.ID = model.ID
@@ -68,10 +70,10 @@ func (r *ExampleRepository) Save(entity *example.Example) error {
 		entity.UpdatedAt = model.UpdatedAt
 		return nil
 	}
-	return r.db.Save(model).Error
+	return r.db.WithContext(ctx).Save(model).Error
 }
 
 // Delete deletes by ID
-func (r *ExampleRepository) Delete(id uint) error {
-	return r.db.Delete(&ExampleModel{}, id).Error
+func (r *ExampleRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&ExampleModel{}, id).Error
 }
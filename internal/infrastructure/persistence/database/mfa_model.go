@@ -0,0 +1,63 @@
+package database
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/mfa"
+)
+
+// TOTPCredentialModel is the GORM model for TOTP credentials
+type TOTPCredentialModel struct {
+	ID           uint   `gorm:"primaryKey"`
+	UserID       uint   `gorm:"not null;uniqueIndex"`
+	Secret       string `gorm:"size:64;not null"`
+	ConfirmedAt  *time.Time
+	LastUsedStep int64 `gorm:"not null;default:0"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// TableName overrides the table name
+func (TOTPCredentialModel) TableName() string {
+	return "totp_credentials"
+}
+
+// ToDomain converts to domain entity
+func (m *TOTPCredentialModel) ToDomain() *mfa.TOTPCredential {
+	return &mfa.TOTPCredential{
+		ID:           m.ID,
+		UserID:       m.UserID,
+		Secret:       m.Secret,
+		ConfirmedAt:  m.ConfirmedAt,
+		LastUsedStep: m.LastUsedStep,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+// TOTPCredentialFromDomain converts from domain entity
+func TOTPCredentialFromDomain(c *mfa.TOTPCredential) *TOTPCredentialModel {
+	return &TOTPCredentialModel{
+		ID:           c.ID,
+		UserID:       c.UserID,
+		Secret:       c.Secret,
+		ConfirmedAt:  c.ConfirmedAt,
+		LastUsedStep: c.LastUsedStep,
+		CreatedAt:    c.CreatedAt,
+		UpdatedAt:    c.UpdatedAt,
+	}
+}
+
+// RecoveryCodeModel is the GORM model for MFA recovery codes
+type RecoveryCodeModel struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TableName overrides the table name
+func (RecoveryCodeModel) TableName() string {
+	return "mfa_recovery_codes"
+}
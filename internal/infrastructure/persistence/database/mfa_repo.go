@@ -0,0 +1,105 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/mfa"
+
+	"gorm.io/gorm"
+)
+
+// MFARepository implements mfa.Repository
+type MFARepository struct {
+	db *gorm.DB
+}
+
+// NewMFARepository creates a new repository
+func NewMFARepository(database *DB) mfa.Repository {
+	return &MFARepository{db: database.GormDB()}
+}
+
+// FindTOTP finds the user's credential
+func (r *MFARepository) FindTOTP(userID uint) (*mfa.TOTPCredential, error) {
+	var model TOTPCredentialModel
+	if err := r.db.Where("user_id = ?", userID).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, mfa.ErrTOTPNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// SaveTOTP creates or updates the user's credential
+func (r *MFARepository) SaveTOTP(c *mfa.TOTPCredential) error {
+	model := TOTPCredentialFromDomain(c)
+	if model.ID == 0 {
+		if err := r.db.Create(model).Error; err != nil {
+			return err
+		}
+		c.ID = model.ID
+		c.CreatedAt = model.CreatedAt
+		c.UpdatedAt = model.UpdatedAt
+		return nil
+	}
+	return r.db.Save(model).Error
+}
+
+// MarkStepUsed advances the last used step only if it moves forward
+func (r *MFARepository) MarkStepUsed(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&TOTPCredentialModel{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteTOTP removes the user's credential and recovery codes
+func (r *MFARepository) DeleteTOTP(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&TOTPCredentialModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&RecoveryCodeModel{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes discards the user's recovery codes and stores new ones
+func (r *MFARepository) ReplaceRecoveryCodes(userID uint, hashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCodeModel{}).Error; err != nil {
+			return err
+		}
+		if len(hashes) == 0 {
+			return nil
+		}
+		models := make([]RecoveryCodeModel, len(hashes))
+		for i, h := range hashes {
+			models[i] = RecoveryCodeModel{UserID: userID, CodeHash: h}
+		}
+		return tx.Create(&models).Error
+	})
+}
+
+// UseRecoveryCode marks an unused matching code as used
+func (r *MFARepository) UseRecoveryCode(userID uint, hash string, at time.Time) (bool, error) {
+	result := r.db.Model(&RecoveryCodeModel{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected >= 1, nil
+}
+
+// CountRecoveryCodes counts the user's unused recovery codes
+func (r *MFARepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&RecoveryCodeModel{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
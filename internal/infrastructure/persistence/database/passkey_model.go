@@ -0,0 +1,72 @@
+package database
+
+import (
+	"strings"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/passkey"
+)
+
+// PasskeyModel is the GORM model for WebAuthn passkeys
+type PasskeyModel struct {
+	ID              uint   `gorm:"primaryKey"`
+	UserID          uint   `gorm:"not null;index"`
+	Name            string `gorm:"size:100;not null"`
+	CredentialID    []byte `gorm:"size:1023;not null;uniqueIndex"` // the WebAuthn limit for credential IDs
+	PublicKey       []byte `gorm:"not null"`
+	AttestationType string `gorm:"size:32;not null"`
+	Transports      string `gorm:"size:200;not null"` // comma-separated
+	AAGUID          []byte `gorm:"size:16"`
+	SignCount       uint32 `gorm:"not null"`
+	BackupEligible  bool   `gorm:"not null"`
+	BackupState     bool   `gorm:"not null"`
+	LastUsedAt      *time.Time
+	CreatedAt       time.Time
+}
+
+// TableName overrides the table name
+func (PasskeyModel) TableName() string {
+	return "passkeys"
+}
+
+// ToDomain converts to domain entity
+func (m *PasskeyModel) ToDomain() *passkey.Passkey {
+	var transports []string
+	if m.Transports != "" {
+		transports = strings.Split(m.Transports, ",")
+	}
+	return &passkey.Passkey{
+		ID:              m.ID,
+		UserID:          m.UserID,
+		Name:            m.Name,
+		CredentialID:    m.CredentialID,
+		PublicKey:       m.PublicKey,
+		AttestationType: m.AttestationType,
+		Transports:      transports,
+		AAGUID:          m.AAGUID,
+		SignCount:       m.SignCount,
+		BackupEligible:  m.BackupEligible,
+		BackupState:     m.BackupState,
+		LastUsedAt:      m.LastUsedAt,
+		CreatedAt:       m.CreatedAt,
+	}
+}
+
+// PasskeyFromDomain converts from domain entity
+func PasskeyFromDomain(p *passkey.Passkey) *PasskeyModel {
+	return &PasskeyModel{
+		ID:              p.ID,
+		UserID:          p.UserID,
+		Name:            p.Name,
+		CredentialID:    p.CredentialID,
+		PublicKey:       p.PublicKey,
+		AttestationType: p.AttestationType,
+		Transports:      strings.Join(p.Transports, ","),
+		AAGUID:          p.AAGUID,
+		SignCount:       p.SignCount,
+		BackupEligible:  p.BackupEligible,
+		BackupState:     p.BackupState,
+		LastUsedAt:      p.LastUsedAt,
+		CreatedAt:       p.CreatedAt,
+	}
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"errors"
+
+	"go-ddd-scaffold/internal/domain/passkey"
+
+	"gorm.io/gorm"
+)
+
+// PasskeyRepository implements passkey.Repository
+type PasskeyRepository struct {
+	db *gorm.DB
+}
+
+// NewPasskeyRepository creates a new repository
+func NewPasskeyRepository(database *DB) passkey.Repository {
+	return &PasskeyRepository{db: database.GormDB()}
+}
+
+// FindByID finds by ID
+func (r *PasskeyRepository) FindByID(id uint) (*passkey.Passkey, error) {
+	var model PasskeyModel
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, translatePasskeyError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// FindByCredentialID finds by WebAuthn credential ID
+func (r *PasskeyRepository) FindByCredentialID(credentialID []byte) (*passkey.Passkey, error) {
+	var model PasskeyModel
+	if err := r.db.Where("credential_id = ?", credentialID).First(&model).Error; err != nil {
+		return nil, translatePasskeyError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// ListByUser returns the user's passkeys, oldest first
+func (r *PasskeyRepository) ListByUser(userID uint) ([]*passkey.Passkey, error) {
+	var models []PasskeyModel
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	entities := make([]*passkey.Passkey, len(models))
+	for i := range models {
+		entities[i] = models[i].ToDomain()
+	}
+	return entities, nil
+}
+
+// Save creates or updates
+func (r *PasskeyRepository) Save(p *passkey.Passkey) error {
+	model := PasskeyFromDomain(p)
+	if model.ID == 0 {
+		if err := r.db.Create(model).Error; err != nil {
+			return err
+		}
+		p.ID = model.ID
+		p.CreatedAt = model.CreatedAt
+		return nil
+	}
+	return r.db.Save(model).Error
+}
+
+// Delete removes a passkey
+func (r *PasskeyRepository) Delete(id uint) error {
+	return r.db.Delete(&PasskeyModel{}, id).Error
+}
+
+// DeleteByUser removes all of the user's passkeys
+func (r *PasskeyRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&PasskeyModel{}).Error
+}
+
+func translatePasskeyError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return passkey.ErrPasskeyNotFound
+	}
+	return err
+}
@@ -0,0 +1,16 @@
+package database
+
+import "time"
+
+// PasswordHistoryModel is the GORM model for previously used password hashes
+type PasswordHistoryModel struct {
+	ID           uint   `gorm:"primaryKey"`
+	UserID       uint   `gorm:"not null;index"`
+	PasswordHash string `gorm:"size:255;not null"`
+	CreatedAt    time.Time
+}
+
+// TableName overrides the table name
+func (PasswordHistoryModel) TableName() string {
+	return "password_histories"
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"go-ddd-scaffold/internal/domain/user"
+
+	"gorm.io/gorm"
+)
+
+// PasswordHistoryRepository implements user.PasswordHistoryRepository
+type PasswordHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordHistoryRepository creates a new repository
+func NewPasswordHistoryRepository(database *DB) user.PasswordHistoryRepository {
+	return &PasswordHistoryRepository{db: database.GormDB()}
+}
+
+// Recent returns the newest password hashes of the user, newest first
+func (r *PasswordHistoryRepository) Recent(userID uint, limit int) ([]string, error) {
+	var hashes []string
+	err := r.db.Model(&PasswordHistoryModel{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes).Error
+	return hashes, err
+}
+
+// Add records a password hash and prunes all but the newest keep entries
+func (r *PasswordHistoryRepository) Add(userID uint, passwordHash string, keep int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&PasswordHistoryModel{UserID: userID, PasswordHash: passwordHash}).Error; err != nil {
+			return err
+		}
+
+		// Pruned in Go: MySQL rejects LIMIT inside an IN subquery and OFFSET without LIMIT
+		var ids []uint
+		if err := tx.Model(&PasswordHistoryModel{}).
+			Where("user_id = ?", userID).
+			Order("id DESC").
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) <= keep {
+			return nil
+		}
+		return tx.Delete(&PasswordHistoryModel{}, ids[keep:]).Error
+	})
+}
+
+// DeleteByUser removes the user's history
+func (r *PasswordHistoryRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&PasswordHistoryModel{}).Error
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/rbac"
+)
+
+// RoleModel is the GORM model for roles
+type RoleModel struct {
+	ID          uint              `gorm:"primaryKey"`
+	Code        string            `gorm:"size:50;not null;uniqueIndex"`
+	Name        string            `gorm:"size:100;not null"`
+	Description string            `gorm:"size:255"`
+	RequireMFA  bool              `gorm:"not null;default:false"`
+	Permissions []PermissionModel `gorm:"many2many:role_permissions;joinForeignKey:RoleID;joinReferences:PermissionID"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TableName overrides the table name
+func (RoleModel) TableName() string {
+	return "roles"
+}
+
+// PermissionModel is the GORM model for permissions
+type PermissionModel struct {
+	ID          uint   `gorm:"primaryKey"`
+	Code        string `gorm:"size:100;not null;uniqueIndex"`
+	Description string `gorm:"size:255"`
+	CreatedAt   time.Time
+}
+
+// TableName overrides the table name
+func (PermissionModel) TableName() string {
+	return "permissions"
+}
+
+// ToDomain converts to domain entity
+func (m *RoleModel) ToDomain() *rbac.Role {
+	codes := make([]string, len(m.Permissions))
+	for i, p := range m.Permissions {
+		codes[i] = p.Code
+	}
+	return &rbac.Role{
+		ID:          m.ID,
+		Code:        m.Code,
+		Name:        m.Name,
+		Description: m.Description,
+		RequireMFA:  m.RequireMFA,
+		Permissions: codes,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// RoleFromDomain converts from domain entity (permissions are handled by the repository)
+func RoleFromDomain(r *rbac.Role) *RoleModel {
+	return &RoleModel{
+		ID:          r.ID,
+		Code:        r.Code,
+		Name:        r.Name,
+		Description: r.Description,
+		RequireMFA:  r.RequireMFA,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+// ToDomain converts to domain entity
+func (m *PermissionModel) ToDomain() *rbac.Permission {
+	return &rbac.Permission{
+		ID:          m.ID,
+		Code:        m.Code,
+		Description: m.Description,
+	}
+}
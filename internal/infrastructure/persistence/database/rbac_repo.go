@@ -0,0 +1,143 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"go-ddd-scaffold/internal/domain/rbac"
+
+	"gorm.io/gorm"
+)
+
+// RBACRepository implements rbac.Repository
+type RBACRepository struct {
+	db *gorm.DB
+}
+
+// NewRBACRepository creates a new repository
+func NewRBACRepository(database *DB) rbac.Repository {
+	return &RBACRepository{db: database.GormDB()}
+}
+
+// ListRoles returns all roles with their permissions
+func (r *RBACRepository) ListRoles() ([]*rbac.Role, error) {
+	var models []RoleModel
+	if err := r.db.Preload("Permissions").Order("id ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	roles := make([]*rbac.Role, len(models))
+	for i := range models {
+		roles[i] = models[i].ToDomain()
+	}
+	return roles, nil
+}
+
+// FindRoleByID finds by ID
+func (r *RBACRepository) FindRoleByID(id uint) (*rbac.Role, error) {
+	var model RoleModel
+	if err := r.db.Preload("Permissions").First(&model, id).Error; err != nil {
+		return nil, translateRoleError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// FindRoleByCode finds by code
+func (r *RBACRepository) FindRoleByCode(code string) (*rbac.Role, error) {
+	var model RoleModel
+	if err := r.db.Preload("Permissions").Where("code = ?", code).First(&model).Error; err != nil {
+		return nil, translateRoleError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// SaveRole creates or updates the role and replaces its permission set
+func (r *RBACRepository) SaveRole(role *rbac.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		model := RoleFromDomain(role)
+		if err := tx.Save(model).Error; err != nil {
+			return err
+		}
+
+		var perms []PermissionModel
+		if len(role.Permissions) > 0 {
+			if err := tx.Where("code IN ?", role.Permissions).Find(&perms).Error; err != nil {
+				return err
+			}
+			if len(perms) != len(role.Permissions) {
+				return fmt.Errorf("role %s references unknown permissions", role.Code)
+			}
+		}
+		if err := tx.Model(model).Association("Permissions").Replace(perms); err != nil {
+			return err
+		}
+
+		role.ID = model.ID
+		role.CreatedAt = model.CreatedAt
+		role.UpdatedAt = model.UpdatedAt
+		return nil
+	})
+}
+
+// DeleteRole deletes by ID together with its permission links
+func (r *RBACRepository) DeleteRole(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		model := &RoleModel{ID: id}
+		if err := tx.Model(model).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(model).Error
+	})
+}
+
+// ListPermissions returns all known permissions
+func (r *RBACRepository) ListPermissions() ([]*rbac.Permission, error) {
+	var models []PermissionModel
+	if err := r.db.Order("code ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	perms := make([]*rbac.Permission, len(models))
+	for i := range models {
+		perms[i] = models[i].ToDomain()
+	}
+	return perms, nil
+}
+
+// EnsurePermission creates the permission if missing
+func (r *RBACRepository) EnsurePermission(p *rbac.Permission) (bool, error) {
+	var model PermissionModel
+	err := r.db.Where("code = ?", p.Code).First(&model).Error
+	if err == nil {
+		p.ID = model.ID
+		return false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	model = PermissionModel{Code: p.Code, Description: p.Description}
+	if err := r.db.Create(&model).Error; err != nil {
+		return false, err
+	}
+	p.ID = model.ID
+	return true, nil
+}
+
+// GrantPermission adds a permission to a role
+func (r *RBACRepository) GrantPermission(roleCode, permissionCode string) error {
+	var role RoleModel
+	if err := r.db.Where("code = ?", roleCode).First(&role).Error; err != nil {
+		return translateRoleError(err)
+	}
+	var perm PermissionModel
+	if err := r.db.Where("code = ?", permissionCode).First(&perm).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&role).Association("Permissions").Append(&perm)
+}
+
+func translateRoleError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return rbac.ErrRoleNotFound
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/token"
+)
+
+// RefreshTokenModel is the GORM model for refresh tokens
+type RefreshTokenModel struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	FamilyID  string    `gorm:"size:36;not null;index"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// TableName overrides the table name
+func (RefreshTokenModel) TableName() string {
+	return "refresh_tokens"
+}
+
+// ToDomain converts to domain entity
+func (m *RefreshTokenModel) ToDomain() *token.RefreshToken {
+	return &token.RefreshToken{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		FamilyID:  m.FamilyID,
+		TokenHash: m.TokenHash,
+		ExpiresAt: m.ExpiresAt,
+		UsedAt:    m.UsedAt,
+		RevokedAt: m.RevokedAt,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// RefreshTokenFromDomain converts from domain entity
+func RefreshTokenFromDomain(t *token.RefreshToken) *RefreshTokenModel {
+	return &RefreshTokenModel{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		FamilyID:  t.FamilyID,
+		TokenHash: t.TokenHash,
+		ExpiresAt: t.ExpiresAt,
+		UsedAt:    t.UsedAt,
+		RevokedAt: t.RevokedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
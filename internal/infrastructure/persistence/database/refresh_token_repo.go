@@ -0,0 +1,74 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/token"
+
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository implements token.Repository
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository creates a new repository
+func NewRefreshTokenRepository(database *DB) token.Repository {
+	return &RefreshTokenRepository{db: database.GormDB()}
+}
+
+// FindByHash finds by token hash
+func (r *RefreshTokenRepository) FindByHash(hash string) (*token.RefreshToken, error) {
+	var model RefreshTokenModel
+	if err := r.db.Where("token_hash = ?", hash).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, token.ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// Create persists a new token
+func (r *RefreshTokenRepository) Create(entity *token.RefreshToken) error {
+	model := RefreshTokenFromDomain(entity)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+	entity.ID = model.ID
+	entity.CreatedAt = model.CreatedAt
+	return nil
+}
+
+// MarkUsed consumes the token only if nobody else did first
+func (r *RefreshTokenRepository) MarkUsed(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&RefreshTokenModel{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeFamily revokes every token in the family
+func (r *RefreshTokenRepository) RevokeFamily(familyID string, at time.Time) error {
+	return r.db.Model(&RefreshTokenModel{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+// RevokeByUser revokes every token of the user
+func (r *RefreshTokenRepository) RevokeByUser(userID uint, at time.Time) error {
+	return r.db.Model(&RefreshTokenModel{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+// DeleteExpired removes the user's expired tokens
+func (r *RefreshTokenRepository) DeleteExpired(userID uint, before time.Time) error {
+	return r.db.Where("user_id = ? AND expires_at < ?", userID, before).
+		Delete(&RefreshTokenModel{}).Error
+}
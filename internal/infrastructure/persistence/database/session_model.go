@@ -0,0 +1,58 @@
+package database
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/session"
+)
+
+// SessionModel is the GORM model for signed-in devices
+type SessionModel struct {
+	ID             uint      `gorm:"primaryKey"`
+	UserID         uint      `gorm:"not null;index"`
+	FamilyID       string    `gorm:"size:36;not null;uniqueIndex"`
+	UserAgent      string    `gorm:"size:255"`
+	IP             string    `gorm:"size:45"`
+	LastSeenAt     time.Time `gorm:"not null"`
+	ExpiresAt      time.Time `gorm:"not null"`
+	RevokedAt      *time.Time
+	CreatedAt      time.Time
+	ImpersonatorID uint `gorm:"not null;default:0"`
+}
+
+// TableName overrides the table name
+func (SessionModel) TableName() string {
+	return "sessions"
+}
+
+// ToDomain converts to domain entity
+func (m *SessionModel) ToDomain() *session.Session {
+	return &session.Session{
+		ID:             m.ID,
+		UserID:         m.UserID,
+		FamilyID:       m.FamilyID,
+		UserAgent:      m.UserAgent,
+		IP:             m.IP,
+		CreatedAt:      m.CreatedAt,
+		LastSeenAt:     m.LastSeenAt,
+		ExpiresAt:      m.ExpiresAt,
+		RevokedAt:      m.RevokedAt,
+		ImpersonatorID: m.ImpersonatorID,
+	}
+}
+
+// SessionFromDomain converts from domain entity
+func SessionFromDomain(s *session.Session) *SessionModel {
+	return &SessionModel{
+		ID:             s.ID,
+		UserID:         s.UserID,
+		FamilyID:       s.FamilyID,
+		UserAgent:      s.UserAgent,
+		IP:             s.IP,
+		LastSeenAt:     s.LastSeenAt,
+		ExpiresAt:      s.ExpiresAt,
+		RevokedAt:      s.RevokedAt,
+		CreatedAt:      s.CreatedAt,
+		ImpersonatorID: s.ImpersonatorID,
+	}
+}
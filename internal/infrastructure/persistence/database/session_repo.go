@@ -0,0 +1,92 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"go-ddd-scaffold/internal/domain/session"
+
+	"gorm.io/gorm"
+)
+
+// SessionRepository implements session.Repository
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository creates a new repository
+func NewSessionRepository(database *DB) session.Repository {
+	return &SessionRepository{db: database.GormDB()}
+}
+
+// FindByID finds by ID
+func (r *SessionRepository) FindByID(id uint) (*session.Session, error) {
+	var model SessionModel
+	if err := r.db.First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, session.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// ListActive returns the user's live sessions, newest first
+func (r *SessionRepository) ListActive(userID uint, now time.Time) ([]*session.Session, error) {
+	var models []SessionModel
+	if err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("id DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	entities := make([]*session.Session, len(models))
+	for i := range models {
+		entities[i] = models[i].ToDomain()
+	}
+	return entities, nil
+}
+
+// Create persists a new session
+func (r *SessionRepository) Create(entity *session.Session) error {
+	model := SessionFromDomain(entity)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+	entity.ID = model.ID
+	entity.CreatedAt = model.CreatedAt
+	return nil
+}
+
+// Touch updates last_seen_at unless it was already updated within interval,
+// so busy sessions do not cost a write per request
+func (r *SessionRepository) Touch(familyID string, at time.Time, interval time.Duration) error {
+	return r.db.Model(&SessionModel{}).
+		Where("family_id = ? AND last_seen_at < ?", familyID, at.Add(-interval)).
+		Update("last_seen_at", at).Error
+}
+
+// Extend records a refresh of the session
+func (r *SessionRepository) Extend(familyID string, at, expiresAt time.Time) error {
+	return r.db.Model(&SessionModel{}).
+		Where("family_id = ?", familyID).
+		Updates(map[string]any{"last_seen_at": at, "expires_at": expiresAt}).Error
+}
+
+// Revoke revokes the session of a token family
+func (r *SessionRepository) Revoke(familyID string, at time.Time) error {
+	return r.db.Model(&SessionModel{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+// RevokeByUser revokes every session of the user
+func (r *SessionRepository) RevokeByUser(userID uint, at time.Time) error {
+	return r.db.Model(&SessionModel{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+// DeleteExpired removes the user's expired sessions
+func (r *SessionRepository) DeleteExpired(userID uint, before time.Time) error {
+	return r.db.Where("user_id = ? AND expires_at < ?", userID, before).
+		Delete(&SessionModel{}).Error
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// TenantModel is the GORM model for tenants
+type TenantModel struct {
+	ID        uint   `gorm:"primaryKey"`
+	Code      string `gorm:"size:50;not null;uniqueIndex"`
+	Name      string `gorm:"size:100;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName overrides the table name
+func (TenantModel) TableName() string {
+	return "tenants"
+}
+
+// ToDomain converts to domain entity
+func (m *TenantModel) ToDomain() *tenant.Tenant {
+	return &tenant.Tenant{
+		ID:        m.ID,
+		Code:      m.Code,
+		Name:      m.Name,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+// TenantFromDomain converts from domain entity
+func TenantFromDomain(t *tenant.Tenant) *TenantModel {
+	return &TenantModel{
+		ID:        t.ID,
+		Code:      t.Code,
+		Name:      t.Name,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// EnsureDefaultTenant creates the default tenant that pre-existing rows
+// (whose tenant_id column defaults to it) belong to
+func EnsureDefaultTenant(db *gorm.DB) {
+	var count int64
+	db.Model(&TenantModel{}).Where("id = ?", tenant.DefaultID).Count(&count)
+	if count > 0 {
+		return
+	}
+
+	model := &TenantModel{ID: tenant.DefaultID, Code: tenant.DefaultCode, Name: "Default"}
+	if err := db.Create(model).Error; err != nil {
+		logger.Errorf("failed to create default tenant: %v", err)
+		return
+	}
+	logger.Info("default tenant created")
+}
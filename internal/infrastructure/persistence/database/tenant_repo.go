@@ -0,0 +1,72 @@
+package database
+
+import (
+	"errors"
+
+	"go-ddd-scaffold/internal/domain/tenant"
+
+	"gorm.io/gorm"
+)
+
+// TenantRepository implements tenant.Repository
+type TenantRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantRepository creates a new repository
+func NewTenantRepository(database *DB) tenant.Repository {
+	return &TenantRepository{db: database.GormDB()}
+}
+
+// FindByID finds by ID
+func (r *TenantRepository) FindByID(id uint) (*tenant.Tenant, error) {
+	var model TenantModel
+	if err := r.db.First(&model, id).Error; err != nil {
+		return nil, translateTenantError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// FindByCode finds by code
+func (r *TenantRepository) FindByCode(code string) (*tenant.Tenant, error) {
+	var model TenantModel
+	if err := r.db.Where("code = ?", code).First(&model).Error; err != nil {
+		return nil, translateTenantError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// List returns all tenants ordered by ID
+func (r *TenantRepository) List() ([]*tenant.Tenant, error) {
+	var models []TenantModel
+	if err := r.db.Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	entities := make([]*tenant.Tenant, len(models))
+	for i := range models {
+		entities[i] = models[i].ToDomain()
+	}
+	return entities, nil
+}
+
+// Save creates or updates
+func (r *TenantRepository) Save(entity *tenant.Tenant) error {
+	model := TenantFromDomain(entity)
+	if model.ID == 0 {
+		if err := r.db.Create(model).Error; err != nil {
+			return err
+		}
+		entity.ID = model.ID
+		entity.CreatedAt = model.CreatedAt
+		entity.UpdatedAt = model.UpdatedAt
+		return nil
+	}
+	return r.db.Save(model).Error
+}
+
+func translateTenantError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return tenant.ErrTenantNotFound
+	}
+	return err
+}
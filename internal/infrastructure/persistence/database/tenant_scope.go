@@ -0,0 +1,121 @@
+package database
+
+import (
+	"reflect"
+
+	"go-ddd-scaffold/internal/domain/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// tenantField is the model field that makes a model tenant-aware
+const tenantField = "TenantID"
+
+// registerTenantScope installs callbacks that confine every statement on a
+// model with a TenantID field to the tenant in the statement's context
+// (db.WithContext). Reads, updates and deletes get a tenant_id condition and
+// inserts are stamped with the tenant; the tenant of a row never changes.
+// Without a tenant in the context the statement fails with tenant.ErrNoTenant;
+// tenant.WithAllTenants lifts the scope for deliberate cross-tenant work. Raw
+// SQL is not rewritten.
+func registerTenantScope(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("tenant:create", stampTenant); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", scopeTenant); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", scopeTenantUpdate); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:delete", scopeTenant); err != nil {
+		return err
+	}
+	return cb.Row().Before("gorm:row").Register("tenant:row", scopeTenant)
+}
+
+// scopeTenant adds the tenant condition to queries, updates and deletes
+func scopeTenant(db *gorm.DB) {
+	field := tenantColumn(db)
+	if field == nil {
+		return
+	}
+	ctx := db.Statement.Context
+	if tenant.IsAllTenants(ctx) {
+		return
+	}
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		_ = db.AddError(tenant.ErrNoTenant)
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: id},
+	}})
+}
+
+// scopeTenantUpdate scopes updates and keeps them from moving rows to another
+// tenant, including zero-valued tenant fields written back by Save
+func scopeTenantUpdate(db *gorm.DB) {
+	scopeTenant(db)
+	if field := tenantColumn(db); field != nil {
+		db.Statement.Omits = append(db.Statement.Omits, field.DBName)
+	}
+}
+
+// stampTenant sets the tenant of new rows and rejects rows of another tenant
+func stampTenant(db *gorm.DB) {
+	field := tenantColumn(db)
+	if field == nil {
+		return
+	}
+	ctx := db.Statement.Context
+	all := tenant.IsAllTenants(ctx)
+	id, ok := tenant.FromContext(ctx)
+	if !ok && !all {
+		_ = db.AddError(tenant.ErrNoTenant)
+		return
+	}
+
+	stamp := func(rv reflect.Value) {
+		current, zero := field.ValueOf(ctx, rv)
+		switch {
+		case all:
+			if zero {
+				_ = db.AddError(tenant.ErrNoTenant)
+			}
+		case zero:
+			_ = db.AddError(field.Set(ctx, rv, id))
+		case current != any(id):
+			_ = db.AddError(tenant.ErrCrossTenant)
+		}
+	}
+	switch rv := db.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			stamp(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		stamp(rv)
+	}
+
+	// Save falls back to an upsert when its scoped UPDATE matches no row; on a
+	// key owned by another tenant that would overwrite the other tenant's row
+	if c, ok := db.Statement.Clauses["ON CONFLICT"]; ok && !all {
+		if onConflict, ok := c.Expression.(clause.OnConflict); ok && !onConflict.DoNothing {
+			db.Statement.AddClause(clause.OnConflict{Columns: onConflict.Columns, DoNothing: true})
+		}
+	}
+}
+
+// tenantColumn returns the tenant field of the statement's model, or nil for
+// models that are not tenant-aware, raw SQL and statements that already failed
+func tenantColumn(db *gorm.DB) *schema.Field {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.SQL.Len() > 0 {
+		return nil
+	}
+	return db.Statement.Schema.LookUpField(tenantField)
+}
@@ -0,0 +1,194 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/infrastructure/persistence/database"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	if err := logger.Init(&config.LogConfig{Level: "error", Format: "console", Output: "console"}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// note is tenant-aware through its TenantID field
+type note struct {
+	ID       uint `gorm:"primaryKey"`
+	TenantID uint `gorm:"index"`
+	Text     string
+}
+
+// setting is shared by all tenants
+type setting struct {
+	ID    uint `gorm:"primaryKey"`
+	Value string
+}
+
+const (
+	tenantA uint = 1
+	tenantB uint = 2
+)
+
+// newScopedDB opens a fresh SQLite database holding one note per tenant
+func newScopedDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	d, err := database.NewDB(&config.DatabaseConfig{Type: "sqlite", Path: filepath.Join(t.TempDir(), "test.db"), MaxIdleConns: 1, MaxOpenConns: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { d.Close() })
+	if err := d.AutoMigrate(&note{}, &setting{}); err != nil {
+		t.Fatal(err)
+	}
+	db := d.GormDB()
+	for _, id := range []uint{tenantA, tenantB} {
+		if err := db.WithContext(tenant.WithTenant(context.Background(), id)).Create(&note{Text: "seed"}).Error; err != nil {
+			t.Fatalf("seed tenant %d: %v", id, err)
+		}
+	}
+	return db
+}
+
+// notes returns every note regardless of tenant
+func notes(t *testing.T, db *gorm.DB) []note {
+	t.Helper()
+	var all []note
+	if err := db.WithContext(tenant.WithAllTenants(context.Background())).Order("id").Find(&all).Error; err != nil {
+		t.Fatal(err)
+	}
+	return all
+}
+
+func TestTenantScopeCreate(t *testing.T) {
+	db := newScopedDB(t)
+	inA := db.WithContext(tenant.WithTenant(context.Background(), tenantA))
+	all := db.WithContext(tenant.WithAllTenants(context.Background()))
+
+	n := &note{Text: "stamped"}
+	if err := inA.Create(n).Error; err != nil || n.TenantID != tenantA {
+		t.Fatalf("Create = tenant %d, %v, want tenant %d", n.TenantID, err, tenantA)
+	}
+	batch := []note{{Text: "one"}, {Text: "two", TenantID: tenantA}}
+	if err := inA.Create(&batch).Error; err != nil || batch[0].TenantID != tenantA {
+		t.Fatalf("Create batch = %+v, %v", batch, err)
+	}
+	if err := all.Create(&note{Text: "explicit", TenantID: tenantB}).Error; err != nil {
+		t.Fatalf("Create across tenants with an explicit tenant: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		db   *gorm.DB
+		row  any
+		want error
+	}{
+		"no tenant":                       {db.WithContext(context.Background()), &note{Text: "x"}, tenant.ErrNoTenant},
+		"another tenant":                  {inA, &note{Text: "x", TenantID: tenantB}, tenant.ErrCrossTenant},
+		"another tenant in a batch":       {inA, &[]note{{Text: "x"}, {Text: "y", TenantID: tenantB}}, tenant.ErrCrossTenant},
+		"all tenants without a tenant id": {all, &note{Text: "x"}, tenant.ErrNoTenant},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.db.Create(tc.row).Error; !errors.Is(err, tc.want) {
+				t.Fatalf("Create error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	// Models without a TenantID field are not scoped
+	if err := db.WithContext(context.Background()).Create(&setting{Value: "shared"}).Error; err != nil {
+		t.Fatalf("Create setting: %v", err)
+	}
+}
+
+func TestTenantScopeQuery(t *testing.T) {
+	db := newScopedDB(t)
+	inA := db.WithContext(tenant.WithTenant(context.Background(), tenantA))
+
+	var found []note
+	if err := inA.Find(&found).Error; err != nil || len(found) != 1 || found[0].TenantID != tenantA {
+		t.Fatalf("Find = %+v, %v, want tenant %d's note only", found, err, tenantA)
+	}
+	// Explicit conditions are combined with the tenant, never replace it
+	var other note
+	if err := inA.Where("tenant_id = ?", tenantB).First(&other).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("First of another tenant's note = %+v, %v", other, err)
+	}
+	var count int64
+	if err := inA.Model(&note{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("Count = %d, %v, want 1", count, err)
+	}
+	var text string
+	if err := inA.Model(&note{}).Select("text").Where("id = ?", 2).Row().Scan(&text); err == nil {
+		t.Fatalf("Row read another tenant's note: %q", text)
+	}
+	if got := notes(t, db); len(got) != 2 {
+		t.Fatalf("all tenants see %d notes, want 2", len(got))
+	}
+
+	if err := db.WithContext(context.Background()).Find(&found).Error; !errors.Is(err, tenant.ErrNoTenant) {
+		t.Fatalf("Find without a tenant = %v, want ErrNoTenant", err)
+	}
+
+	// Raw SQL is the caller's responsibility
+	if err := inA.Raw("SELECT count(*) FROM notes").Scan(&count).Error; err != nil || count != 2 {
+		t.Fatalf("raw count = %d, %v, want 2", count, err)
+	}
+}
+
+func TestTenantScopeUpdateAndDelete(t *testing.T) {
+	db := newScopedDB(t)
+	inA := db.WithContext(tenant.WithTenant(context.Background(), tenantA))
+	seeded := notes(t, db)
+	mine, theirs := seeded[0], seeded[1]
+
+	if res := inA.Model(&note{}).Where("id = ?", theirs.ID).Update("text", "hijacked"); res.Error != nil || res.RowsAffected != 0 {
+		t.Fatalf("Update of another tenant's note affected %d rows, %v", res.RowsAffected, res.Error)
+	}
+	if res := inA.Delete(&note{}, theirs.ID); res.Error != nil || res.RowsAffected != 0 {
+		t.Fatalf("Delete of another tenant's note affected %d rows, %v", res.RowsAffected, res.Error)
+	}
+
+	// Save cannot move a row to another tenant, nor clear its tenant
+	moved := mine
+	moved.Text, moved.TenantID = "moved", tenantB
+	if err := inA.Save(&moved).Error; err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := inA.Save(&note{ID: mine.ID, Text: "cleared"}).Error; err != nil {
+		t.Fatalf("Save without a tenant id: %v", err)
+	}
+	if err := inA.Model(&note{}).Where("id = ?", mine.ID).Updates(map[string]any{"text": "kept", "tenant_id": tenantB}).Error; err != nil {
+		t.Fatalf("Updates: %v", err)
+	}
+
+	// Save of another tenant's key falls back to an insert that must not
+	// overwrite the row
+	if err := inA.Save(&note{ID: theirs.ID, Text: "overwritten", TenantID: tenantA}).Error; err != nil {
+		t.Fatalf("Save of another tenant's key: %v", err)
+	}
+
+	got := notes(t, db)
+	if got[0].Text != "kept" || got[0].TenantID != tenantA {
+		t.Fatalf("own note = %+v, want text kept in tenant %d", got[0], tenantA)
+	}
+	if got[1] != theirs {
+		t.Fatalf("another tenant's note = %+v, want %+v unchanged", got[1], theirs)
+	}
+
+	if err := db.WithContext(context.Background()).Delete(&note{}, mine.ID).Error; !errors.Is(err, tenant.ErrNoTenant) {
+		t.Fatalf("Delete without a tenant = %v, want ErrNoTenant", err)
+	}
+	if res := inA.Delete(&note{}, mine.ID); res.Error != nil || res.RowsAffected != 1 {
+		t.Fatalf("Delete of an own note affected %d rows, %v", res.RowsAffected, res.Error)
+	}
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"time"
+
+	"go-ddd-scaffold/internal/domain/user"
+)
+
+// UserIdentityModel is the GORM model for links to external identity provider accounts
+type UserIdentityModel struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	Provider  string `gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject"`
+	Subject   string `gorm:"size:255;not null;uniqueIndex:idx_identity_provider_subject"`
+	CreatedAt time.Time
+}
+
+// TableName overrides the table name
+func (UserIdentityModel) TableName() string {
+	return "user_identities"
+}
+
+// ToDomain converts to domain entity
+func (m *UserIdentityModel) ToDomain() *user.Identity {
+	return &user.Identity{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Provider:  m.Provider,
+		Subject:   m.Subject,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// UserIdentityFromDomain converts from domain entity
+func UserIdentityFromDomain(i *user.Identity) *UserIdentityModel {
+	return &UserIdentityModel{
+		ID:        i.ID,
+		UserID:    i.UserID,
+		Provider:  i.Provider,
+		Subject:   i.Subject,
+		CreatedAt: i.CreatedAt,
+	}
+}
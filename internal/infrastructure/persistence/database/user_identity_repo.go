@@ -0,0 +1,68 @@
+package database
+
+import (
+	"errors"
+
+	"go-ddd-scaffold/internal/domain/user"
+
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository implements user.IdentityRepository
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository creates a new repository
+func NewUserIdentityRepository(database *DB) user.IdentityRepository {
+	return &UserIdentityRepository{db: database.GormDB()}
+}
+
+// Find finds the link for a provider account
+func (r *UserIdentityRepository) Find(provider, subject string) (*user.Identity, error) {
+	var model UserIdentityModel
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, user.ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// ListByUsers returns the users' links to a provider
+func (r *UserIdentityRepository) ListByUsers(provider string, userIDs []uint) ([]*user.Identity, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	var models []UserIdentityModel
+	if err := r.db.Where("provider = ? AND user_id IN ?", provider, userIDs).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	identities := make([]*user.Identity, len(models))
+	for i := range models {
+		identities[i] = models[i].ToDomain()
+	}
+	return identities, nil
+}
+
+// Save creates a link
+func (r *UserIdentityRepository) Save(i *user.Identity) error {
+	model := UserIdentityFromDomain(i)
+	if err := r.db.Create(model).Error; err != nil {
+		return err
+	}
+	i.ID = model.ID
+	i.CreatedAt = model.CreatedAt
+	return nil
+}
+
+// Delete removes a link
+func (r *UserIdentityRepository) Delete(id uint) error {
+	return r.db.Delete(&UserIdentityModel{}, id).Error
+}
+
+// DeleteByUser removes the user's links
+func (r *UserIdentityRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&UserIdentityModel{}).Error
+}
@@ -1,46 +1,113 @@
 package database
 
 import (
+	"context"
 	"time"
 
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/domain/user"
 	"go-ddd-scaffold/pkg/logger"
+	"go-ddd-scaffold/pkg/password"
 
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-// UserModel is the database model for users (auth only)
+// UserModel is the database model for users
 type UserModel struct {
-	ID        uint   `gorm:"primarykey"`
-	Username  string `gorm:"uniqueIndex;size:50;not null"`
-	Password  string `gorm:"size:255;not null"`
-	Role      string `gorm:"size:20;default:admin"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID                 uint   `gorm:"primarykey"`
+	TenantID           uint   `gorm:"not null;default:1;uniqueIndex:idx_users_tenant_username"`
+	Username           string `gorm:"uniqueIndex:idx_users_tenant_username;size:50;not null"`
+	Password           string `gorm:"size:255;not null"`
+	Nickname           string `gorm:"size:100"`
+	Email              string `gorm:"size:255;index"`
+	EmailVerifiedAt    *time.Time
+	Role               string `gorm:"size:50;default:user"`
+	Status             string `gorm:"size:20;default:active;index"`
+	MustChangePassword bool   `gorm:"not null;default:false"`
+	ServiceAccount     bool   `gorm:"not null;default:false"`
+	SuperAdmin         bool   `gorm:"not null;default:false"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
 }
 
 func (UserModel) TableName() string {
 	return "users"
 }
 
-// EnsureDefaultAdmin creates the default admin user if no users exist
-func EnsureDefaultAdmin(db *gorm.DB) {
+// ToDomain converts to domain entity
+func (m *UserModel) ToDomain() *user.User {
+	return &user.User{
+		ID:                 m.ID,
+		TenantID:           m.TenantID,
+		Username:           m.Username,
+		PasswordHash:       m.Password,
+		Nickname:           m.Nickname,
+		Email:              m.Email,
+		EmailVerifiedAt:    m.EmailVerifiedAt,
+		Role:               user.Role(m.Role),
+		Status:             user.Status(m.Status),
+		MustChangePassword: m.MustChangePassword,
+		ServiceAccount:     m.ServiceAccount,
+		SuperAdmin:         m.SuperAdmin,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
+	}
+}
+
+// UserFromDomain converts from domain entity
+func UserFromDomain(u *user.User) *UserModel {
+	return &UserModel{
+		ID:                 u.ID,
+		TenantID:           u.TenantID,
+		Username:           u.Username,
+		Password:           u.PasswordHash,
+		Nickname:           u.Nickname,
+		Email:              u.Email,
+		EmailVerifiedAt:    u.EmailVerifiedAt,
+		Role:               string(u.Role),
+		Status:             string(u.Status),
+		MustChangePassword: u.MustChangePassword,
+		ServiceAccount:     u.ServiceAccount,
+		SuperAdmin:         u.SuperAdmin,
+		CreatedAt:          u.CreatedAt,
+		UpdatedAt:          u.UpdatedAt,
+	}
+}
+
+// EnsureDefaultAdmin creates the default admin user, a super-admin of the
+// default tenant, if no users exist. Databases created before tenancy get
+// their oldest admin promoted to super-admin instead.
+func EnsureDefaultAdmin(db *gorm.DB, hasher *password.Hasher) {
+	ctx := tenant.WithAllTenants(context.Background())
+	db = db.WithContext(ctx)
+
+	// Usernames used to be unique across the whole table
+	if m := db.Migrator(); m.HasIndex(&UserModel{}, "idx_users_username") {
+		if err := m.DropIndex(&UserModel{}, "idx_users_username"); err != nil {
+			logger.Errorf("failed to drop global username index: %v", err)
+		}
+	}
+
 	var count int64
 	db.Model(&UserModel{}).Count(&count)
 	if count > 0 {
+		promoteSuperAdmin(db)
 		return
 	}
 
-	hashed, err := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.DefaultCost)
+	hashed, err := hasher.Hash(ctx, "admin123")
 	if err != nil {
 		logger.Errorf("failed to hash default password: %v", err)
 		return
 	}
 
 	admin := &UserModel{
-		Username: "admin",
-		Password: string(hashed),
-		Role:     "admin",
+		TenantID:   tenant.DefaultID,
+		Username:   "admin",
+		Password:   hashed,
+		Role:       string(user.RoleAdmin),
+		Status:     string(user.StatusActive),
+		SuperAdmin: true,
 	}
 	if err := db.Create(admin).Error; err != nil {
 		logger.Errorf("failed to create default admin: %v", err)
@@ -48,3 +115,24 @@ func EnsureDefaultAdmin(db *gorm.DB) {
 	}
 	logger.Info("default admin created: admin / admin123")
 }
+
+// promoteSuperAdmin makes the oldest admin of the default tenant a
+// super-admin when there is none
+func promoteSuperAdmin(db *gorm.DB) {
+	var count int64
+	db.Model(&UserModel{}).Where("super_admin = ?", true).Count(&count)
+	if count > 0 {
+		return
+	}
+
+	var admin UserModel
+	err := db.Where("tenant_id = ? AND role = ?", tenant.DefaultID, string(user.RoleAdmin)).Order("id").First(&admin).Error
+	if err != nil {
+		return
+	}
+	if err := db.Model(&admin).Update("super_admin", true).Error; err != nil {
+		logger.Errorf("failed to promote super-admin: %v", err)
+		return
+	}
+	logger.Infof("user %s promoted to super-admin", admin.Username)
+}
@@ -0,0 +1,214 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-ddd-scaffold/internal/domain/user"
+
+	"gorm.io/gorm"
+)
+
+// userSearchColumns maps searchable fields to columns
+var userSearchColumns = map[user.Field]string{
+	user.FieldID:        "id",
+	user.FieldUsername:  "username",
+	user.FieldNickname:  "nickname",
+	user.FieldEmail:     "email",
+	user.FieldRole:      "role",
+	user.FieldStatus:    "status",
+	user.FieldCreatedAt: "created_at",
+	user.FieldUpdatedAt: "updated_at",
+}
+
+// likeEscaper escapes LIKE wildcards; '!' works as ESCAPE character on every
+// supported database, unlike the backslash
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// UserRepository implements user.Repository
+type UserRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository creates a new repository
+func NewUserRepository(database *DB) user.Repository {
+	return &UserRepository{db: database.GormDB()}
+}
+
+// FindByID finds by ID
+func (r *UserRepository) FindByID(ctx context.Context, id uint) (*user.User, error) {
+	var model UserModel
+	if err := r.db.WithContext(ctx).First(&model, id).Error; err != nil {
+		return nil, translateUserError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// FindByUsername finds by username
+func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*user.User, error) {
+	var model UserModel
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&model).Error; err != nil {
+		return nil, translateUserError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// FindByEmail finds by email, preferring a user who verified it
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	var model UserModel
+	err := r.db.WithContext(ctx).Where("email = ?", email).
+		Order("CASE WHEN email_verified_at IS NULL THEN 1 ELSE 0 END, id").Take(&model).Error
+	if err != nil {
+		return nil, translateUserError(err)
+	}
+	return model.ToDomain(), nil
+}
+
+// List returns paginated results
+func (r *UserRepository) List(ctx context.Context, page, pageSize int, keyword string, status user.Status) ([]*user.User, int64, error) {
+	var models []UserModel
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&UserModel{})
+
+	if keyword != "" {
+		query = query.Where("username LIKE ? OR nickname LIKE ? OR email LIKE ?", "%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	entities := make([]*user.User, len(models))
+	for i := range models {
+		entities[i] = models[i].ToDomain()
+	}
+
+	return entities, total, nil
+}
+
+// Search returns the users matching every criterion ordered by ID
+func (r *UserRepository) Search(ctx context.Context, criteria []user.Criterion, offset, limit int) ([]*user.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&UserModel{})
+	for _, c := range criteria {
+		col, ok := userSearchColumns[c.Field]
+		if !ok {
+			return nil, 0, fmt.Errorf("unsupported search field: %s", c.Field)
+		}
+		cond, args, err := searchCondition(c, col)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where(cond, args...)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if limit == 0 {
+		return []*user.User{}, total, nil
+	}
+
+	var models []UserModel
+	if err := query.Offset(offset).Limit(limit).Order("id ASC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	entities := make([]*user.User, len(models))
+	for i := range models {
+		entities[i] = models[i].ToDomain()
+	}
+	return entities, total, nil
+}
+
+// searchCondition builds the WHERE clause of one criterion
+func searchCondition(c user.Criterion, col string) (string, []any, error) {
+	foldCase := c.Field == user.FieldUsername || c.Field == user.FieldNickname || c.Field == user.FieldEmail
+	if c.Op == user.OpPresent {
+		if foldCase || c.Field == user.FieldRole || c.Field == user.FieldStatus {
+			return col + " IS NOT NULL AND " + col + " <> ''", nil, nil
+		}
+		return col + " IS NOT NULL", nil, nil
+	}
+
+	value := c.Value
+	if text, ok := value.(string); ok && foldCase {
+		col, value = "LOWER("+col+")", strings.ToLower(text)
+	}
+	switch c.Op {
+	case user.OpEq:
+		return col + " = ?", []any{value}, nil
+	case user.OpNe:
+		return col + " <> ?", []any{value}, nil
+	case user.OpGt:
+		return col + " > ?", []any{value}, nil
+	case user.OpGe:
+		return col + " >= ?", []any{value}, nil
+	case user.OpLt:
+		return col + " < ?", []any{value}, nil
+	case user.OpLe:
+		return col + " <= ?", []any{value}, nil
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return "", nil, fmt.Errorf("operator %s requires a string value", c.Op)
+	}
+	pattern := likeEscaper.Replace(text)
+	switch c.Op {
+	case user.OpContains:
+		pattern = "%" + pattern + "%"
+	case user.OpStartsWith:
+		pattern += "%"
+	case user.OpEndsWith:
+		pattern = "%" + pattern
+	default:
+		return "", nil, fmt.Errorf("unsupported search operator: %s", c.Op)
+	}
+	return col + " LIKE ? ESCAPE '!'", []any{pattern}, nil
+}
+
+// CountByRole counts users assigned the role
+func (r *UserRepository) CountByRole(ctx context.Context, role user.Role) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&UserModel{}).Where("role = ?", string(role)).Count(&count).Error
+	return count, err
+}
+
+// Save creates or updates
+func (r *UserRepository) Save(ctx context.Context, entity *user.User) error {
+	model := UserFromDomain(entity)
+	if model.ID == 0 {
+		if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+			return err
+		}
+		entity.ID = model.ID
+		entity.TenantID = model.TenantID
+		entity.CreatedAt = model.CreatedAt
+		entity.UpdatedAt = model.UpdatedAt
+		return nil
+	}
+	return r.db.WithContext(ctx).Save(model).Error
+}
+
+// Delete deletes by ID
+func (r *UserRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&UserModel{}, id).Error
+}
+
+func translateUserError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return user.ErrUserNotFound
+	}
+	return err
+}
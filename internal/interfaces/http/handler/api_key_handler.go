@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler handles personal access tokens and service-account keys
+type APIKeyHandler struct {
+	svc *service.APIKeyAppService
+}
+
+// NewAPIKeyHandler creates a new handler
+func NewAPIKeyHandler(svc *service.APIKeyAppService) *APIKeyHandler {
+	return &APIKeyHandler{svc: svc}
+}
+
+// List returns the current user's API keys
+// @Summary  List my API keys
+// @Tags     APIKey
+// @Security Bearer
+// @Success  200 {object} response.Response{data=[]dto.APIKeyResponse}
+// @Router   /auth/api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	items, err := h.svc.List(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, items)
+}
+
+// Create issues a personal access token; the key is shown only once
+// @Summary  Create API key
+// @Tags     APIKey
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.CreateAPIKeyRequest true "key parameters"
+// @Success  200  {object} response.Response{data=dto.APIKeyCreatedResponse}
+// @Router   /auth/api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.Create(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, item)
+}
+
+// Revoke revokes one of the current user's API keys
+// @Summary  Revoke API key
+// @Tags     APIKey
+// @Security Bearer
+// @Param    id path int true "key ID"
+// @Success  200 {object} response.Response
+// @Router   /auth/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	if err := h.svc.Revoke(c.Request.Context(), c.GetUint("user_id"), uint(id)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
+
+// ListForUser returns a user's API keys
+// @Summary  List user API keys
+// @Tags     User
+// @Security Bearer
+// @Param    id path int true "user ID"
+// @Success  200 {object} response.Response{data=[]dto.APIKeyResponse}
+// @Router   /users/{id}/api-keys [get]
+func (h *APIKeyHandler) ListForUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	items, err := h.svc.List(c.Request.Context(), uint(id))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, items)
+}
+
+// CreateForUser issues a key for a service account; the key is shown only once
+// @Summary  Create service account API key
+// @Tags     User
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    id   path int                      true "service account ID"
+// @Param    body body dto.CreateAPIKeyRequest  true "key parameters"
+// @Success  200  {object} response.Response{data=dto.APIKeyCreatedResponse}
+// @Router   /users/{id}/api-keys [post]
+func (h *APIKeyHandler) CreateForUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.CreateForServiceAccount(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, item)
+}
+
+// RevokeForUser revokes any user's API key, e.g. a leaked personal access token
+// @Summary  Revoke user API key
+// @Tags     User
+// @Security Bearer
+// @Param    id     path int true "user ID"
+// @Param    key_id path int true "key ID"
+// @Success  200 {object} response.Response
+// @Router   /users/{id}/api-keys/{key_id} [delete]
+func (h *APIKeyHandler) RevokeForUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+	keyID, err := strconv.ParseUint(c.Param("key_id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid key ID")
+		return
+	}
+
+	if err := h.svc.Revoke(c.Request.Context(), uint(id), uint(keyID)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
@@ -1,132 +1,332 @@
 package handler
 
 import (
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
 	"strings"
-	"time"
 
-	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/errcode"
 	"go-ddd-scaffold/pkg/response"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // AuthHandler handles authentication endpoints
 type AuthHandler struct {
-	jwtCfg *config.JWTConfig
+	svc *service.AuthAppService
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(jwtCfg *config.JWTConfig) *AuthHandler {
-	return &AuthHandler{jwtCfg: jwtCfg}
+func NewAuthHandler(svc *service.AuthAppService) *AuthHandler {
+	return &AuthHandler{svc: svc}
 }
 
-type loginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
-}
-
-type tokenResponse struct {
-	Token     string `json:"token"`
-	ExpiresAt int64  `json:"expires_at"`
-}
-
-// Pre-computed hash for default password "admin123" (demo only)
-var defaultAdminHash, _ = bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.DefaultCost)
-
 // Login handles user login
 // @Summary  User login
 // @Tags     Auth
 // @Accept   json
 // @Produce  json
-// @Param    body body loginRequest true "Login credentials"
-// @Success  200  {object} response.Response{data=tokenResponse}
+// @Param    body body dto.LoginRequest true "Login credentials"
+// @Success  200  {object} response.Response{data=dto.TokenResponse}
+// @Failure  401  {object} response.Response "Invalid credentials; code 10010 asks for a captcha, 10011 rejects its answer"
+// @Failure  429  {object} response.Response "Too many failures; see Retry-After header"
+// @Failure  503  {object} response.Response "Password hashing saturated or LDAP directory unavailable, retry later"
 // @Router   /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
-	var req loginRequest
+	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, "invalid parameters")
 		return
 	}
 
-	// TODO: Query user from database; using default admin for demo
-	if req.Username != "admin" || bcrypt.CompareHashAndPassword(defaultAdminHash, []byte(req.Password)) != nil {
-		response.Unauthorized(c, "invalid username or password")
+	tokens, err := h.svc.Login(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		loginError(c, err)
 		return
 	}
 
-	token, expiresAt, err := h.generateToken(req.Username, "admin")
+	response.Success(c, tokens)
+}
+
+// Captcha issues an image challenge for Login; each one can be answered once
+// @Summary  Get login captcha
+// @Tags     Auth
+// @Produce  json
+// @Success  200 {object} response.Response{data=dto.CaptchaResponse}
+// @Failure  429 {object} response.Response "Too many captchas; see Retry-After header"
+// @Router   /auth/captcha [get]
+func (h *AuthHandler) Captcha(c *gin.Context) {
+	challenge, err := h.svc.NewCaptcha(c.Request.Context())
 	if err != nil {
-		response.ServerError(c, "failed to generate token")
+		response.FromError(c, err)
 		return
 	}
 
-	response.Success(c, tokenResponse{Token: token, ExpiresAt: expiresAt})
+	response.Success(c, challenge)
 }
 
-// RefreshToken refreshes the JWT token
-// @Summary  Refresh token
+// VerifyMFA completes a login that returned an "mfa_pending" token
+// @Summary  Verify second factor
 // @Tags     Auth
 // @Security Bearer
-// @Success  200 {object} response.Response{data=tokenResponse}
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.MFACodeRequest true "TOTP code or recovery code"
+// @Success  200  {object} response.Response{data=dto.TokenResponse}
+// @Failure  429  {object} response.Response "Too many failures; see Retry-After header"
+// @Router   /auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+
+	claims := currentClaims(c)
+	if claims == nil {
+		response.Unauthorized(c, "invalid or expired token")
+		return
+	}
+
+	tokens, err := h.svc.VerifyMFA(c.Request.Context(), claims, &req, clientInfo(c))
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+
+	response.Success(c, tokens)
+}
+
+// loginError renders lockouts as 429 with Retry-After, other errors by code
+func loginError(c *gin.Context, err error) {
+	var locked *service.LockedError
+	if errors.As(err, &locked) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, response.Response{
+			Code:    errcode.ErrAccountLocked.Code,
+			Message: errcode.ErrAccountLocked.Message,
+		})
+		return
+	}
+	response.FromError(c, err)
+}
+
+// clientInfo describes the requesting device for the session started by a login
+func clientInfo(c *gin.Context) service.ClientInfo {
+	return service.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// RefreshToken exchanges a refresh token for a new token pair.
+// Each refresh token is single-use; replaying one revokes its whole family.
+// @Summary  Refresh token
+// @Tags     Auth
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.RefreshTokenRequest true "Refresh token"
+// @Success  200  {object} response.Response{data=dto.TokenResponse}
 // @Router   /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	username, _ := c.Get("username")
-	role, _ := c.Get("role")
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
 
-	token, expiresAt, err := h.generateToken(username.(string), role.(string))
+	tokens, err := h.svc.Refresh(c.Request.Context(), &req)
 	if err != nil {
-		response.ServerError(c, "failed to generate token")
+		response.FromError(c, err)
 		return
 	}
 
-	response.Success(c, tokenResponse{Token: token, ExpiresAt: expiresAt})
+	response.Success(c, tokens)
 }
 
-func (h *AuthHandler) generateToken(username, role string) (string, int64, error) {
-	expiresAt := time.Now().Add(time.Duration(h.jwtCfg.Expire) * time.Hour)
-	claims := jwt.MapClaims{
-		"username": username,
-		"role":     role,
-		"exp":      expiresAt.Unix(),
-		"iat":      time.Now().Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString([]byte(h.jwtCfg.Secret))
-	return tokenStr, expiresAt.Unix(), err
+// Logout ends the current session, or all sessions of the user with {"all": true}
+// @Summary  Logout
+// @Tags     Auth
+// @Security Bearer
+// @Accept   json
+// @Param    body body dto.LogoutRequest false "Logout options"
+// @Success  200 {object} response.Response
+// @Router   /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.ParamError(c, "invalid parameters")
+			return
+		}
+	}
+
+	claims := currentClaims(c)
+	if claims == nil {
+		response.Unauthorized(c, "invalid or expired token")
+		return
+	}
+
+	if err := h.svc.Logout(c.Request.Context(), claims, &req); err != nil {
+		response.ServerError(c, "logout failed")
+		return
+	}
+
+	response.OK(c)
+}
+
+// ChangePassword changes the current user's password
+// All existing tokens are revoked and a fresh pair is returned.
+// Also accepts tokens restricted to a forced password change. Wrong old
+// passwords count towards the login lockout (429 with Retry-After).
+// @Summary  Change password
+// @Tags     Auth
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.ChangePasswordRequest true "Old and new password"
+// @Success  200  {object} response.Response{data=dto.TokenResponse}
+// @Failure  429  {object} response.Response "Too many failures; see Retry-After header"
+// @Router   /auth/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	claims := currentClaims(c)
+	if claims == nil {
+		response.Unauthorized(c, "invalid or expired token")
+		return
+	}
+
+	tokens, err := h.svc.ChangePassword(c.Request.Context(), claims, &req, clientInfo(c))
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+
+	response.Success(c, tokens)
 }
 
-// AuthMiddleware validates JWT tokens
-func AuthMiddleware(jwtCfg *config.JWTConfig) gin.HandlerFunc {
+// AuthMiddleware authenticates the request with a JWT (rejecting revoked ones)
+// or, when keys is non-nil, with an API key sent as a bearer credential or in
+// the X-API-Key header. Pass nil keys for account routes API keys must not reach.
+// Restricted tokens are rejected unless their restriction is listed in allow.
+// Responses to impersonation tokens name the administrator in X-Impersonated-By.
+// The request context is scoped to the caller's tenant.
+func AuthMiddleware(tokens *service.TokenService, keys *service.APIKeyAppService, allow ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		auth := c.GetHeader("Authorization")
-		if auth == "" || !strings.HasPrefix(auth, "Bearer ") {
-			response.Unauthorized(c, "valid authentication token required")
+		credential := c.GetHeader("X-API-Key")
+		if credential == "" {
+			auth := c.GetHeader("Authorization")
+			if !strings.HasPrefix(auth, "Bearer ") {
+				response.Unauthorized(c, "valid authentication token required")
+				c.Abort()
+				return
+			}
+			credential = strings.TrimPrefix(auth, "Bearer ")
+		} else if !service.IsAPIKey(credential) {
+			response.FromError(c, errcode.ErrInvalidToken)
 			c.Abort()
 			return
 		}
 
-		tokenStr := strings.TrimPrefix(auth, "Bearer ")
-		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-			return []byte(jwtCfg.Secret), nil
-		})
+		var claims *service.Claims
+		var err error
+		switch {
+		case !service.IsAPIKey(credential):
+			claims, err = tokens.Parse(c.Request.Context(), credential)
+		case keys == nil:
+			err = errcode.ErrAPIKeyNotAllowed
+		default:
+			claims, err = keys.Authenticate(c.Request.Context(), credential)
+		}
+		if err != nil {
+			response.FromError(c, err)
+			c.Abort()
+			return
+		}
+
+		if claims.Restriction != "" && !slices.Contains(allow, claims.Restriction) {
+			response.FromError(c, service.RestrictionError(claims.Restriction))
+			c.Abort()
+			return
+		}
+
+		if claims.IsImpersonated() {
+			c.Header(ImpersonationHeader, claims.Actor.Username)
+		}
 
-		if err != nil || !token.Valid {
-			response.Unauthorized(c, "invalid or expired token")
+		c.Request = c.Request.WithContext(claims.Context(c.Request.Context()))
+		c.Set("claims", claims)
+		c.Set("user_id", claims.UserID)
+		c.Set("username", claims.Username)
+		c.Set("role", claims.Role)
+		c.Set("tenant_id", claims.TenantID)
+		c.Next()
+	}
+}
+
+// TenantMiddleware lets super-admins act in another tenant by sending its
+// code in the X-Tenant header, or "*" to work across all tenants. Use after
+// AuthMiddleware; other callers sending the header are refused.
+func TenantMiddleware(tenants *service.TenantAppService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		override := c.GetHeader("X-Tenant")
+		claims := currentClaims(c)
+		if override == "" || claims == nil {
+			c.Next()
+			return
+		}
+
+		ctx, err := tenants.Scope(c.Request.Context(), claims, override)
+		if err != nil {
+			response.FromError(c, err)
 			c.Abort()
 			return
 		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			response.Unauthorized(c, "failed to parse token")
+// RequireSession rejects requests authenticated with an API key; use after
+// AuthMiddleware on routes that share a group with API-key-enabled routes
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims := currentClaims(c); claims == nil || claims.IsAPIKey() {
+			response.FromError(c, errcode.ErrAPIKeyNotAllowed)
 			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
 
-		c.Set("username", claims["username"])
-		c.Set("role", claims["role"])
+// DenyImpersonation rejects impersonation tokens; use after AuthMiddleware on
+// routes that manage the user's own credentials, which an administrator
+// acting as the user must not change
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if currentClaims(c).IsImpersonated() {
+			response.FromError(c, errcode.ErrImpersonationNotAllowed)
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
+
+// currentClaims returns the claims stored by AuthMiddleware
+func currentClaims(c *gin.Context) *service.Claims {
+	v, ok := c.Get("claims")
+	if !ok {
+		return nil
+	}
+	claims, _ := v.(*service.Claims)
+	return claims
+}
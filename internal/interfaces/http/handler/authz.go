@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authz builds permission-checking middleware; use after AuthMiddleware
+type Authz struct {
+	rbac *service.RBACAppService
+}
+
+// NewAuthz creates a new authorizer
+func NewAuthz(rbac *service.RBACAppService) *Authz {
+	return &Authz{rbac: rbac}
+}
+
+// RequirePermission rejects requests whose role (and API key scopes, if any)
+// do not grant the permission
+func (a *Authz) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := currentClaims(c)
+		if claims == nil {
+			response.FromError(c, errcode.ErrPermissionDenied)
+			c.Abort()
+			return
+		}
+		ok, err := a.rbac.Authorize(c.Request.Context(), claims, permission)
+		if err != nil {
+			logger.Errorf("permission check failed: %v", err)
+			response.ServerError(c, "permission check failed")
+			c.Abort()
+			return
+		}
+		if !ok {
+			response.FromError(c, errcode.ErrPermissionDenied)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSuperAdmin rejects requests from anyone but super-admins; use after
+// AuthMiddleware on routes that affect every tenant
+func RequireSuperAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims := currentClaims(c); claims == nil || !claims.SuperAdmin {
+			response.FromError(c, errcode.ErrSuperAdminRequired)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
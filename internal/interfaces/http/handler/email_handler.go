@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailHandler handles email verification and password reset links
+type EmailHandler struct {
+	svc *service.EmailAppService
+}
+
+// NewEmailHandler creates a new handler
+func NewEmailHandler(svc *service.EmailAppService) *EmailHandler {
+	return &EmailHandler{svc: svc}
+}
+
+// ForgotPassword mails a password reset link to a verified address. The
+// response is the same whether or not an account matched.
+// @Summary  Forgot password
+// @Tags     Auth
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.ForgotPasswordRequest true "Tenant and email address"
+// @Success  200  {object} response.Response
+// @Router   /auth/forgot-password [post]
+func (h *EmailHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+
+	if err := h.svc.ForgotPassword(c.Request.Context(), &req); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
+
+// ResetPassword sets a new password with the token from a reset link and
+// signs the user out everywhere
+// @Summary  Reset password
+// @Tags     Auth
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.ResetPasswordByTokenRequest true "Reset token and new password"
+// @Success  200  {object} response.Response
+// @Router   /auth/reset-password [post]
+func (h *EmailHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordByTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+
+	if err := h.svc.ResetPassword(c.Request.Context(), &req); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
+
+// VerifyEmail confirms an email address with the token from a verification link
+// @Summary  Verify email
+// @Tags     Auth
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.VerifyEmailRequest true "Verification token"
+// @Success  200  {object} response.Response
+// @Router   /auth/email/verify [post]
+func (h *EmailHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+
+	if err := h.svc.VerifyEmail(c.Request.Context(), &req); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
+
+// ResendVerification mails a new verification link to the caller's address
+// @Summary  Resend verification email
+// @Tags     Auth
+// @Security Bearer
+// @Success  200 {object} response.Response
+// @Router   /auth/email/verification [post]
+func (h *EmailHandler) ResendVerification(c *gin.Context) {
+	claims := currentClaims(c)
+	if claims == nil {
+		response.Unauthorized(c, "invalid or expired token")
+		return
+	}
+
+	if err := h.svc.ResendVerification(c.Request.Context(), claims); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
@@ -37,7 +37,7 @@ func (h *ExampleHandler) List(c *gin.Context) {
 		return
 	}
 
-	items, total, err := h.svc.List(&req)
+	items, total, err := h.svc.List(c.Request.Context(), &req)
 	if err != nil {
 		response.ServerError(c, "query failed")
 		return
@@ -62,7 +62,7 @@ func (h *ExampleHandler) Create(c *gin.Context) {
 		return
 	}
 
-	item, err := h.svc.Create(&req)
+	item, err := h.svc.Create(c.Request.Context(), &req)
 	if err != nil {
 		response.ServerError(c, "create failed: "+err.Error())
 		return
@@ -85,7 +85,7 @@ func (h *ExampleHandler) Get(c *gin.Context) {
 		return
 	}
 
-	item, err := h.svc.GetByID(uint(id))
+	item, err := h.svc.GetByID(c.Request.Context(), uint(id))
 	if err != nil {
 		response.NotFound(c, "record not found")
 		return
@@ -116,7 +116,7 @@ func (h *ExampleHandler) Update(c *gin.Context) {
 		return
 	}
 
-	item, err := h.svc.Update(uint(id), &req)
+	item, err := h.svc.Update(c.Request.Context(), uint(id), &req)
 	if err != nil {
 		response.ServerError(c, "update failed: "+err.Error())
 		return
@@ -139,7 +139,7 @@ func (h *ExampleHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.svc.Delete(uint(id)); err != nil {
+	if err := h.svc.Delete(c.Request.Context(), uint(id)); err != nil {
 		response.ServerError(c, "delete failed: "+err.Error())
 		return
 	}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"strconv"
+
+	_ "go-ddd-scaffold/internal/application/dto" // response types for swagger
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHeader names the administrator on responses to requests made
+// with an impersonation token
+const ImpersonationHeader = "X-Impersonated-By"
+
+// ImpersonationHandler handles admin impersonation
+type ImpersonationHandler struct {
+	svc *service.ImpersonationAppService
+}
+
+// NewImpersonationHandler creates a new handler
+func NewImpersonationHandler(svc *service.ImpersonationAppService) *ImpersonationHandler {
+	return &ImpersonationHandler{svc: svc}
+}
+
+// Start issues a short-lived token acting as the user. It cannot be
+// refreshed and is refused on credential management routes.
+// @Summary  Impersonate user
+// @Tags     User
+// @Security Bearer
+// @Produce  json
+// @Param    id  path int true "user ID"
+// @Success  200 {object} response.Response{data=dto.ImpersonationResponse}
+// @Failure  403 {object} response.Response "The user is an administrator, disabled or a service account"
+// @Router   /users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Start(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	claims := currentClaims(c)
+	if claims == nil {
+		response.Unauthorized(c, "invalid or expired token")
+		return
+	}
+
+	resp, err := h.svc.Start(c.Request.Context(), claims, uint(id), clientInfo(c))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, resp)
+}
+
+// Stop ends the impersonation the request's token belongs to
+// @Summary  Stop impersonation
+// @Tags     Auth
+// @Security Bearer
+// @Success  200 {object} response.Response
+// @Router   /auth/impersonation [delete]
+func (h *ImpersonationHandler) Stop(c *gin.Context) {
+	claims := currentClaims(c)
+	if claims == nil {
+		response.Unauthorized(c, "invalid or expired token")
+		return
+	}
+
+	if err := h.svc.Stop(c.Request.Context(), claims); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
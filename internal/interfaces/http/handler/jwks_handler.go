@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-ddd-scaffold/internal/application/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler publishes the token verification keys
+type JWKSHandler struct {
+	tokens *service.TokenService
+}
+
+// NewJWKSHandler creates a new handler
+func NewJWKSHandler(tokens *service.TokenService) *JWKSHandler {
+	return &JWKSHandler{tokens: tokens}
+}
+
+// JWKS returns the public keys that verify access tokens, as a plain RFC 7517
+// key set rather than the unified response format. HMAC keys are never listed.
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.tokens.JWKS())
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler handles two-factor enrollment endpoints for the current user
+type MFAHandler struct {
+	svc *service.MFAAppService
+}
+
+// NewMFAHandler creates a new handler
+func NewMFAHandler(svc *service.MFAAppService) *MFAHandler {
+	return &MFAHandler{svc: svc}
+}
+
+// Status returns the current user's two-factor state
+// @Summary  Two-factor status
+// @Tags     MFA
+// @Security Bearer
+// @Success  200 {object} response.Response{data=dto.MFAStatusResponse}
+// @Router   /auth/mfa [get]
+func (h *MFAHandler) Status(c *gin.Context) {
+	item, err := h.svc.Status(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, item)
+}
+
+// SetupTOTP starts TOTP enrollment
+// @Summary  Start TOTP enrollment
+// @Tags     MFA
+// @Security Bearer
+// @Produce  json
+// @Success  200 {object} response.Response{data=dto.TOTPSetupResponse}
+// @Router   /auth/mfa/totp/setup [post]
+func (h *MFAHandler) SetupTOTP(c *gin.Context) {
+	item, err := h.svc.SetupTOTP(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, item)
+}
+
+// ConfirmTOTP activates TOTP with a first code and returns recovery codes.
+// Tokens restricted to enrollment should then be refreshed to lift the restriction.
+// @Summary  Confirm TOTP enrollment
+// @Tags     MFA
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.MFACodeRequest true "code from the authenticator app"
+// @Success  200  {object} response.Response{data=dto.RecoveryCodesResponse}
+// @Router   /auth/mfa/totp/confirm [post]
+func (h *MFAHandler) ConfirmTOTP(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+
+	item, err := h.svc.ConfirmTOTP(c.GetUint("user_id"), req.Code)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, item)
+}
+
+// DisableTOTP removes the current user's authenticator.
+// Wrong codes count towards the login lockout (429 with Retry-After).
+// @Summary  Disable TOTP
+// @Tags     MFA
+// @Security Bearer
+// @Accept   json
+// @Param    body body dto.MFACodeRequest true "TOTP code or recovery code"
+// @Success  200  {object} response.Response
+// @Failure  429  {object} response.Response "Too many failures; see Retry-After header"
+// @Router   /auth/mfa/totp [delete]
+func (h *MFAHandler) DisableTOTP(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+
+	if err := h.svc.DisableTOTP(c.Request.Context(), c.GetUint("user_id"), req.Code, clientInfo(c)); err != nil {
+		loginError(c, err)
+		return
+	}
+	response.OK(c)
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes.
+// Wrong codes count towards the login lockout (429 with Retry-After).
+// @Summary  Regenerate recovery codes
+// @Tags     MFA
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.MFACodeRequest true "TOTP code or recovery code"
+// @Success  200  {object} response.Response{data=dto.RecoveryCodesResponse}
+// @Failure  429  {object} response.Response "Too many failures; see Retry-After header"
+// @Router   /auth/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+
+	item, err := h.svc.RegenerateRecoveryCodes(c.Request.Context(), c.GetUint("user_id"), req.Code, clientInfo(c))
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+	response.Success(c, item)
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasskeyHandler handles WebAuthn passkey registration, login and management
+type PasskeyHandler struct {
+	svc *service.PasskeyAppService
+}
+
+// NewPasskeyHandler creates a new handler
+func NewPasskeyHandler(svc *service.PasskeyAppService) *PasskeyHandler {
+	return &PasskeyHandler{svc: svc}
+}
+
+// List returns the current user's passkeys
+// @Summary  List my passkeys
+// @Tags     Passkey
+// @Security Bearer
+// @Success  200 {object} response.Response{data=[]dto.PasskeyResponse}
+// @Router   /auth/passkeys [get]
+func (h *PasskeyHandler) List(c *gin.Context) {
+	items, err := h.svc.List(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, items)
+}
+
+// BeginRegistration returns the options for navigator.credentials.create()
+// @Summary  Start passkey registration
+// @Tags     Passkey
+// @Security Bearer
+// @Produce  json
+// @Success  200 {object} response.Response{data=dto.PasskeyChallengeResponse}
+// @Router   /auth/passkeys/register/begin [post]
+func (h *PasskeyHandler) BeginRegistration(c *gin.Context) {
+	challenge, err := h.svc.BeginRegistration(c.Request.Context(), c.GetUint("user_id"))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, challenge)
+}
+
+// FinishRegistration stores the credential created by the authenticator
+// @Summary  Complete passkey registration
+// @Tags     Passkey
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.FinishPasskeyRegistrationRequest true "challenge ID and the created credential"
+// @Success  200  {object} response.Response{data=dto.PasskeyResponse}
+// @Router   /auth/passkeys/register/finish [post]
+func (h *PasskeyHandler) FinishRegistration(c *gin.Context) {
+	var req dto.FinishPasskeyRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.FinishRegistration(c.Request.Context(), c.GetUint("user_id"), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, item)
+}
+
+// Rename renames one of the current user's passkeys
+// @Summary  Rename passkey
+// @Tags     Passkey
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    id   path int                      true "passkey ID"
+// @Param    body body dto.RenamePasskeyRequest true "new name"
+// @Success  200  {object} response.Response{data=dto.PasskeyResponse}
+// @Router   /auth/passkeys/{id} [put]
+func (h *PasskeyHandler) Rename(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	var req dto.RenamePasskeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.Rename(c.Request.Context(), c.GetUint("user_id"), uint(id), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, item)
+}
+
+// Delete removes one of the current user's passkeys
+// @Summary  Delete passkey
+// @Tags     Passkey
+// @Security Bearer
+// @Param    id path int true "passkey ID"
+// @Success  200 {object} response.Response
+// @Router   /auth/passkeys/{id} [delete]
+func (h *PasskeyHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), c.GetUint("user_id"), uint(id)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
+
+// BeginLogin returns the options for navigator.credentials.get()
+// @Summary  Start passkey login
+// @Tags     Auth
+// @Produce  json
+// @Success  200 {object} response.Response{data=dto.PasskeyChallengeResponse}
+// @Router   /auth/passkeys/login/begin [post]
+func (h *PasskeyHandler) BeginLogin(c *gin.Context) {
+	challenge, err := h.svc.BeginLogin(c.Request.Context())
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, challenge)
+}
+
+// FinishLogin verifies the assertion and issues tokens. Passkeys that did
+// not verify the user get an "mfa_pending" token when 2FA is enabled.
+// @Summary  Complete passkey login
+// @Tags     Auth
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.FinishPasskeyLoginRequest true "challenge ID and the authenticator's assertion"
+// @Success  200  {object} response.Response{data=dto.TokenResponse}
+// @Router   /auth/passkeys/login/finish [post]
+func (h *PasskeyHandler) FinishLogin(c *gin.Context) {
+	var req dto.FinishPasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+
+	tokens, err := h.svc.FinishLogin(c.Request.Context(), &req, clientInfo(c))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, tokens)
+}
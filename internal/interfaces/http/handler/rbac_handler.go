@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RBACHandler handles role and permission management endpoints
+type RBACHandler struct {
+	svc *service.RBACAppService
+}
+
+// NewRBACHandler creates a new handler
+func NewRBACHandler(svc *service.RBACAppService) *RBACHandler {
+	return &RBACHandler{svc: svc}
+}
+
+// ListRoles returns all roles
+// @Summary  List roles
+// @Tags     RBAC
+// @Security Bearer
+// @Success  200 {object} response.Response{data=[]dto.RoleResponse}
+// @Router   /roles [get]
+func (h *RBACHandler) ListRoles(c *gin.Context) {
+	items, err := h.svc.ListRoles()
+	if err != nil {
+		response.ServerError(c, "query failed")
+		return
+	}
+	response.Success(c, items)
+}
+
+// CreateRole creates a role
+// @Summary  Create role
+// @Tags     RBAC
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.CreateRoleRequest true "create parameters"
+// @Success  200  {object} response.Response{data=dto.RoleResponse}
+// @Router   /roles [post]
+func (h *RBACHandler) CreateRole(c *gin.Context) {
+	var req dto.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.CreateRole(c.Request.Context(), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// UpdateRole updates a role
+// @Summary  Update role
+// @Tags     RBAC
+// @Security Bearer
+// @Accept   json
+// @Param    id   path int                    true "ID"
+// @Param    body body dto.UpdateRoleRequest  true "update parameters"
+// @Success  200  {object} response.Response{data=dto.RoleResponse}
+// @Router   /roles/{id} [put]
+func (h *RBACHandler) UpdateRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	var req dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.UpdateRole(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// DeleteRole deletes a role
+// @Summary  Delete role
+// @Tags     RBAC
+// @Security Bearer
+// @Param    id path int true "ID"
+// @Success  200 {object} response.Response
+// @Router   /roles/{id} [delete]
+func (h *RBACHandler) DeleteRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	if err := h.svc.DeleteRole(c.Request.Context(), uint(id)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.OK(c)
+}
+
+// ListPermissions returns all permissions
+// @Summary  List permissions
+// @Tags     RBAC
+// @Security Bearer
+// @Success  200 {object} response.Response{data=[]dto.PermissionResponse}
+// @Router   /permissions [get]
+func (h *RBACHandler) ListPermissions(c *gin.Context) {
+	items, err := h.svc.ListPermissions()
+	if err != nil {
+		response.ServerError(c, "query failed")
+		return
+	}
+	response.Success(c, items)
+}
+
+// AssignRole assigns a role to a user
+// @Summary  Assign role to user
+// @Tags     RBAC
+// @Security Bearer
+// @Accept   json
+// @Param    id   path int                    true "user ID"
+// @Param    body body dto.AssignRoleRequest  true "role"
+// @Success  200  {object} response.Response
+// @Router   /users/{id}/role [put]
+func (h *RBACHandler) AssignRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	var req dto.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	if err := h.svc.AssignRole(c.Request.Context(), uint(id), &req); err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.OK(c)
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+	"go-ddd-scaffold/pkg/scim"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMHandler serves SCIM 2.0 provisioning. Responses use the SCIM media type
+// and error format instead of the unified response structure.
+type SCIMHandler struct {
+	svc *service.SCIMAppService
+}
+
+// NewSCIMHandler creates a new handler
+func NewSCIMHandler(svc *service.SCIMAppService) *SCIMHandler {
+	return &SCIMHandler{svc: svc}
+}
+
+// SCIMMiddleware authenticates the provisioning client with the bearer token
+// configured as scim.token and scopes the request to the provisioned tenant
+func SCIMMiddleware(svc *service.SCIMAppService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer realm="scim"`)
+			writeSCIMError(c, scim.NewError(http.StatusUnauthorized, "", "bearer token required"))
+			c.Abort()
+			return
+		}
+		ctx, err := svc.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			writeSCIMError(c, err)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// ServiceProviderConfig describes the supported SCIM features
+func (h *SCIMHandler) ServiceProviderConfig(c *gin.Context) {
+	writeSCIM(c, http.StatusOK, h.svc.ServiceProviderConfig())
+}
+
+// ResourceTypes lists the resource types
+func (h *SCIMHandler) ResourceTypes(c *gin.Context) {
+	types := h.svc.ResourceTypes()
+	writeSCIM(c, http.StatusOK, scim.NewListResponse(types, len(types), int64(len(types)), 1))
+}
+
+// ResourceType returns a resource type
+func (h *SCIMHandler) ResourceType(c *gin.Context) {
+	t, err := h.svc.ResourceType(c.Param("id"))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, t)
+}
+
+// Schemas lists the schemas of the resources
+func (h *SCIMHandler) Schemas(c *gin.Context) {
+	schemas := h.svc.Schemas()
+	writeSCIM(c, http.StatusOK, scim.NewListResponse(schemas, len(schemas), int64(len(schemas)), 1))
+}
+
+// Schema returns a schema
+func (h *SCIMHandler) Schema(c *gin.Context) {
+	schema, err := h.svc.Schema(c.Param("id"))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, schema)
+}
+
+// ListUsers returns a page of users. Filters support comparisons joined by
+// "and" on userName, displayName, emails, active, meta.created and
+// meta.lastModified, and eq on id, externalId and groups.
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	var q dto.SCIMListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		writeSCIMError(c, scim.BadRequest(scim.ErrInvalidValue, "invalid query parameters"))
+		return
+	}
+	list, err := h.svc.ListUsers(c.Request.Context(), &q)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, list)
+}
+
+// GetUser returns a user
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	u, err := h.svc.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, u)
+}
+
+// CreateUser provisions a user
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var req dto.SCIMUser
+	if !bindSCIM(c, &req) {
+		return
+	}
+	u, err := h.svc.CreateUser(c.Request.Context(), &req)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Header("Location", u.Meta.Location)
+	writeSCIM(c, http.StatusCreated, u)
+}
+
+// ReplaceUser replaces a user's attributes
+func (h *SCIMHandler) ReplaceUser(c *gin.Context) {
+	var req dto.SCIMUser
+	if !bindSCIM(c, &req) {
+		return
+	}
+	u, err := h.svc.ReplaceUser(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, u)
+}
+
+// PatchUser applies PATCH operations to a user; "active": false deprovisions it
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	var req scim.PatchRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+	u, err := h.svc.PatchUser(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, u)
+}
+
+// DeleteUser deprovisions a user: the account is disabled and its tokens revoked
+func (h *SCIMHandler) DeleteUser(c *gin.Context) {
+	if err := h.svc.DeleteUser(c.Request.Context(), c.Param("id")); err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroups returns a page of groups (roles). Filters support comparisons
+// joined by "and" on displayName and id, and eq on members.
+func (h *SCIMHandler) ListGroups(c *gin.Context) {
+	var q dto.SCIMListQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		writeSCIMError(c, scim.BadRequest(scim.ErrInvalidValue, "invalid query parameters"))
+		return
+	}
+	list, err := h.svc.ListGroups(c.Request.Context(), &q)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, list)
+}
+
+// GetGroup returns a group
+func (h *SCIMHandler) GetGroup(c *gin.Context) {
+	g, err := h.svc.GetGroup(c.Request.Context(), c.Param("id"), c.Query("excludedAttributes"))
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, g)
+}
+
+// CreateGroup creates a role without permissions and assigns it to the members
+func (h *SCIMHandler) CreateGroup(c *gin.Context) {
+	var req dto.SCIMGroup
+	if !bindSCIM(c, &req) {
+		return
+	}
+	g, err := h.svc.CreateGroup(c.Request.Context(), &req)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Header("Location", g.Meta.Location)
+	writeSCIM(c, http.StatusCreated, g)
+}
+
+// ReplaceGroup replaces a group's members
+func (h *SCIMHandler) ReplaceGroup(c *gin.Context) {
+	var req dto.SCIMGroup
+	if !bindSCIM(c, &req) {
+		return
+	}
+	g, err := h.svc.ReplaceGroup(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, g)
+}
+
+// PatchGroup adds, removes or replaces a group's members
+func (h *SCIMHandler) PatchGroup(c *gin.Context) {
+	var req scim.PatchRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+	g, err := h.svc.PatchGroup(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	writeSCIM(c, http.StatusOK, g)
+}
+
+// DeleteGroup moves the members to the default role and deletes the role
+// unless it is built in or still used by other tenants
+func (h *SCIMHandler) DeleteGroup(c *gin.Context) {
+	if err := h.svc.DeleteGroup(c.Request.Context(), c.Param("id")); err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// bindSCIM decodes a JSON request body, answering invalidSyntax on failure
+func bindSCIM(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		writeSCIMError(c, scim.BadRequest(scim.ErrInvalidSyntax, "invalid request body: "+err.Error()))
+		return false
+	}
+	return true
+}
+
+func writeSCIM(c *gin.Context, status int, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		writeSCIMError(c, err)
+		return
+	}
+	c.Data(status, scim.ContentType, data)
+}
+
+// writeSCIMError answers with a SCIM error, translating application errors
+func writeSCIMError(c *gin.Context, err error) {
+	var scimErr *scim.Error
+	var appErr *errcode.Error
+	switch {
+	case errors.As(err, &scimErr):
+	case errors.As(err, &appErr):
+		switch appErr.Code {
+		case errcode.ErrAccountNotFound.Code, errcode.ErrRoleNotFound.Code:
+			scimErr = scim.NewError(http.StatusNotFound, "", appErr.Message)
+		case errcode.ErrAccountExists.Code, errcode.ErrRoleExists.Code:
+			scimErr = scim.NewError(http.StatusConflict, scim.ErrUniqueness, appErr.Message)
+		case errcode.ErrRoleInUse.Code:
+			scimErr = scim.NewError(http.StatusConflict, "", appErr.Message)
+		default:
+			status := appErr.GetHTTPStatus()
+			scimType := ""
+			if status == http.StatusBadRequest {
+				scimType = scim.ErrInvalidValue
+			}
+			if status >= http.StatusInternalServerError {
+				logger.Errorf("scim %s %s: %v", c.Request.Method, c.FullPath(), err)
+			}
+			scimErr = scim.NewError(status, scimType, appErr.Message)
+		}
+	default:
+		logger.Errorf("scim %s %s: %v", c.Request.Method, c.FullPath(), err)
+		scimErr = scim.NewError(http.StatusInternalServerError, "", "internal server error")
+	}
+	writeSCIM(c, scimErr.StatusCode(), scimErr)
+}
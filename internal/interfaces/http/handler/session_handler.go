@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"strconv"
+
+	_ "go-ddd-scaffold/internal/application/dto" // response types for swagger
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler handles signed-in device management
+type SessionHandler struct {
+	svc *service.SessionAppService
+}
+
+// NewSessionHandler creates a new handler
+func NewSessionHandler(svc *service.SessionAppService) *SessionHandler {
+	return &SessionHandler{svc: svc}
+}
+
+// List returns the current user's active sessions
+// @Summary  List my sessions
+// @Tags     Session
+// @Security Bearer
+// @Success  200 {object} response.Response{data=[]dto.SessionResponse}
+// @Router   /auth/sessions [get]
+func (h *SessionHandler) List(c *gin.Context) {
+	items, err := h.svc.List(c.Request.Context(), c.GetUint("user_id"), currentSessionID(c))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, items)
+}
+
+// Revoke signs out one of the current user's sessions
+// @Summary  Revoke session
+// @Tags     Session
+// @Security Bearer
+// @Param    id path int true "session ID"
+// @Success  200 {object} response.Response
+// @Router   /auth/sessions/{id} [delete]
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	if err := h.svc.Revoke(c.Request.Context(), c.GetUint("user_id"), uint(id)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
+
+// RevokeOthers signs out every session of the current user but this one
+// @Summary  Revoke other sessions
+// @Tags     Session
+// @Security Bearer
+// @Success  200 {object} response.Response
+// @Router   /auth/sessions [delete]
+func (h *SessionHandler) RevokeOthers(c *gin.Context) {
+	if err := h.svc.RevokeOthers(c.Request.Context(), c.GetUint("user_id"), currentSessionID(c)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
+
+// ListForUser returns a user's active sessions
+// @Summary  List user sessions
+// @Tags     User
+// @Security Bearer
+// @Param    id path int true "user ID"
+// @Success  200 {object} response.Response{data=[]dto.SessionResponse}
+// @Router   /users/{id}/sessions [get]
+func (h *SessionHandler) ListForUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	items, err := h.svc.List(c.Request.Context(), uint(id), currentSessionID(c))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, items)
+}
+
+// RevokeForUser signs out one of a user's sessions
+// @Summary  Revoke user session
+// @Tags     User
+// @Security Bearer
+// @Param    id         path int true "user ID"
+// @Param    session_id path int true "session ID"
+// @Success  200 {object} response.Response
+// @Router   /users/{id}/sessions/{session_id} [delete]
+func (h *SessionHandler) RevokeForUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid session ID")
+		return
+	}
+
+	if err := h.svc.Revoke(c.Request.Context(), uint(id), uint(sessionID)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
+
+// RevokeAllForUser signs out every session of a user
+// @Summary  Revoke all user sessions
+// @Tags     User
+// @Security Bearer
+// @Param    id path int true "user ID"
+// @Success  200 {object} response.Response
+// @Router   /users/{id}/sessions [delete]
+func (h *SessionHandler) RevokeAllForUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	if err := h.svc.RevokeAll(c.Request.Context(), uint(id)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.OK(c)
+}
+
+// currentSessionID returns the session of the request's access token, if any
+func currentSessionID(c *gin.Context) string {
+	if claims := currentClaims(c); claims != nil {
+		return claims.SessionID
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/errcode"
+	"go-ddd-scaffold/pkg/logger"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ssoStateCookie binds a login's state to the browser that started it
+const ssoStateCookie = "sso_state"
+
+// SSOHandler handles OpenID Connect single sign-on endpoints
+type SSOHandler struct {
+	svc *service.SSOAppService
+}
+
+// NewSSOHandler creates a new handler
+func NewSSOHandler(svc *service.SSOAppService) *SSOHandler {
+	return &SSOHandler{svc: svc}
+}
+
+// Config tells the login page whether single sign-on is available
+// @Summary  Single sign-on availability
+// @Tags     Auth
+// @Produce  json
+// @Success  200 {object} response.Response{data=dto.SSOConfigResponse}
+// @Router   /auth/oidc/config [get]
+func (h *SSOHandler) Config(c *gin.Context) {
+	response.Success(c, &dto.SSOConfigResponse{Enabled: h.svc.Enabled()})
+}
+
+// Login redirects the browser to the identity provider. The login state is
+// also kept in an HttpOnly cookie scoped to the callback path, which the
+// callback requires to match.
+// @Summary  Start single sign-on
+// @Tags     Auth
+// @Success  302
+// @Router   /auth/oidc/login [get]
+func (h *SSOHandler) Login(c *gin.Context) {
+	target, state, err := h.svc.AuthURL(c.Request.Context())
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.setStateCookie(c, state, 0)
+	c.Redirect(http.StatusFound, target)
+}
+
+// Callback receives the identity provider's redirect and sends the browser back
+// to the frontend with a one-time sign-in code (sso_code) or an error (sso_error)
+// @Summary  Single sign-on callback
+// @Tags     Auth
+// @Param    code  query string true "authorization code"
+// @Param    state query string true "login state"
+// @Success  302
+// @Router   /auth/oidc/callback [get]
+func (h *SSOHandler) Callback(c *gin.Context) {
+	if idpErr := c.Query("error"); idpErr != "" {
+		h.setStateCookie(c, "", -1)
+		msg := c.Query("error_description")
+		if msg == "" {
+			msg = idpErr
+		}
+		h.redirect(c, "sso_error", msg)
+		return
+	}
+
+	boundState, _ := c.Cookie(ssoStateCookie)
+	h.setStateCookie(c, "", -1)
+	code, err := h.svc.Callback(c.Request.Context(), c.Query("code"), c.Query("state"), boundState, clientInfo(c))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	h.redirect(c, "sso_code", code)
+}
+
+// Exchange redeems the one-time sign-in code for tokens
+// @Summary  Complete single sign-on
+// @Tags     Auth
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.SSOExchangeRequest true "sign-in code from the callback redirect"
+// @Success  200  {object} response.Response{data=dto.TokenResponse}
+// @Router   /auth/oidc/exchange [post]
+func (h *SSOHandler) Exchange(c *gin.Context) {
+	var req dto.SSOExchangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+
+	tokens, err := h.svc.Exchange(c.Request.Context(), req.Code)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, tokens)
+}
+
+// fail sends browser-facing errors back to the frontend instead of rendering JSON
+func (h *SSOHandler) fail(c *gin.Context, err error) {
+	var e *errcode.Error
+	if !errors.As(err, &e) {
+		logger.Errorf("sso login failed: %v", err)
+		e = errcode.ErrSSOFailed
+	}
+	h.redirect(c, "sso_error", e.Message)
+}
+
+// setStateCookie sets (maxAge 0: until the browser closes) or deletes (maxAge -1)
+// the state cookie. SameSite=Lax still sends it on the IdP's top-level redirect.
+func (h *SSOHandler) setStateCookie(c *gin.Context, state string, maxAge int) {
+	path, secure := "/", false
+	if callback, err := url.Parse(h.svc.RedirectURL()); err == nil {
+		if callback.Path != "" {
+			path = callback.Path
+		}
+		secure = callback.Scheme == "https"
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoStateCookie, state, maxAge, path, "", secure, true)
+}
+
+func (h *SSOHandler) redirect(c *gin.Context, key, value string) {
+	target, err := url.Parse(h.svc.FrontendURL())
+	if err != nil {
+		response.ServerError(c, "invalid sso.frontend_url")
+		return
+	}
+	q := target.Query()
+	q.Set(key, value)
+	target.RawQuery = q.Encode()
+	c.Redirect(http.StatusFound, target.String())
+}
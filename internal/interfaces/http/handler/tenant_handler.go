@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantHandler handles tenant administration endpoints (super-admins only)
+type TenantHandler struct {
+	svc *service.TenantAppService
+}
+
+// NewTenantHandler creates a new handler
+func NewTenantHandler(svc *service.TenantAppService) *TenantHandler {
+	return &TenantHandler{svc: svc}
+}
+
+// List returns all tenants
+// @Summary  List tenants
+// @Tags     Tenant
+// @Security Bearer
+// @Success  200 {object} response.Response{data=[]dto.TenantResponse}
+// @Router   /tenants [get]
+func (h *TenantHandler) List(c *gin.Context) {
+	items, err := h.svc.List()
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, items)
+}
+
+// Create creates a tenant and its first administrator
+// @Summary  Create tenant
+// @Tags     Tenant
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.CreateTenantRequest true "create parameters"
+// @Success  200  {object} response.Response{data=dto.TenantResponse}
+// @Router   /tenants [post]
+func (h *TenantHandler) Create(c *gin.Context) {
+	var req dto.CreateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.Create(c.Request.Context(), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// Get returns tenant details
+// @Summary  Get tenant by ID
+// @Tags     Tenant
+// @Security Bearer
+// @Param    id path int true "ID"
+// @Success  200 {object} response.Response{data=dto.TenantResponse}
+// @Router   /tenants/{id} [get]
+func (h *TenantHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	item, err := h.svc.GetByID(uint(id))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// Update renames a tenant
+// @Summary  Update tenant
+// @Tags     Tenant
+// @Security Bearer
+// @Accept   json
+// @Param    id   path int                      true "ID"
+// @Param    body body dto.UpdateTenantRequest  true "update parameters"
+// @Success  200  {object} response.Response{data=dto.TenantResponse}
+// @Router   /tenants/{id} [put]
+func (h *TenantHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	var req dto.UpdateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.Update(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
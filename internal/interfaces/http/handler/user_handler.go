@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler handles user administration endpoints
+type UserHandler struct {
+	svc *service.UserAppService
+}
+
+// NewUserHandler creates a new handler
+func NewUserHandler(svc *service.UserAppService) *UserHandler {
+	return &UserHandler{svc: svc}
+}
+
+// List returns user list
+// @Summary  List users
+// @Tags     User
+// @Security Bearer
+// @Param    page      query int    false "page"      default(1)
+// @Param    page_size query int    false "page size"  default(10)
+// @Param    keyword   query string false "search keyword"
+// @Param    status    query string false "status filter" Enums(active, disabled)
+// @Success  200 {object} response.Response{data=response.PageData}
+// @Router   /users [get]
+func (h *UserHandler) List(c *gin.Context) {
+	var req dto.QueryUserRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.ParamError(c, "invalid parameters")
+		return
+	}
+
+	items, total, err := h.svc.List(c.Request.Context(), &req)
+	if err != nil {
+		response.ServerError(c, "query failed")
+		return
+	}
+
+	response.SuccessPage(c, items, total, req.Page, req.PageSize)
+}
+
+// Create creates a user
+// @Summary  Create user
+// @Tags     User
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.CreateUserRequest true "create parameters"
+// @Success  200  {object} response.Response{data=dto.UserResponse}
+// @Router   /users [post]
+func (h *UserHandler) Create(c *gin.Context) {
+	var req dto.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.Create(c.Request.Context(), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// CreateServiceAccount creates a password-less machine identity
+// @Summary  Create service account
+// @Tags     User
+// @Security Bearer
+// @Accept   json
+// @Produce  json
+// @Param    body body dto.CreateServiceAccountRequest true "create parameters"
+// @Success  200  {object} response.Response{data=dto.UserResponse}
+// @Router   /service-accounts [post]
+func (h *UserHandler) CreateServiceAccount(c *gin.Context) {
+	var req dto.CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.CreateServiceAccount(c.Request.Context(), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// Get returns user details
+// @Summary  Get user by ID
+// @Tags     User
+// @Security Bearer
+// @Param    id path int true "ID"
+// @Success  200 {object} response.Response{data=dto.UserResponse}
+// @Router   /users/{id} [get]
+func (h *UserHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	item, err := h.svc.GetByID(c.Request.Context(), uint(id))
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// Update updates a user
+// @Summary  Update user
+// @Tags     User
+// @Security Bearer
+// @Accept   json
+// @Param    id   path int                    true "ID"
+// @Param    body body dto.UpdateUserRequest  true "update parameters"
+// @Success  200  {object} response.Response{data=dto.UserResponse}
+// @Router   /users/{id} [put]
+func (h *UserHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	var req dto.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	item, err := h.svc.Update(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.Success(c, item)
+}
+
+// ResetPassword sets a temporary password that must be changed at next login
+// and signs the user out everywhere
+// @Summary  Reset user password
+// @Tags     User
+// @Security Bearer
+// @Accept   json
+// @Param    id   path int                       true "ID"
+// @Param    body body dto.ResetPasswordRequest  true "temporary password"
+// @Success  200  {object} response.Response
+// @Router   /users/{id}/reset-password [post]
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "invalid parameters: "+err.Error())
+		return
+	}
+
+	if err := h.svc.ResetPassword(c.Request.Context(), uint(id), &req); err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.OK(c)
+}
+
+// ResetMFA removes a user's second factor
+// @Summary  Reset user two-factor authentication
+// @Tags     User
+// @Security Bearer
+// @Param    id path int true "ID"
+// @Success  200 {object} response.Response
+// @Router   /users/{id}/mfa/reset [post]
+func (h *UserHandler) ResetMFA(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	if err := h.svc.ResetMFA(c.Request.Context(), uint(id)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.OK(c)
+}
+
+// Disable disables a user and signs them out
+// @Summary  Disable user
+// @Tags     User
+// @Security Bearer
+// @Param    id path int true "ID"
+// @Success  200 {object} response.Response
+// @Router   /users/{id}/disable [post]
+func (h *UserHandler) Disable(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	if err := h.svc.Disable(c.Request.Context(), c.GetUint("user_id"), uint(id)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.OK(c)
+}
+
+// Enable re-enables a disabled user
+// @Summary  Enable user
+// @Tags     User
+// @Security Bearer
+// @Param    id path int true "ID"
+// @Success  200 {object} response.Response
+// @Router   /users/{id}/enable [post]
+func (h *UserHandler) Enable(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	if err := h.svc.Enable(c.Request.Context(), uint(id)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.OK(c)
+}
+
+// Delete deletes a user
+// @Summary  Delete user
+// @Tags     User
+// @Security Bearer
+// @Param    id path int true "ID"
+// @Success  200 {object} response.Response
+// @Router   /users/{id} [delete]
+func (h *UserHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ParamError(c, "invalid ID")
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), c.GetUint("user_id"), uint(id)); err != nil {
+		response.FromError(c, err)
+		return
+	}
+
+	response.OK(c)
+}
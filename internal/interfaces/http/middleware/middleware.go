@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"context"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"go-ddd-scaffold/pkg/errcode"
 	"go-ddd-scaffold/pkg/logger"
 	"go-ddd-scaffold/pkg/response"
 )
@@ -34,8 +37,8 @@ func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Request-ID")
-		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, X-Request-ID, X-Tenant")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID, X-Impersonated-By")
 		c.Header("Access-Control-Max-Age", "86400")
 
 		if c.Request.Method == "OPTIONS" {
@@ -108,7 +111,8 @@ func SecurityHeaders() gin.HandlerFunc {
 	}
 }
 
-// RateLimit IP 级别速率限制
+// RateLimit IP 级别速率限制：每个客户端 IP 在 window 内最多 maxRequests 次请求，
+// 超出时返回 429 和 Retry-After。计数保存在本进程内，过期的计数在新窗口开始时顺带清理。
 func RateLimit(maxRequests int, window time.Duration) gin.HandlerFunc {
 	type visitor struct {
 		count   int
@@ -116,12 +120,21 @@ func RateLimit(maxRequests int, window time.Duration) gin.HandlerFunc {
 	}
 	var mu sync.Mutex
 	visitors := make(map[string]*visitor)
+	nextSweep := time.Now().Add(window)
 
 	return func(c *gin.Context) {
 		ip := c.ClientIP()
+		now := time.Now()
 		mu.Lock()
+		if now.After(nextSweep) {
+			for k, v := range visitors {
+				if now.After(v.resetAt) {
+					delete(visitors, k)
+				}
+			}
+			nextSweep = now.Add(window)
+		}
 		v, exists := visitors[ip]
-		now := time.Now()
 		if !exists || now.After(v.resetAt) {
 			visitors[ip] = &visitor{count: 1, resetAt: now.Add(window)}
 			mu.Unlock()
@@ -130,10 +143,12 @@ func RateLimit(maxRequests int, window time.Duration) gin.HandlerFunc {
 		}
 		v.count++
 		if v.count > maxRequests {
+			wait := v.resetAt.Sub(now)
 			mu.Unlock()
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"code":    50004,
-				"message": "请求过于频繁，请稍后再试",
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, response.Response{
+				Code:    errcode.ErrTooFrequent.Code,
+				Message: errcode.ErrTooFrequent.Message,
 			})
 			c.Abort()
 			return
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"go-ddd-scaffold/internal/application/service"
 	"go-ddd-scaffold/internal/container"
 	"go-ddd-scaffold/internal/interfaces/http/handler"
 	"go-ddd-scaffold/internal/interfaces/http/middleware"
@@ -27,6 +29,9 @@ func Setup(c *container.Container) *gin.Engine {
 	}
 
 	r := gin.New()
+	if err := r.SetTrustedProxies(c.Config.Server.TrustedProxies); err != nil {
+		logger.Warnf("invalid server.trusted_proxies: %v", err)
+	}
 
 	// Global middleware
 	r.Use(
@@ -34,6 +39,7 @@ func Setup(c *container.Container) *gin.Engine {
 		middleware.CORS(),
 		middleware.RequestID(),
 		middleware.Logger(),
+		handler.AuditMiddleware(c.AuditService),
 	)
 
 	// Health check
@@ -41,39 +47,197 @@ func Setup(c *container.Container) *gin.Engine {
 		response.OK(ctx)
 	})
 
+	// Token verification keys for other services
+	r.GET("/.well-known/jwks.json", handler.NewJWKSHandler(c.TokenService).JWKS)
+
 	// Swagger API docs
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// API v1
 	v1 := r.Group("/api/v1")
 	{
-		// Auth (public)
+		apiKeyHandler := handler.NewAPIKeyHandler(c.APIKeyService)
+		sessionHandler := handler.NewSessionHandler(c.SessionService)
+		impersonationHandler := handler.NewImpersonationHandler(c.ImpersonationService)
+
+		// Auth (public, or accepting restricted tokens where listed)
 		auth := v1.Group("/auth")
 		{
-			authHandler := handler.NewAuthHandler(&c.Config.JWT)
+			authHandler := handler.NewAuthHandler(c.AuthService)
 			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", handler.AuthMiddleware(&c.Config.JWT), authHandler.RefreshToken)
+			// Each captcha costs an image render and a cache entry
+			if rate := c.Config.Security.Login.CaptchaRate; rate > 0 {
+				auth.GET("/captcha", middleware.RateLimit(rate, time.Minute), authHandler.Captcha)
+			} else {
+				auth.GET("/captcha", authHandler.Captcha)
+			}
+			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/logout", handler.AuthMiddleware(c.TokenService, nil, service.RestrictionPasswordChange, service.RestrictionMFAEnroll), authHandler.Logout)
+			auth.PUT("/password", handler.AuthMiddleware(c.TokenService, nil, service.RestrictionPasswordChange, service.RestrictionMFAEnroll), handler.DenyImpersonation(), authHandler.ChangePassword)
+			auth.POST("/mfa/verify", handler.AuthMiddleware(c.TokenService, nil, service.RestrictionMFAPending), authHandler.VerifyMFA)
+
+			// Links sent by mail
+			emailHandler := handler.NewEmailHandler(c.EmailService)
+			auth.POST("/forgot-password", emailHandler.ForgotPassword)
+			auth.POST("/reset-password", emailHandler.ResetPassword)
+			auth.POST("/email/verify", emailHandler.VerifyEmail)
+			auth.POST("/email/verification", handler.AuthMiddleware(c.TokenService, nil), emailHandler.ResendVerification)
+
+			// OpenID Connect single sign-on (browser redirects, then a code exchange)
+			ssoHandler := handler.NewSSOHandler(c.SSOService)
+			oidc := auth.Group("/oidc")
+			{
+				oidc.GET("/config", ssoHandler.Config)
+				oidc.GET("/login", ssoHandler.Login)
+				oidc.GET("/callback", ssoHandler.Callback)
+				oidc.POST("/exchange", ssoHandler.Exchange)
+			}
+
+			// Ends an impersonation started at /users/:id/impersonate
+			auth.DELETE("/impersonation", handler.AuthMiddleware(c.TokenService, nil), impersonationHandler.Stop)
+
+			// Two-factor enrollment (reachable while the role forces enrollment)
+			mfaHandler := handler.NewMFAHandler(c.MFAService)
+			enroll := auth.Group("/mfa", handler.AuthMiddleware(c.TokenService, nil, service.RestrictionMFAEnroll), handler.DenyImpersonation())
+			{
+				enroll.GET("", mfaHandler.Status)
+				enroll.POST("/totp/setup", mfaHandler.SetupTOTP)
+				enroll.POST("/totp/confirm", mfaHandler.ConfirmTOTP)
+			}
+			mfa := auth.Group("/mfa", handler.AuthMiddleware(c.TokenService, nil), handler.DenyImpersonation())
+			{
+				mfa.DELETE("/totp", mfaHandler.DisableTOTP)
+				mfa.POST("/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+			}
+
+			// Personal access tokens (sessions only, so a leaked key cannot mint more keys)
+			apiKeys := auth.Group("/api-keys", handler.AuthMiddleware(c.TokenService, nil), handler.DenyImpersonation())
+			{
+				apiKeys.GET("", apiKeyHandler.List)
+				apiKeys.POST("", apiKeyHandler.Create)
+				apiKeys.DELETE("/:id", apiKeyHandler.Revoke)
+			}
+
+			// Passkeys: passwordless login, and registration for signed-in users
+			passkeyHandler := handler.NewPasskeyHandler(c.PasskeyService)
+			auth.POST("/passkeys/login/begin", passkeyHandler.BeginLogin)
+			auth.POST("/passkeys/login/finish", passkeyHandler.FinishLogin)
+			passkeys := auth.Group("/passkeys", handler.AuthMiddleware(c.TokenService, nil), handler.DenyImpersonation())
+			{
+				passkeys.GET("", passkeyHandler.List)
+				passkeys.POST("/register/begin", passkeyHandler.BeginRegistration)
+				passkeys.POST("/register/finish", passkeyHandler.FinishRegistration)
+				passkeys.PUT("/:id", passkeyHandler.Rename)
+				passkeys.DELETE("/:id", passkeyHandler.Delete)
+			}
+
+			// Signed-in devices
+			sessions := auth.Group("/sessions", handler.AuthMiddleware(c.TokenService, nil))
+			{
+				sessions.GET("", sessionHandler.List)
+				sessions.DELETE("", sessionHandler.RevokeOthers)
+				sessions.DELETE("/:id", sessionHandler.Revoke)
+			}
 		}
 
-		// Authenticated routes
+		// Authenticated routes (JWT or API key), scoped to the caller's tenant
+		// unless a super-admin names another one
 		authorized := v1.Group("")
-		authorized.Use(handler.AuthMiddleware(&c.Config.JWT))
+		authorized.Use(handler.AuthMiddleware(c.TokenService, c.APIKeyService), handler.TenantMiddleware(c.TenantService))
 		{
+			authz := handler.NewAuthz(c.RBACService)
+
+			// Tenant administration
+			tenantHandler := handler.NewTenantHandler(c.TenantService)
+			tenants := authorized.Group("/tenants", handler.RequireSuperAdmin())
+			{
+				tenants.GET("", tenantHandler.List)
+				tenants.POST("", tenantHandler.Create)
+				tenants.GET("/:id", tenantHandler.Get)
+				tenants.PUT("/:id", tenantHandler.Update)
+			}
+
+			// RBAC administration (roles are shared by all tenants)
+			rbacHandler := handler.NewRBACHandler(c.RBACService)
+			authorized.GET("/permissions", authz.RequirePermission("rbac:read"), rbacHandler.ListPermissions)
+			roles := authorized.Group("/roles")
+			{
+				roles.GET("", authz.RequirePermission("rbac:read"), rbacHandler.ListRoles)
+				roles.POST("", handler.RequireSuperAdmin(), authz.RequirePermission("rbac:write"), rbacHandler.CreateRole)
+				roles.PUT("/:id", handler.RequireSuperAdmin(), authz.RequirePermission("rbac:write"), rbacHandler.UpdateRole)
+				roles.DELETE("/:id", handler.RequireSuperAdmin(), authz.RequirePermission("rbac:write"), rbacHandler.DeleteRole)
+			}
+
+			// User administration
+			userHandler := handler.NewUserHandler(c.UserService)
+			users := authorized.Group("/users")
+			{
+				users.GET("", authz.RequirePermission("user:read"), userHandler.List)
+				users.POST("", authz.RequirePermission("user:write"), userHandler.Create)
+				users.GET("/:id", authz.RequirePermission("user:read"), userHandler.Get)
+				users.PUT("/:id", authz.RequirePermission("user:write"), userHandler.Update)
+				users.POST("/:id/reset-password", authz.RequirePermission("user:write"), userHandler.ResetPassword)
+				users.POST("/:id/mfa/reset", authz.RequirePermission("user:write"), userHandler.ResetMFA)
+				users.DELETE("/:id", authz.RequirePermission("user:write"), userHandler.Delete)
+				users.POST("/:id/disable", authz.RequirePermission("user:write"), userHandler.Disable)
+				users.POST("/:id/enable", authz.RequirePermission("user:write"), userHandler.Enable)
+				users.PUT("/:id/role", authz.RequirePermission("rbac:write"), rbacHandler.AssignRole)
+				users.POST("/:id/impersonate", handler.RequireSession(), authz.RequirePermission("user:impersonate"), impersonationHandler.Start)
+
+				users.GET("/:id/api-keys", authz.RequirePermission("user:read"), apiKeyHandler.ListForUser)
+				users.POST("/:id/api-keys", handler.RequireSession(), authz.RequirePermission("user:write"), apiKeyHandler.CreateForUser)
+				users.DELETE("/:id/api-keys/:key_id", authz.RequirePermission("user:write"), apiKeyHandler.RevokeForUser)
+
+				users.GET("/:id/sessions", authz.RequirePermission("user:read"), sessionHandler.ListForUser)
+				users.DELETE("/:id/sessions", authz.RequirePermission("user:write"), sessionHandler.RevokeAllForUser)
+				users.DELETE("/:id/sessions/:session_id", authz.RequirePermission("user:write"), sessionHandler.RevokeForUser)
+			}
+			authorized.POST("/service-accounts", authz.RequirePermission("user:write"), userHandler.CreateServiceAccount)
+
+			// Audit log
+			auditHandler := handler.NewAuditHandler(c.AuditService)
+			authorized.GET("/audit-logs", authz.RequirePermission("audit:read"), auditHandler.List)
+
 			// Example module
 			exampleHandler := handler.NewExampleHandler(c.ExampleService)
 			examples := authorized.Group("/examples")
 			{
-				examples.GET("", exampleHandler.List)
-				examples.POST("", exampleHandler.Create)
-				examples.GET("/:id", exampleHandler.Get)
-				examples.PUT("/:id", exampleHandler.Update)
-				examples.DELETE("/:id", exampleHandler.Delete)
+				examples.GET("", authz.RequirePermission("example:read"), exampleHandler.List)
+				examples.POST("", authz.RequirePermission("example:write"), exampleHandler.Create)
+				examples.GET("/:id", authz.RequirePermission("example:read"), exampleHandler.Get)
+				examples.PUT("/:id", authz.RequirePermission("example:write"), exampleHandler.Update)
+				examples.DELETE("/:id", authz.RequirePermission("example:delete"), exampleHandler.Delete)
 			}
 
 			// GEN:ROUTE_REGISTER - Code generator appends routes here, do not remove
 		}
 	}
 
+	// SCIM 2.0 provisioning for a central identity provider (own bearer token)
+	scimHandler := handler.NewSCIMHandler(c.SCIMService)
+	scim := r.Group("/scim/v2", handler.SCIMMiddleware(c.SCIMService))
+	{
+		scim.GET("/ServiceProviderConfig", scimHandler.ServiceProviderConfig)
+		scim.GET("/ResourceTypes", scimHandler.ResourceTypes)
+		scim.GET("/ResourceTypes/:id", scimHandler.ResourceType)
+		scim.GET("/Schemas", scimHandler.Schemas)
+		scim.GET("/Schemas/:id", scimHandler.Schema)
+
+		scim.GET("/Users", scimHandler.ListUsers)
+		scim.POST("/Users", scimHandler.CreateUser)
+		scim.GET("/Users/:id", scimHandler.GetUser)
+		scim.PUT("/Users/:id", scimHandler.ReplaceUser)
+		scim.PATCH("/Users/:id", scimHandler.PatchUser)
+		scim.DELETE("/Users/:id", scimHandler.DeleteUser)
+
+		scim.GET("/Groups", scimHandler.ListGroups)
+		scim.POST("/Groups", scimHandler.CreateGroup)
+		scim.GET("/Groups/:id", scimHandler.GetGroup)
+		scim.PUT("/Groups/:id", scimHandler.ReplaceGroup)
+		scim.PATCH("/Groups/:id", scimHandler.PatchGroup)
+		scim.DELETE("/Groups/:id", scimHandler.DeleteGroup)
+	}
+
 	// ====== Frontend static files ======
 	registerFrontendRoutes(r)
 
@@ -119,7 +283,7 @@ func registerEmbeddedFrontend(r *gin.Engine, distFS fs.FS) {
 		path := c.Request.URL.Path
 
 		// API and Swagger routes return 404
-		if strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/swagger/") {
+		if strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/scim/") || strings.HasPrefix(path, "/swagger/") {
 			response.NotFound(c, "API not found")
 			return
 		}
@@ -144,7 +308,7 @@ func registerEmbeddedFrontend(r *gin.Engine, distFS fs.FS) {
 func spaFallback(staticDir string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		path := c.Request.URL.Path
-		if strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/swagger/") {
+		if strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/scim/") || strings.HasPrefix(path, "/swagger/") {
 			response.NotFound(c, "API not found")
 			return
 		}
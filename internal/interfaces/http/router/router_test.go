@@ -0,0 +1,207 @@
+package router_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-ddd-scaffold/internal/application/dto"
+	"go-ddd-scaffold/internal/application/service"
+	"go-ddd-scaffold/internal/container"
+	"go-ddd-scaffold/internal/domain/tenant"
+	"go-ddd-scaffold/internal/interfaces/http/router"
+	"go-ddd-scaffold/pkg/config"
+	"go-ddd-scaffold/pkg/logger"
+)
+
+func TestMain(m *testing.M) {
+	if err := logger.Init(&config.LogConfig{Level: "error", Format: "console", Output: "console"}); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// newTestServer serves the full router over a fresh SQLite database and an
+// in-memory cache, after letting the test adjust the default config
+func newTestServer(t *testing.T, configure func(cfg *config.Config)) (*container.Container, http.Handler) {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.App.Mode = "release"
+	cfg.Database.Type = "sqlite"
+	cfg.Database.Path = filepath.Join(t.TempDir(), "test.db")
+	cfg.Cache.Driver = "memory"
+	cfg.Security.PasswordHash.Algorithm = "bcrypt"
+	cfg.Security.PasswordHash.BcryptCost = 4
+	if configure != nil {
+		configure(cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("invalid test config: %v", err)
+	}
+
+	c, err := container.New(cfg)
+	if err != nil {
+		t.Fatalf("container.New: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c, router.Setup(c)
+}
+
+// do serves one request and returns the recorded response
+func do(h http.Handler, method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+// usernames decodes the users listed in a /users response
+func usernames(t *testing.T, w *httptest.ResponseRecorder) []string {
+	t.Helper()
+	var resp struct {
+		Data struct {
+			List []dto.UserResponse `json:"list"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode users: %v\n%s", err, w.Body)
+	}
+	names := make([]string, 0, len(resp.Data.List))
+	for _, u := range resp.Data.List {
+		names = append(names, u.Username)
+	}
+	return names
+}
+
+func TestAPIKeysOfSuperAdminsStayInTheirTenant(t *testing.T) {
+	c, h := newTestServer(t, nil)
+	ctx := tenant.WithTenant(context.Background(), tenant.DefaultID)
+	if _, err := c.TenantService.Create(ctx, &dto.CreateTenantRequest{Code: "acme", AdminUsername: "wile", AdminPassword: "Zq8#vLm2!pT9x"}); err != nil {
+		t.Fatalf("Create tenant: %v", err)
+	}
+
+	session, err := c.AuthService.Login(context.Background(), &dto.LoginRequest{Username: "admin", Password: "admin123"}, service.ClientInfo{IP: "192.0.2.60"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	claims, err := c.TokenService.Parse(context.Background(), session.Token)
+	if err != nil || !claims.SuperAdmin {
+		t.Fatalf("the default admin is no super-admin: %+v, %v", claims, err)
+	}
+	key, err := c.APIKeyService.Create(ctx, claims.UserID, &dto.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"user:read"}})
+	if err != nil {
+		t.Fatalf("Create API key: %v", err)
+	}
+	bearer := map[string]string{"Authorization": "Bearer " + session.Token}
+	apiKey := map[string]string{"X-API-Key": key.Key}
+
+	// The session of the super-admin manages tenants and crosses into them
+	if w := do(h, http.MethodGet, "/api/v1/tenants", "", bearer); w.Code != http.StatusOK {
+		t.Fatalf("GET /tenants with a session = %d %s", w.Code, w.Body)
+	}
+	bearer["X-Tenant"] = "acme"
+	if w := do(h, http.MethodGet, "/api/v1/users", "", bearer); w.Code != http.StatusOK || strings.Join(usernames(t, w), ",") != "wile" {
+		t.Fatalf("GET /users in acme with a session = %d %s", w.Code, w.Body)
+	}
+
+	// The super-admin's key only reaches what its scopes grant in its own tenant
+	if w := do(h, http.MethodGet, "/api/v1/tenants", "", apiKey); w.Code != http.StatusForbidden {
+		t.Fatalf("GET /tenants with an API key = %d, want 403", w.Code)
+	}
+	if w := do(h, http.MethodGet, "/api/v1/users", "", apiKey); w.Code != http.StatusOK || strings.Join(usernames(t, w), ",") != "admin" {
+		t.Fatalf("GET /users with an API key = %d %s", w.Code, w.Body)
+	}
+	for _, override := range []string{"acme", service.AllTenants} {
+		apiKey["X-Tenant"] = override
+		w := do(h, http.MethodGet, "/api/v1/users", "", apiKey)
+		if w.Code != http.StatusForbidden || strings.Contains(w.Body.String(), "wile") {
+			t.Fatalf("GET /users with an API key and X-Tenant %q = %d %s", override, w.Code, w.Body)
+		}
+	}
+}
+
+func TestSCIMRequestBodiesAreAuditedRedacted(t *testing.T) {
+	const token = "scim-test-token-0123456789abcdef0123"
+	c, h := newTestServer(t, func(cfg *config.Config) {
+		cfg.SCIM.Enabled = true
+		cfg.SCIM.Token = token
+	})
+	scim := map[string]string{"Authorization": "Bearer " + token}
+
+	w := do(h, http.MethodPost, "/scim/v2/Users", `{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"userName":"olga"}`, scim)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /Users = %d %s", w.Code, w.Body)
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+
+	// The password fails the policy, so no diff is recorded and the audit
+	// log falls back to the request body
+	patch := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"replace","path":"password","value":"hunter2"}]}`
+	w = do(h, http.MethodPatch, "/scim/v2/Users/"+created.ID, patch, map[string]string{
+		"Authorization": "Bearer " + token,
+		"Content-Type":  "application/scim+json; charset=utf-8",
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("PATCH /Users/:id = %d %s", w.Code, w.Body)
+	}
+
+	// Closing the audit service writes the queued entries
+	c.AuditService.Close()
+	entries, _, err := c.AuditService.List(tenant.WithTenant(context.Background(), tenant.DefaultID), &dto.QueryAuditLogRequest{Method: http.MethodPatch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("%d PATCH entries, want 1", len(entries))
+	}
+	recorded, err := json.Marshal(entries[0].Changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := entries[0].Changes["Operations"]; !ok {
+		t.Fatalf("body not recorded: %s", recorded)
+	}
+	if strings.Contains(string(recorded), "hunter2") {
+		t.Fatalf("password recorded in the audit log: %s", recorded)
+	}
+}
+
+func TestCaptchasAreRateLimitedPerIP(t *testing.T) {
+	_, h := newTestServer(t, func(cfg *config.Config) {
+		cfg.Security.Login.CaptchaRate = 2
+	})
+	for i := 0; i < 2; i++ {
+		if w := do(h, http.MethodGet, "/api/v1/auth/captcha", "", nil); w.Code != http.StatusOK {
+			t.Fatalf("captcha %d = %d %s", i+1, w.Code, w.Body)
+		}
+	}
+	w := do(h, http.MethodGet, "/api/v1/auth/captcha", "", nil)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("captcha over the limit = %d, Retry-After %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	// Another client has its own allowance
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/captcha", nil)
+	req.RemoteAddr = "198.51.100.7:4321"
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("captcha from another client = %d %s", w.Code, w.Body)
+	}
+}
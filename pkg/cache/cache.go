@@ -20,7 +20,10 @@ type Cache interface {
 	Set(ctx context.Context, key string, value []byte, expiration time.Duration) error
 	SetString(ctx context.Context, key, value string, expiration time.Duration) error
 	Delete(ctx context.Context, key string) error
+	// Take 原子地读取并删除键，并发调用时只有一个能取到值，用于一次性的验证码、state 等
+	Take(ctx context.Context, key string) ([]byte, error)
 	Exists(ctx context.Context, key string) (bool, error)
+	// Increment 原子递增；键不存在时从 0 开始并设置过期时间，已存在时不改变过期时间（固定窗口计数）
 	Increment(ctx context.Context, key string, expiration time.Duration) (int64, error)
 	DeleteByPrefix(ctx context.Context, prefix string) error
 	Close() error
@@ -104,21 +107,37 @@ func (c *MemoryCache) Delete(_ context.Context, key string) error {
 	return nil
 }
 
+// Take 读取并删除（互斥锁保证只有一个调用方取到值）
+func (c *MemoryCache) Take(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	val, err := c.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Delete(key)
+	return val, nil
+}
+
 func (c *MemoryCache) Exists(_ context.Context, key string) (bool, error) {
 	_, found := c.cache.Get(key)
 	return found, nil
 }
 
-// Increment 原子递增（互斥锁保证原子性）
+// Increment 原子递增（互斥锁保证原子性），已存在的键保留原有过期时间
 func (c *MemoryCache) Increment(_ context.Context, key string, exp time.Duration) (int64, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if exp == 0 {
 		exp = c.defaultExpiration
 	}
-	val, found := c.cache.Get(key)
+	val, expires, found := c.cache.GetWithExpiration(key)
 	var count int64
 	if found {
+		exp = gocache.NoExpiration
+		if !expires.IsZero() {
+			exp = max(time.Until(expires), time.Nanosecond)
+		}
 		switch v := val.(type) {
 		case int64:
 			count = v
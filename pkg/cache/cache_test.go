@@ -0,0 +1,26 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-ddd-scaffold/pkg/cache"
+)
+
+func TestMemoryIncrementKeepsExpiration(t *testing.T) {
+	c := cache.NewMemoryCache(time.Minute, time.Minute)
+	ctx := context.Background()
+
+	if _, err := c.Increment(ctx, "hits", 200*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(120 * time.Millisecond)
+	if n, _ := c.Increment(ctx, "hits", 200*time.Millisecond); n != 2 {
+		t.Fatalf("Increment = %d, want 2", n)
+	}
+	time.Sleep(120 * time.Millisecond)
+	if n, _ := c.Increment(ctx, "hits", 200*time.Millisecond); n != 1 {
+		t.Fatalf("Increment after the window = %d, want 1", n)
+	}
+}
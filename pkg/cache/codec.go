@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec 缓存值的序列化方式
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	Name() string
+}
+
+// 内置的序列化方式
+var (
+	JSONCodec    Codec = jsonCodec{}
+	GobCodec     Codec = gobCodec{}
+	MsgpackCodec Codec = msgpackCodec{}
+)
+
+// jsonCodec 可读性最好，跨语言通用；只序列化导出字段
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return "json" }
+
+// gobCodec 保留 Go 类型信息，接口类型的字段需先 gob.Register 具体类型
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func (gobCodec) Name() string { return "gob" }
+
+// msgpackCodec 比 JSON 更紧凑、更快；字段名取自 msgpack 标签，没有标签时为 Go 字段名
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error)      { return msgpack.Marshal(v) }
+func (msgpackCodec) Unmarshal(data []byte, v any) error { return msgpack.Unmarshal(data, v) }
+func (msgpackCodec) Name() string                       { return "msgpack" }
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Invalidation 失效消息：Key 被写入或删除，其他实例应丢弃本地的副本
+type Invalidation struct {
+	Source string `json:"source"`           // 发送实例的 ID，实例据此忽略自己发出的消息
+	Key    string `json:"key"`              // 键，Prefix 为 true 时为前缀
+	Prefix bool   `json:"prefix,omitempty"` // 按前缀失效
+}
+
+// Invalidator 失效消息的广播通道，可替换为不同的传输方式
+type Invalidator interface {
+	// Publish 向所有订阅者（包括发送者自己）广播消息
+	Publish(ctx context.Context, msg Invalidation) error
+	// Subscribe 注册消息处理函数，返回取消订阅的函数。处理函数不应阻塞。
+	Subscribe(handler func(Invalidation)) (unsubscribe func(), err error)
+}
+
+// MemoryInvalidator 进程内的失效通道，共享同一实例的多个缓存如同多个服务实例，用于测试和单机部署。
+// Publish 同步调用所有处理函数。
+type MemoryInvalidator struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]func(Invalidation)
+}
+
+// NewMemoryInvalidator 创建进程内的失效通道
+func NewMemoryInvalidator() *MemoryInvalidator {
+	return &MemoryInvalidator{handlers: make(map[int]func(Invalidation))}
+}
+
+func (b *MemoryInvalidator) Publish(_ context.Context, msg Invalidation) error {
+	b.mu.RLock()
+	handlers := make([]func(Invalidation), 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(msg)
+	}
+	return nil
+}
+
+func (b *MemoryInvalidator) Subscribe(handler func(Invalidation)) (func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	return func() {
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}, nil
+}
+
+// RedisInvalidator 基于 Redis 发布订阅的失效通道。
+// 断线期间的消息会丢失，go-redis 会自动重新订阅；期间的本地副本最多保留到其有效期结束。
+type RedisInvalidator struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewRedisInvalidator 创建 Redis 失效通道，channel 为频道全名（不会自动加键前缀）
+func NewRedisInvalidator(client redis.UniversalClient, channel string) *RedisInvalidator {
+	return &RedisInvalidator{client: client, channel: channel}
+}
+
+func (r *RedisInvalidator) Publish(ctx context.Context, msg Invalidation) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return r.client.Publish(ctx, r.channel, payload).Err()
+}
+
+// Subscribe 订阅频道，确认订阅成功后才返回；处理函数在单独的 goroutine 中依次调用
+func (r *RedisInvalidator) Subscribe(handler func(Invalidation)) (func(), error) {
+	ctx := context.Background()
+	pubsub := r.client.Subscribe(ctx, r.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for m := range pubsub.Channel() {
+			var msg Invalidation
+			if json.Unmarshal([]byte(m.Payload), &msg) == nil {
+				handler(msg)
+			}
+		}
+	}()
+	return func() {
+		pubsub.Close()
+		<-done
+	}, nil
+}
+
+var (
+	_ Invalidator = (*MemoryInvalidator)(nil)
+	_ Invalidator = (*RedisInvalidator)(nil)
+)
@@ -0,0 +1,252 @@
+package cache
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis 部署模式
+const (
+	RedisStandalone = "standalone"
+	RedisSentinel   = "sentinel"
+	RedisCluster    = "cluster"
+)
+
+// scanBatch 每次 SCAN 返回的建议键数，也是一批删除的键数
+const scanBatch = 500
+
+// incrementScript 原子递增，只在键新建时设置过期时间（毫秒，0 为永不过期），与 MemoryCache.Increment 的语义一致
+var incrementScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// RedisOptions Redis 连接参数
+type RedisOptions struct {
+	Mode             string   // standalone、sentinel、cluster，默认 standalone
+	Addrs            []string // standalone 取第一个地址；sentinel 为哨兵地址；cluster 为种子节点
+	MasterName       string   // sentinel 模式的主节点名
+	Username         string
+	Password         string
+	SentinelUsername string
+	SentinelPassword string
+	DB               int           // standalone 和 sentinel 模式的库号
+	KeyPrefix        string        // 所有键的命名空间前缀，如 "myapp:"
+	TLS              bool          // 使用 TLS 连接
+	PoolSize         int           // 每个节点的连接池大小，<= 0 时为 10 × CPU 核数
+	DialTimeout      time.Duration // 0 时为 5 秒
+	ReadTimeout      time.Duration // 0 时为 3 秒
+	WriteTimeout     time.Duration // 0 时与 ReadTimeout 相同
+}
+
+// RedisCache 基于 Redis 的缓存实现，多个实例可共享锁定计数和 Token 黑名单
+type RedisCache struct {
+	client            redis.UniversalClient
+	prefix            string
+	defaultExpiration time.Duration
+}
+
+// NewRedisCache 按部署模式创建 Redis 缓存；不会主动连接，可用 Ping 检查连通性。
+// defaultExpiration 为未指定过期时间的键的有效期，0 时为 15 分钟。
+func NewRedisCache(opts RedisOptions, defaultExpiration time.Duration) (*RedisCache, error) {
+	if len(opts.Addrs) == 0 {
+		return nil, errors.New("cache: redis address required")
+	}
+	var tlsConfig *tls.Config
+	if opts.TLS {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	var client redis.UniversalClient
+	switch opts.Mode {
+	case "", RedisStandalone:
+		client = redis.NewClient(&redis.Options{
+			Addr:         opts.Addrs[0],
+			Username:     opts.Username,
+			Password:     opts.Password,
+			DB:           opts.DB,
+			TLSConfig:    tlsConfig,
+			PoolSize:     opts.PoolSize,
+			DialTimeout:  opts.DialTimeout,
+			ReadTimeout:  opts.ReadTimeout,
+			WriteTimeout: opts.WriteTimeout,
+		})
+	case RedisSentinel:
+		if opts.MasterName == "" {
+			return nil, errors.New("cache: redis sentinel mode requires a master name")
+		}
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       opts.MasterName,
+			SentinelAddrs:    opts.Addrs,
+			SentinelUsername: opts.SentinelUsername,
+			SentinelPassword: opts.SentinelPassword,
+			Username:         opts.Username,
+			Password:         opts.Password,
+			DB:               opts.DB,
+			TLSConfig:        tlsConfig,
+			PoolSize:         opts.PoolSize,
+			DialTimeout:      opts.DialTimeout,
+			ReadTimeout:      opts.ReadTimeout,
+			WriteTimeout:     opts.WriteTimeout,
+		})
+	case RedisCluster:
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        opts.Addrs,
+			Username:     opts.Username,
+			Password:     opts.Password,
+			TLSConfig:    tlsConfig,
+			PoolSize:     opts.PoolSize,
+			DialTimeout:  opts.DialTimeout,
+			ReadTimeout:  opts.ReadTimeout,
+			WriteTimeout: opts.WriteTimeout,
+		})
+	default:
+		return nil, fmt.Errorf("cache: unsupported redis mode %q", opts.Mode)
+	}
+	return NewRedisCacheWithClient(client, opts.KeyPrefix, defaultExpiration), nil
+}
+
+// NewRedisCacheWithClient 使用已有的客户端创建 Redis 缓存，Close 时关闭该客户端
+func NewRedisCacheWithClient(client redis.UniversalClient, keyPrefix string, defaultExpiration time.Duration) *RedisCache {
+	if defaultExpiration == 0 {
+		defaultExpiration = 15 * time.Minute
+	}
+	return &RedisCache{client: client, prefix: keyPrefix, defaultExpiration: defaultExpiration}
+}
+
+// Client 底层客户端，用于发布订阅等缓存接口之外的操作；键不会自动加前缀
+func (c *RedisCache) Client() redis.UniversalClient {
+	return c.client
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	val, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	return val, err
+}
+
+func (c *RedisCache) GetString(ctx context.Context, key string) (string, error) {
+	val, err := c.client.Get(ctx, c.prefix+key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+	return val, err
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, exp time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, c.expiration(exp)).Err()
+}
+
+func (c *RedisCache) SetString(ctx context.Context, key, value string, exp time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, c.expiration(exp)).Err()
+}
+
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
+
+// Take 用 GETDEL 原子地读取并删除（需要 Redis 6.2 及以上）
+func (c *RedisCache) Take(ctx context.Context, key string) ([]byte, error) {
+	val, err := c.client.GetDel(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	return val, err
+}
+
+func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.client.Exists(ctx, c.prefix+key).Result()
+	return n > 0, err
+}
+
+// Increment 原子递增，键不存在时新建并设置过期时间 exp，已存在时保留原有过期时间（Lua 脚本保证原子性）
+func (c *RedisCache) Increment(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	return incrementScript.Run(ctx, c.client, []string{c.prefix + key}, c.expiration(exp).Milliseconds()).Int64()
+}
+
+// DeleteByPrefix 用 SCAN 分批查找并删除前缀匹配的键，不会像 KEYS 一样阻塞服务端。
+// 集群模式下逐个扫描所有主节点。扫描期间新写入的键可能不会被删除。
+func (c *RedisCache) DeleteByPrefix(ctx context.Context, prefix string) error {
+	match := escapeGlob(c.prefix+prefix) + "*"
+	if cluster, ok := c.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return deleteMatching(ctx, node, match)
+		})
+	}
+	return deleteMatching(ctx, c.client, match)
+}
+
+func (c *RedisCache) Close() error { return c.client.Close() }
+func (c *RedisCache) Name() string { return "redis" }
+
+func (c *RedisCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// expiration 0 取默认有效期；与 go-cache 一致，负数表示永不过期（Redis 中为 0）
+func (c *RedisCache) expiration(exp time.Duration) time.Duration {
+	switch {
+	case exp == 0:
+		return c.defaultExpiration
+	case exp < 0:
+		return 0
+	}
+	return exp
+}
+
+// deleteMatching 扫描一个节点并删除匹配的键。
+// 每个键单独 UNLINK，集群中同一批键分属不同槽位也不会出现 CROSSSLOT 错误。
+func deleteMatching(ctx context.Context, client redis.Cmdable, match string) error {
+	iter := client.Scan(ctx, 0, match, scanBatch).Iterator()
+	batch := make([]string, 0, scanBatch)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range batch {
+				pipe.Unlink(ctx, key)
+			}
+			return nil
+		})
+		batch = batch[:0]
+		return err
+	}
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == scanBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// escapeGlob 转义 SCAN MATCH 模式中的通配符
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var _ Cache = (*RedisCache)(nil)
@@ -0,0 +1,164 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"go-ddd-scaffold/pkg/cache"
+	"go-ddd-scaffold/pkg/cache/redistest"
+)
+
+func startRedis(t *testing.T) *redistest.Server {
+	t.Helper()
+	srv, err := redistest.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRedisIncrementSetsTTLOnCreation(t *testing.T) {
+	srv := startRedis(t)
+	c := srv.Cache("app:", time.Minute)
+	ctx := context.Background()
+
+	for want := int64(1); want <= 2; want++ {
+		n, err := c.Increment(ctx, "hits", 10*time.Second)
+		if err != nil || n != want {
+			t.Fatalf("Increment = %d, %v, want %d", n, err, want)
+		}
+		if want == 1 {
+			srv.FastForward(4 * time.Second)
+		}
+	}
+	// 再次递增不会延长窗口
+	if ttl := srv.TTL("app:hits"); ttl != 6*time.Second {
+		t.Fatalf("TTL = %v, want 6s", ttl)
+	}
+	srv.FastForward(6 * time.Second)
+	if n, _ := c.Increment(ctx, "hits", 10*time.Second); n != 1 {
+		t.Fatalf("Increment after the window = %d, want 1", n)
+	}
+
+	// 0 取默认有效期，负数永不过期
+	if _, err := c.Increment(ctx, "default", 0); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := srv.TTL("app:default"); ttl != time.Minute {
+		t.Fatalf("TTL = %v, want the default 1m", ttl)
+	}
+	if _, err := c.Increment(ctx, "forever", -1); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := srv.TTL("app:forever"); ttl != 0 {
+		t.Fatalf("TTL = %v, want none", ttl)
+	}
+}
+
+func TestRedisDeleteByPrefix(t *testing.T) {
+	srv := startRedis(t)
+	c := srv.Cache("app:", 0)
+	other := srv.Cache("other:", 0)
+	ctx := context.Background()
+
+	// 超过一批的键，确认分批删除
+	for i := range 1200 {
+		if err := c.SetString(ctx, fmt.Sprintf("user:%d", i), "x", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, key := range []string{"users", "a*b:1", "axb:1", "a[x]b:1"} {
+		if err := c.SetString(ctx, key, "x", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := other.SetString(ctx, "user:1", "x", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.DeleteByPrefix(ctx, "user:"); err != nil {
+		t.Fatal(err)
+	}
+	// 前缀中的通配符按字面匹配
+	if err := c.DeleteByPrefix(ctx, "a*b:"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteByPrefix(ctx, "a[x]"); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := srv.Keys()
+	want := []string{"app:axb:1", "app:users", "other:user:1"}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+}
+
+func TestRedisNotFound(t *testing.T) {
+	srv := startRedis(t)
+	c := srv.Cache("app:", 0)
+	ctx := context.Background()
+
+	if _, err := c.Get(ctx, "missing"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("Get = %v, want ErrNotFound", err)
+	}
+	if _, err := c.GetString(ctx, "missing"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("GetString = %v, want ErrNotFound", err)
+	}
+	if _, err := c.Take(ctx, "missing"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("Take = %v, want ErrNotFound", err)
+	}
+
+	if err := c.Set(ctx, "state", []byte("abc"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := c.Take(ctx, "state"); err != nil || string(val) != "abc" {
+		t.Fatalf("Take = %q, %v", val, err)
+	}
+	if _, err := c.Take(ctx, "state"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("second Take = %v, want ErrNotFound", err)
+	}
+
+	// 过期的键同样是 ErrNotFound
+	if err := c.SetString(ctx, "code", "123456", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	srv.FastForward(time.Second)
+	if _, err := c.GetString(ctx, "code"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("GetString after expiry = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRedisClusterDeleteByPrefix(t *testing.T) {
+	srv := startRedis(t)
+	// 替身以单个节点应答 CLUSTER SLOTS，集群客户端经 ForEachMaster 扫描该节点
+	c, err := cache.NewRedisCache(cache.RedisOptions{Mode: cache.RedisCluster, Addrs: []string{srv.Addr()}, KeyPrefix: "app:"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	ctx := context.Background()
+
+	for _, key := range []string{"session:1", "session:2", "session*", "sessions"} {
+		if err := c.SetString(ctx, key, "x", 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := c.Increment(ctx, "session:count", time.Minute); err != nil || n != 1 {
+		t.Fatalf("Increment = %d, %v", n, err)
+	}
+	if err := c.DeleteByPrefix(ctx, "session:"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteByPrefix(ctx, "session*"); err != nil {
+		t.Fatal(err)
+	}
+	if keys := srv.Keys(); !slices.Equal(keys, []string{"app:sessions"}) {
+		t.Fatalf("keys = %v, want [app:sessions]", keys)
+	}
+}
@@ -0,0 +1,45 @@
+// Package redistest 提供进程内的 Redis 替身（基于 miniredis），用于在 Go 测试和本地开发中运行 RedisCache
+// 及依赖它的锁定计数、Token 黑名单等代码，无需真实的 Redis 服务。
+//
+// 替身支持字符串、过期时间、SCAN、Lua 脚本和发布订阅，不支持哨兵与集群模式。
+// 键不会随真实时间过期，测试中用 FastForward 推进服务端时间。
+package redistest
+
+import (
+	"time"
+
+	"go-ddd-scaffold/pkg/cache"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// Server 进程内的 Redis 服务，可并发使用
+type Server struct {
+	*miniredis.Miniredis
+}
+
+// Start 在本机随机端口启动服务
+func Start() (*Server, error) {
+	m, err := miniredis.Run()
+	if err != nil {
+		return nil, err
+	}
+	return &Server{Miniredis: m}, nil
+}
+
+// StartAddr 在指定地址启动服务，如 ":6379"
+func StartAddr(addr string) (*Server, error) {
+	m := miniredis.NewMiniRedis()
+	if err := m.StartAddr(addr); err != nil {
+		return nil, err
+	}
+	return &Server{Miniredis: m}, nil
+}
+
+// Cache 创建连接到该服务的 RedisCache；多个 Cache 共享数据，如同多个服务实例。
+// defaultExpiration 为 0 时为 15 分钟。
+func (s *Server) Cache(keyPrefix string, defaultExpiration time.Duration) *cache.RedisCache {
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	return cache.NewRedisCacheWithClient(client, keyPrefix, defaultExpiration)
+}
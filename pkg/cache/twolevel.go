@@ -0,0 +1,263 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// TwoLevelOptions 二级缓存参数
+type TwoLevelOptions struct {
+	L1TTL       time.Duration // 本地副本的最长有效期，0 时为 30 秒
+	L2TTL       time.Duration // 未指定过期时间时写入 L2 的有效期，0 时使用 L2 自己的默认值
+	Invalidator Invalidator   // 失效通道，nil 时不广播（只有一个实例时）
+	InstanceID  string        // 本实例 ID，空时随机生成
+}
+
+// LevelStats 一级缓存的读取统计
+type LevelStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// TwoLevelStats 二级缓存的统计
+type TwoLevelStats struct {
+	L1            LevelStats `json:"l1"`
+	L2            LevelStats `json:"l2"`
+	Published     uint64     `json:"published"`      // 发出的失效消息
+	PublishErrors uint64     `json:"publish_errors"` // 发送失败的失效消息
+	Received      uint64     `json:"received"`       // 收到的其他实例的失效消息
+}
+
+// TwoLevelCache 二级缓存：本地 L1（通常是 MemoryCache）在前，共享的 L2（如 RedisCache）在后。
+//
+// 读取先查 L1，未命中再查 L2 并把结果写回 L1；写入和删除先作用于 L2，再更新本地 L1，
+// 并通过 Invalidator 通知其他实例丢弃各自的 L1 副本。Increment 只在 L2 上计数。
+// 失效消息丢失时，其他实例的副本最多保留 L1TTL；从 L2 读回的副本也可能比 L2 中的键晚 L1TTL 过期，
+// 因此 L1TTL 应远小于数据可容忍的陈旧时间。Exists 未命中 L1 时不写回，锁定标记等不会因此延长。
+type TwoLevelCache struct {
+	l1, l2      Cache
+	l1TTL       time.Duration
+	l2TTL       time.Duration
+	invalidator Invalidator
+	instanceID  string
+	unsubscribe func()
+
+	l1Hits, l1Misses, l2Hits, l2Misses atomic.Uint64
+	published, publishErrors, received atomic.Uint64
+}
+
+// NewTwoLevelCache 组合两个缓存并订阅失效通道；Close 时关闭两级缓存并取消订阅
+func NewTwoLevelCache(l1, l2 Cache, opts TwoLevelOptions) (*TwoLevelCache, error) {
+	if opts.L1TTL <= 0 {
+		opts.L1TTL = 30 * time.Second
+	}
+	if opts.InstanceID == "" {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		opts.InstanceID = hex.EncodeToString(b)
+	}
+	c := &TwoLevelCache{
+		l1:          l1,
+		l2:          l2,
+		l1TTL:       opts.L1TTL,
+		l2TTL:       opts.L2TTL,
+		invalidator: opts.Invalidator,
+		instanceID:  opts.InstanceID,
+	}
+	if c.invalidator != nil {
+		unsubscribe, err := c.invalidator.Subscribe(c.onInvalidation)
+		if err != nil {
+			return nil, err
+		}
+		c.unsubscribe = unsubscribe
+	}
+	return c, nil
+}
+
+func (c *TwoLevelCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if val, err := c.l1.Get(ctx, key); err == nil {
+		c.l1Hits.Add(1)
+		return val, nil
+	}
+	c.l1Misses.Add(1)
+	val, err := c.l2.Get(ctx, key)
+	if err != nil {
+		c.countL2(err)
+		return nil, err
+	}
+	c.l2Hits.Add(1)
+	_ = c.l1.Set(ctx, key, val, c.l1TTL)
+	return val, nil
+}
+
+func (c *TwoLevelCache) GetString(ctx context.Context, key string) (string, error) {
+	if val, err := c.l1.GetString(ctx, key); err == nil {
+		c.l1Hits.Add(1)
+		return val, nil
+	}
+	c.l1Misses.Add(1)
+	val, err := c.l2.GetString(ctx, key)
+	if err != nil {
+		c.countL2(err)
+		return "", err
+	}
+	c.l2Hits.Add(1)
+	_ = c.l1.SetString(ctx, key, val, c.l1TTL)
+	return val, nil
+}
+
+func (c *TwoLevelCache) Set(ctx context.Context, key string, value []byte, exp time.Duration) error {
+	if err := c.l2.Set(ctx, key, value, c.l2Expiration(exp)); err != nil {
+		return err
+	}
+	c.publish(ctx, Invalidation{Key: key})
+	return c.l1.Set(ctx, key, value, c.l1Expiration(exp))
+}
+
+func (c *TwoLevelCache) SetString(ctx context.Context, key, value string, exp time.Duration) error {
+	if err := c.l2.SetString(ctx, key, value, c.l2Expiration(exp)); err != nil {
+		return err
+	}
+	c.publish(ctx, Invalidation{Key: key})
+	return c.l1.SetString(ctx, key, value, c.l1Expiration(exp))
+}
+
+func (c *TwoLevelCache) Delete(ctx context.Context, key string) error {
+	if err := c.l2.Delete(ctx, key); err != nil {
+		return err
+	}
+	c.publish(ctx, Invalidation{Key: key})
+	return c.l1.Delete(ctx, key)
+}
+
+// Take 只从 L2 取值：本地副本可能已被其他实例取走，不能作为依据
+func (c *TwoLevelCache) Take(ctx context.Context, key string) ([]byte, error) {
+	val, err := c.l2.Take(ctx, key)
+	_ = c.l1.Delete(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	c.publish(ctx, Invalidation{Key: key})
+	return val, nil
+}
+
+func (c *TwoLevelCache) Exists(ctx context.Context, key string) (bool, error) {
+	if ok, err := c.l1.Exists(ctx, key); err == nil && ok {
+		c.l1Hits.Add(1)
+		return true, nil
+	}
+	c.l1Misses.Add(1)
+	ok, err := c.l2.Exists(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		c.l2Hits.Add(1)
+	} else {
+		c.l2Misses.Add(1)
+	}
+	return ok, nil
+}
+
+// Increment 在 L2 上原子递增，并丢弃各实例中该键的本地副本
+func (c *TwoLevelCache) Increment(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	n, err := c.l2.Increment(ctx, key, c.l2Expiration(exp))
+	if err != nil {
+		return 0, err
+	}
+	c.publish(ctx, Invalidation{Key: key})
+	_ = c.l1.Delete(ctx, key)
+	return n, nil
+}
+
+func (c *TwoLevelCache) DeleteByPrefix(ctx context.Context, prefix string) error {
+	if err := c.l2.DeleteByPrefix(ctx, prefix); err != nil {
+		return err
+	}
+	c.publish(ctx, Invalidation{Key: prefix, Prefix: true})
+	return c.l1.DeleteByPrefix(ctx, prefix)
+}
+
+func (c *TwoLevelCache) Close() error {
+	if c.unsubscribe != nil {
+		c.unsubscribe()
+	}
+	return errors.Join(c.l1.Close(), c.l2.Close())
+}
+
+func (c *TwoLevelCache) Name() string { return c.l1.Name() + "+" + c.l2.Name() }
+
+func (c *TwoLevelCache) Ping(ctx context.Context) error {
+	if err := c.l1.Ping(ctx); err != nil {
+		return err
+	}
+	return c.l2.Ping(ctx)
+}
+
+// Stats 自创建以来的统计
+func (c *TwoLevelCache) Stats() TwoLevelStats {
+	return TwoLevelStats{
+		L1:            LevelStats{Hits: c.l1Hits.Load(), Misses: c.l1Misses.Load()},
+		L2:            LevelStats{Hits: c.l2Hits.Load(), Misses: c.l2Misses.Load()},
+		Published:     c.published.Load(),
+		PublishErrors: c.publishErrors.Load(),
+		Received:      c.received.Load(),
+	}
+}
+
+// publish 广播失效消息。L2 已经写入，发送失败只计入统计，不让调用方误以为写入失败。
+func (c *TwoLevelCache) publish(ctx context.Context, msg Invalidation) {
+	if c.invalidator == nil {
+		return
+	}
+	msg.Source = c.instanceID
+	if err := c.invalidator.Publish(ctx, msg); err != nil {
+		c.publishErrors.Add(1)
+		return
+	}
+	c.published.Add(1)
+}
+
+// onInvalidation 丢弃其他实例写入或删除的键的本地副本
+func (c *TwoLevelCache) onInvalidation(msg Invalidation) {
+	if msg.Source == c.instanceID {
+		return
+	}
+	c.received.Add(1)
+	ctx := context.Background()
+	if msg.Prefix {
+		_ = c.l1.DeleteByPrefix(ctx, msg.Key)
+	} else {
+		_ = c.l1.Delete(ctx, msg.Key)
+	}
+}
+
+// countL2 只把键不存在计为未命中，连接错误等不计入
+func (c *TwoLevelCache) countL2(err error) {
+	if errors.Is(err, ErrNotFound) {
+		c.l2Misses.Add(1)
+	}
+}
+
+// l1Expiration 本地副本的有效期不超过 L1TTL
+func (c *TwoLevelCache) l1Expiration(exp time.Duration) time.Duration {
+	if exp > 0 && exp < c.l1TTL {
+		return exp
+	}
+	return c.l1TTL
+}
+
+func (c *TwoLevelCache) l2Expiration(exp time.Duration) time.Duration {
+	if exp == 0 {
+		return c.l2TTL
+	}
+	return exp
+}
+
+var _ Cache = (*TwoLevelCache)(nil)
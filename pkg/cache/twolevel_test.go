@@ -0,0 +1,195 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-ddd-scaffold/pkg/cache"
+)
+
+// newInstances 创建共享同一个 L2 和失效通道的两个二级缓存，如同两个服务实例
+func newInstances(t *testing.T, l1TTL time.Duration) (a, b *cache.TwoLevelCache, l2 cache.Cache) {
+	t.Helper()
+	l2 = cache.NewMemoryCache(time.Hour, time.Minute)
+	invalidator := cache.NewMemoryInvalidator()
+	newInstance := func(id string) *cache.TwoLevelCache {
+		c, err := cache.NewTwoLevelCache(cache.NewMemoryCache(time.Hour, time.Minute), l2, cache.TwoLevelOptions{
+			L1TTL: l1TTL, Invalidator: invalidator, InstanceID: id,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	return newInstance("a"), newInstance("b"), l2
+}
+
+func requireString(t *testing.T, c cache.Cache, key, want string) {
+	t.Helper()
+	got, err := c.GetString(context.Background(), key)
+	if err != nil || got != want {
+		t.Fatalf("GetString(%s) = %q, %v, want %q", key, got, err, want)
+	}
+}
+
+func requireNotFound(t *testing.T, c cache.Cache, key string) {
+	t.Helper()
+	if _, err := c.GetString(context.Background(), key); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("GetString(%s) = %v, want ErrNotFound", key, err)
+	}
+}
+
+func TestTwoLevelInvalidatesOtherInstances(t *testing.T) {
+	a, b, _ := newInstances(t, time.Hour)
+	ctx := context.Background()
+
+	if err := a.SetString(ctx, "user:1", "alice", 0); err != nil {
+		t.Fatal(err)
+	}
+	requireString(t, b, "user:1", "alice")
+
+	// b 的本地副本在 a 写入后被丢弃，不会读到旧值
+	if err := a.SetString(ctx, "user:1", "alicia", 0); err != nil {
+		t.Fatal(err)
+	}
+	requireString(t, b, "user:1", "alicia")
+
+	if err := a.Delete(ctx, "user:1"); err != nil {
+		t.Fatal(err)
+	}
+	requireNotFound(t, b, "user:1")
+
+	// 计数只在 L2 上进行，本地副本同样被丢弃
+	if err := b.SetString(ctx, "count", "1", 0); err != nil {
+		t.Fatal(err)
+	}
+	requireString(t, a, "count", "1")
+	if n, err := b.Increment(ctx, "count", 0); err != nil || n != 2 {
+		t.Fatalf("Increment = %d, %v, want 2", n, err)
+	}
+	requireString(t, a, "count", "2")
+
+	// 一次性的值被一个实例取走后，另一个实例的副本不再可用
+	if err := a.SetString(ctx, "state", "xyz", 0); err != nil {
+		t.Fatal(err)
+	}
+	requireString(t, b, "state", "xyz")
+	if val, err := a.Take(ctx, "state"); err != nil || string(val) != "xyz" {
+		t.Fatalf("Take = %q, %v", val, err)
+	}
+	if _, err := b.Take(ctx, "state"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("second Take = %v, want ErrNotFound", err)
+	}
+	requireNotFound(t, b, "state")
+}
+
+func TestTwoLevelPrefixInvalidation(t *testing.T) {
+	a, b, _ := newInstances(t, time.Hour)
+	ctx := context.Background()
+
+	for _, key := range []string{"user:1", "user:2", "role:1"} {
+		if err := a.SetString(ctx, key, key, 0); err != nil {
+			t.Fatal(err)
+		}
+		requireString(t, b, key, key)
+	}
+	if err := a.DeleteByPrefix(ctx, "user:"); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []cache.Cache{a, b} {
+		requireNotFound(t, c, "user:1")
+		requireNotFound(t, c, "user:2")
+		requireString(t, c, "role:1", "role:1")
+	}
+}
+
+func TestTwoLevelL1TTLCap(t *testing.T) {
+	ctx := context.Background()
+	l2 := cache.NewMemoryCache(time.Hour, time.Minute)
+	c, err := cache.NewTwoLevelCache(cache.NewMemoryCache(time.Hour, time.Minute), l2, cache.TwoLevelOptions{L1TTL: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 绕过二级缓存修改 L2，如同没有收到失效消息：本地副本最多保留 L1TTL
+	if err := c.SetString(ctx, "k", "v1", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := l2.SetString(ctx, "k", "v2", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	requireString(t, c, "k", "v1")
+	time.Sleep(150 * time.Millisecond)
+	requireString(t, c, "k", "v2")
+
+	// 从 L2 读回的副本同样受 L1TTL 限制
+	if err := l2.SetString(ctx, "k", "v3", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	requireString(t, c, "k", "v2")
+	time.Sleep(150 * time.Millisecond)
+	requireString(t, c, "k", "v3")
+
+	// 比 L1TTL 短的有效期保持不变
+	if err := c.SetString(ctx, "short", "v", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	requireNotFound(t, c, "short")
+}
+
+// failingInvalidator 发送总是失败的失效通道
+type failingInvalidator struct{}
+
+func (failingInvalidator) Publish(context.Context, cache.Invalidation) error {
+	return errors.New("channel down")
+}
+
+func (failingInvalidator) Subscribe(func(cache.Invalidation)) (func(), error) {
+	return func() {}, nil
+}
+
+func TestTwoLevelStats(t *testing.T) {
+	a, b, _ := newInstances(t, time.Hour)
+	ctx := context.Background()
+
+	requireNotFound(t, a, "k")              // L1 和 L2 都未命中
+	_ = a.SetString(ctx, "k", "v", 0)       // 发出一条失效消息，b 收到
+	requireString(t, a, "k", "v")           // L1 命中
+	requireString(t, b, "k", "v")           // L1 未命中，L2 命中
+	requireString(t, b, "k", "v")           // L1 命中
+	if ok, _ := b.Exists(ctx, "gone"); ok { // L1 和 L2 都未命中
+		t.Fatal("Exists(gone) = true")
+	}
+
+	want := cache.TwoLevelStats{
+		L1:        cache.LevelStats{Hits: 1, Misses: 1},
+		L2:        cache.LevelStats{Hits: 0, Misses: 1},
+		Published: 1,
+	}
+	if got := a.Stats(); got != want {
+		t.Fatalf("a.Stats() = %+v, want %+v", got, want)
+	}
+	want = cache.TwoLevelStats{
+		L1:       cache.LevelStats{Hits: 1, Misses: 2},
+		L2:       cache.LevelStats{Hits: 1, Misses: 1},
+		Received: 1,
+	}
+	if got := b.Stats(); got != want {
+		t.Fatalf("b.Stats() = %+v, want %+v", got, want)
+	}
+
+	// 广播失败不影响写入，只计入统计
+	c, err := cache.NewTwoLevelCache(cache.NewMemoryCache(0, 0), cache.NewMemoryCache(0, 0), cache.TwoLevelOptions{Invalidator: failingInvalidator{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetString(ctx, "k", "v", 0); err != nil {
+		t.Fatalf("SetString = %v", err)
+	}
+	if got := c.Stats(); got.PublishErrors != 1 || got.Published != 0 {
+		t.Fatalf("Stats() = %+v, want one publish error", got)
+	}
+}
@@ -0,0 +1,276 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// 缓存项格式：1 字节类型 + 8 字节新鲜期截止时间（Unix 毫秒）+ 序列化后的值
+const (
+	entryValue    byte = 1
+	entryNegative byte = 2
+	entryHeader        = 9
+)
+
+// TypedOptions 类型化缓存参数
+type TypedOptions struct {
+	Codec       Codec                       // 序列化方式，nil 时为 JSONCodec
+	Prefix      string                      // 键前缀，如 "user:"，DeleteAll 按此前缀删除
+	TTL         time.Duration               // 调用方传入 ttl <= 0 时的有效期，0 时为 5 分钟
+	NegativeTTL time.Duration               // loader 返回 ErrNotFound 时缓存"不存在"的时长，0 不缓存
+	Jitter      float64                     // 有效期随机缩短的最大比例（0-1），避免同批写入的键同时过期
+	StaleTTL    time.Duration               // 过期后仍返回旧值、同时在后台刷新的时长，0 不启用
+	LoadTimeout time.Duration               // 单次 loader 调用的超时，0 时为 10 秒
+	OnError     func(key string, err error) // 后台刷新或写入缓存失败时调用，这些错误不会返回给调用方
+}
+
+// TypedStats 类型化缓存的统计
+type TypedStats struct {
+	Hits         uint64 `json:"hits"`          // 命中新鲜的值
+	StaleHits    uint64 `json:"stale_hits"`    // 命中过期但仍可用的值，并触发了后台刷新
+	NegativeHits uint64 `json:"negative_hits"` // 命中缓存的"不存在"
+	Misses       uint64 `json:"misses"`        // 未命中，需要等待加载
+	Loads        uint64 `json:"loads"`         // 实际调用 loader 的次数，并发的未命中只计一次
+	LoadErrors   uint64 `json:"load_errors"`   // loader 返回 ErrNotFound 以外的错误
+	Refreshes    uint64 `json:"refreshes"`     // 后台刷新次数
+}
+
+// Typed 在 Cache 之上按类型 T 读写缓存，调用方无需自己序列化。
+//
+// GetOrLoad 实现旁路缓存：未命中时调用 loader 并写回，同一键的并发未命中通过 singleflight 合并为一次加载，
+// 避免缓存失效瞬间大量请求同时打到数据库。loader 返回 ErrNotFound 表示数据不存在，可按 NegativeTTL 缓存；
+// 有效期可随机缩短（Jitter）以错开过期时间；设置 StaleTTL 后，刚过期的值会先返回给调用方，同时在后台刷新。
+//
+// 同一次加载的结果由所有等待的调用方共享，T 为切片、map 或指针时不要修改返回值。
+// 无法解码的缓存项（如旧版本写入或换了序列化方式）视为不存在。
+type Typed[T any] struct {
+	cache      Cache
+	opts       TypedOptions
+	group      singleflight.Group
+	refreshing sync.Map
+
+	hits, staleHits, negativeHits, misses atomic.Uint64
+	loads, loadErrors, refreshes          atomic.Uint64
+}
+
+// NewTyped 创建类型化缓存
+func NewTyped[T any](c Cache, opts TypedOptions) *Typed[T] {
+	if opts.Codec == nil {
+		opts.Codec = JSONCodec
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 5 * time.Minute
+	}
+	opts.Jitter = min(max(opts.Jitter, 0), 1)
+	if opts.LoadTimeout <= 0 {
+		opts.LoadTimeout = 10 * time.Second
+	}
+	return &Typed[T]{cache: c, opts: opts}
+}
+
+// entry 解码后的缓存项
+type entry[T any] struct {
+	negative   bool
+	freshUntil time.Time
+	value      T
+}
+
+func (e *entry[T]) fresh() bool { return time.Now().Before(e.freshUntil) }
+
+// Get 读取缓存的值，不触发加载；不存在、缓存为"不存在"时返回 ErrNotFound。处于 StaleTTL 内的旧值照常返回。
+func (t *Typed[T]) Get(ctx context.Context, key string) (T, error) {
+	var zero T
+	e, err := t.read(ctx, t.opts.Prefix+key)
+	if err != nil {
+		return zero, err
+	}
+	if e.negative {
+		return zero, ErrNotFound
+	}
+	return e.value, nil
+}
+
+// Set 写入值，ttl <= 0 时使用 TypedOptions.TTL
+func (t *Typed[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return t.set(ctx, t.opts.Prefix+key, value, ttl)
+}
+
+// Delete 删除键，下次读取时重新加载
+func (t *Typed[T]) Delete(ctx context.Context, key string) error {
+	return t.cache.Delete(ctx, t.opts.Prefix+key)
+}
+
+// DeleteAll 删除 Prefix 下的所有键；未设置 Prefix 时返回错误，以免清空整个缓存
+func (t *Typed[T]) DeleteAll(ctx context.Context) error {
+	if t.opts.Prefix == "" {
+		return errors.New("cache: DeleteAll requires a key prefix")
+	}
+	return t.cache.DeleteByPrefix(ctx, t.opts.Prefix)
+}
+
+// GetOrLoad 读取缓存的值，未命中时调用 loader 加载并按 ttl 写回（ttl <= 0 时使用 TypedOptions.TTL）。
+//
+// 同一键的并发调用只执行一次 loader。loader 在独立于调用方的上下文中运行（保留其中的值，受 LoadTimeout 限制），
+// 调用方取消时立即返回 ctx.Err()，加载仍会完成并写入缓存供后续请求使用。
+// loader 返回 ErrNotFound 时本方法也返回 ErrNotFound。读取缓存出错（如 Redis 不可用）时直接调用 loader。
+func (t *Typed[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	key = t.opts.Prefix + key
+	if e, err := t.read(ctx, key); err == nil {
+		switch {
+		case e.fresh() && e.negative:
+			t.negativeHits.Add(1)
+			return zero, ErrNotFound
+		case e.fresh():
+			t.hits.Add(1)
+			return e.value, nil
+		case !e.negative:
+			t.staleHits.Add(1)
+			t.refresh(ctx, key, ttl, loader)
+			return e.value, nil
+		}
+	}
+
+	t.misses.Add(1)
+	ch := t.group.DoChan(key, func() (any, error) {
+		return t.load(ctx, key, ttl, loader)
+	})
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	}
+}
+
+// Stats 自创建以来的统计
+func (t *Typed[T]) Stats() TypedStats {
+	return TypedStats{
+		Hits:         t.hits.Load(),
+		StaleHits:    t.staleHits.Load(),
+		NegativeHits: t.negativeHits.Load(),
+		Misses:       t.misses.Load(),
+		Loads:        t.loads.Load(),
+		LoadErrors:   t.loadErrors.Load(),
+		Refreshes:    t.refreshes.Load(),
+	}
+}
+
+// refresh 在后台重新加载过期的值，同一键同时只有一个刷新，并与前台的加载合并
+func (t *Typed[T]) refresh(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) {
+	if _, busy := t.refreshing.LoadOrStore(key, struct{}{}); busy {
+		return
+	}
+	t.refreshes.Add(1)
+	go func() {
+		defer t.refreshing.Delete(key)
+		_, err, _ := t.group.Do(key, func() (any, error) {
+			return t.load(ctx, key, ttl, loader)
+		})
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			t.report(key, err)
+		}
+	}()
+}
+
+// load 调用 loader 并写回缓存。进入 singleflight 之前可能有另一次加载刚刚完成，因此先再查一次缓存。
+func (t *Typed[T]) load(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), t.opts.LoadTimeout)
+	defer cancel()
+
+	if e, err := t.read(ctx, key); err == nil && e.fresh() {
+		if e.negative {
+			return zero, ErrNotFound
+		}
+		return e.value, nil
+	}
+
+	t.loads.Add(1)
+	value, err := loader(ctx)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		// 数据已不存在：缓存"不存在"，未启用负缓存时删除可能残留的旧值
+		if t.opts.NegativeTTL > 0 {
+			err = t.write(ctx, key, entryNegative, nil, t.opts.NegativeTTL, 0)
+		} else {
+			err = t.cache.Delete(ctx, key)
+		}
+		if err != nil {
+			t.report(key, err)
+		}
+		return zero, ErrNotFound
+	case err != nil:
+		t.loadErrors.Add(1)
+		return zero, err
+	}
+
+	if err := t.set(ctx, key, value, ttl); err != nil {
+		t.report(key, err)
+	}
+	return value, nil
+}
+
+// read 读取并解码缓存项，无法解码时返回 ErrNotFound
+func (t *Typed[T]) read(ctx context.Context, key string) (*entry[T], error) {
+	data, err := t.cache.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < entryHeader {
+		return nil, ErrNotFound
+	}
+	e := &entry[T]{freshUntil: time.UnixMilli(int64(binary.BigEndian.Uint64(data[1:entryHeader])))}
+	switch data[0] {
+	case entryNegative:
+		e.negative = true
+	case entryValue:
+		if err := t.opts.Codec.Unmarshal(data[entryHeader:], &e.value); err != nil {
+			return nil, ErrNotFound
+		}
+	default:
+		return nil, ErrNotFound
+	}
+	return e, nil
+}
+
+// set 序列化并写入值，在新鲜期之外额外保留 StaleTTL
+func (t *Typed[T]) set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	payload, err := t.opts.Codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if ttl <= 0 {
+		ttl = t.opts.TTL
+	}
+	return t.write(ctx, key, entryValue, payload, ttl, t.opts.StaleTTL)
+}
+
+// write 写入缓存项：新鲜期为加上抖动后的 ttl，底层缓存的有效期为新鲜期加 stale
+func (t *Typed[T]) write(ctx context.Context, key string, kind byte, payload []byte, ttl, stale time.Duration) error {
+	if t.opts.Jitter > 0 {
+		ttl -= time.Duration(rand.Float64() * t.opts.Jitter * float64(ttl))
+		ttl = max(ttl, time.Millisecond)
+	}
+	data := make([]byte, entryHeader, entryHeader+len(payload))
+	data[0] = kind
+	binary.BigEndian.PutUint64(data[1:], uint64(time.Now().Add(ttl).UnixMilli()))
+	data = append(data, payload...)
+	return t.cache.Set(ctx, key, data, ttl+stale)
+}
+
+func (t *Typed[T]) report(key string, err error) {
+	if t.opts.OnError != nil {
+		t.opts.OnError(key, err)
+	}
+}
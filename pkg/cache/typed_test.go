@@ -0,0 +1,241 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-ddd-scaffold/pkg/cache"
+)
+
+type profile struct {
+	ID      uint
+	Name    string
+	Score   float64
+	Active  bool
+	Tags    []string
+	Counts  map[string]int
+	Manager *profile
+}
+
+func newProfile() profile {
+	return profile{
+		ID: 7, Name: "alice", Score: 4.5, Active: true,
+		Tags:    []string{"admin", "staff"},
+		Counts:  map[string]int{"logins": 3},
+		Manager: &profile{ID: 1, Name: "root"},
+	}
+}
+
+func TestTypedCodecsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	want := newProfile()
+	for _, codec := range []cache.Codec{cache.JSONCodec, cache.GobCodec, cache.MsgpackCodec} {
+		t.Run(codec.Name(), func(t *testing.T) {
+			typed := cache.NewTyped[profile](cache.NewMemoryCache(0, 0), cache.TypedOptions{Codec: codec, Prefix: "profile:"})
+			if err := typed.Set(ctx, "7", want, 0); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			got, err := typed.Get(ctx, "7")
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("Get = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	// 换了序列化方式后旧的缓存项视为不存在，重新加载
+	c := cache.NewMemoryCache(0, 0)
+	if err := cache.NewTyped[profile](c, cache.TypedOptions{Codec: cache.JSONCodec}).Set(ctx, "7", want, 0); err != nil {
+		t.Fatal(err)
+	}
+	typed := cache.NewTyped[profile](c, cache.TypedOptions{Codec: cache.GobCodec})
+	if _, err := typed.Get(ctx, "7"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("Get with another codec = %v, want ErrNotFound", err)
+	}
+	got, err := typed.GetOrLoad(ctx, "7", 0, func(context.Context) (profile, error) { return want, nil })
+	if err != nil || got.Name != want.Name {
+		t.Fatalf("GetOrLoad = %+v, %v", got, err)
+	}
+}
+
+func TestTypedGetOrLoadCollapsesConcurrentMisses(t *testing.T) {
+	typed := cache.NewTyped[string](cache.NewMemoryCache(0, 0), cache.TypedOptions{})
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "value", nil
+	}
+
+	const callers = 50
+	var wg sync.WaitGroup
+	var started sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		started.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+			got, err := typed.GetOrLoad(ctx, "k", 0, loader)
+			if err == nil && got != "value" {
+				err = fmt.Errorf("got %q", got)
+			}
+			errs <- err
+		}()
+	}
+	started.Wait()
+	// 等所有调用方进入 singleflight 后再放行加载
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("GetOrLoad: %v", err)
+		}
+	}
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("loader called %d times, want 1", n)
+	}
+	if stats := typed.Stats(); stats.Loads != 1 || stats.Misses != callers {
+		t.Fatalf("Stats() = %+v, want 1 load for %d misses", stats, callers)
+	}
+	if _, err := typed.GetOrLoad(ctx, "k", 0, loader); err != nil || calls.Load() != 1 {
+		t.Fatalf("cached GetOrLoad = %v after %d loads", err, calls.Load())
+	}
+}
+
+func TestTypedNegativeCaching(t *testing.T) {
+	ctx := context.Background()
+	var calls atomic.Int32
+	missing := func(context.Context) (string, error) {
+		calls.Add(1)
+		return "", cache.ErrNotFound
+	}
+
+	typed := cache.NewTyped[string](cache.NewMemoryCache(0, 0), cache.TypedOptions{NegativeTTL: time.Minute})
+	for range 3 {
+		if _, err := typed.GetOrLoad(ctx, "ghost", 0, missing); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("GetOrLoad = %v, want ErrNotFound", err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("loader called %d times, want 1", n)
+	}
+	if stats := typed.Stats(); stats.NegativeHits != 2 {
+		t.Fatalf("Stats() = %+v, want 2 negative hits", stats)
+	}
+	if _, err := typed.Get(ctx, "ghost"); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("Get = %v, want ErrNotFound", err)
+	}
+
+	// 未启用负缓存时每次都重新加载
+	calls.Store(0)
+	typed = cache.NewTyped[string](cache.NewMemoryCache(0, 0), cache.TypedOptions{})
+	for range 3 {
+		if _, err := typed.GetOrLoad(ctx, "ghost", 0, missing); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("GetOrLoad = %v, want ErrNotFound", err)
+		}
+	}
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("loader called %d times without negative caching, want 3", n)
+	}
+
+	// 其他错误不缓存
+	calls.Store(0)
+	failing := func(context.Context) (string, error) {
+		calls.Add(1)
+		return "", errors.New("database down")
+	}
+	typed = cache.NewTyped[string](cache.NewMemoryCache(0, 0), cache.TypedOptions{NegativeTTL: time.Minute})
+	for range 2 {
+		if _, err := typed.GetOrLoad(ctx, "k", 0, failing); err == nil || errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("GetOrLoad = %v, want the loader's error", err)
+		}
+	}
+	if n, stats := calls.Load(), typed.Stats(); n != 2 || stats.LoadErrors != 2 {
+		t.Fatalf("loader called %d times with stats %+v, want 2 calls and 2 errors", n, stats)
+	}
+}
+
+func TestTypedJitterBounds(t *testing.T) {
+	srv := startRedis(t)
+	typed := cache.NewTyped[int](srv.Cache("", 0), cache.TypedOptions{Prefix: "n:", TTL: time.Hour, Jitter: 0.5})
+	ctx := context.Background()
+
+	lowest, highest := time.Hour, time.Duration(0)
+	for i := range 200 {
+		if err := typed.Set(ctx, fmt.Sprint(i), i, 0); err != nil {
+			t.Fatal(err)
+		}
+		ttl := srv.TTL(fmt.Sprintf("n:%d", i))
+		if ttl < 30*time.Minute || ttl > time.Hour {
+			t.Fatalf("TTL = %v, want between 30m and 1h", ttl)
+		}
+		lowest, highest = min(lowest, ttl), max(highest, ttl)
+	}
+	// 200 个键的有效期应当分散开
+	if highest-lowest < 10*time.Minute {
+		t.Fatalf("TTLs range from %v to %v, want them spread out", lowest, highest)
+	}
+}
+
+func TestTypedStaleWhileRevalidate(t *testing.T) {
+	typed := cache.NewTyped[string](cache.NewMemoryCache(0, 0), cache.TypedOptions{StaleTTL: time.Minute})
+	ctx := context.Background()
+
+	if err := typed.Set(ctx, "k", "v1", 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "v2", nil
+	}
+
+	// 过期的值立即返回，加载在后台进行，并发的过期读取只触发一次刷新
+	for range 3 {
+		got, err := typed.GetOrLoad(ctx, "k", time.Minute, loader)
+		if err != nil || got != "v1" {
+			t.Fatalf("GetOrLoad = %q, %v, want the stale v1", got, err)
+		}
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if got, _ := typed.Get(ctx, "k"); got == "v2" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the background refresh did not store v2")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got, err := typed.GetOrLoad(ctx, "k", time.Minute, loader); err != nil || got != "v2" {
+		t.Fatalf("GetOrLoad = %q, %v, want the refreshed v2", got, err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("loader called %d times, want 1", n)
+	}
+	if stats := typed.Stats(); stats.StaleHits != 3 || stats.Refreshes != 1 || stats.Hits != 1 {
+		t.Fatalf("Stats() = %+v, want 3 stale hits, 1 refresh and 1 hit", stats)
+	}
+}
@@ -0,0 +1,86 @@
+package captcha
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go-ddd-scaffold/pkg/cache"
+)
+
+func TestVerifyIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(0, 0)
+	m := New(c, Options{})
+
+	ch, err := m.Generate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err := c.GetString(ctx, keyPrefix+ch.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var passed atomic.Int32
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if m.Verify(ctx, ch.ID, answer) {
+				passed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := passed.Load(); n != 1 {
+		t.Fatalf("solved captcha accepted %d times, want 1", n)
+	}
+}
+
+func TestVerifyWrongAnswerBurnsChallenge(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewMemoryCache(0, 0)
+	m := New(c, Options{})
+
+	ch, err := m.Generate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, _ := c.GetString(ctx, keyPrefix+ch.ID)
+	if m.Verify(ctx, ch.ID, "wrong") {
+		t.Fatal("wrong answer accepted")
+	}
+	if m.Verify(ctx, ch.ID, answer) {
+		t.Fatal("challenge still usable after a wrong answer")
+	}
+	if m.Verify(ctx, "", "") {
+		t.Fatal("empty id accepted")
+	}
+}
+
+func TestRandomDigitsAreUniform(t *testing.T) {
+	const n = 1_000_000
+	digits, err := randomDigits(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(digits) != n {
+		t.Fatalf("got %d digits, want %d", len(digits), n)
+	}
+	var counts [10]int
+	for _, d := range digits {
+		if d > 9 {
+			t.Fatalf("digit %d out of range", d)
+		}
+		counts[d]++
+	}
+	// 均匀时每个数字约 100000 次，标准差约 300；取模的偏差会让 0-5 多出约 1.6%
+	for d, got := range counts {
+		if got < n/10*985/1000 || got > n/10*1015/1000 {
+			t.Errorf("digit %d drawn %d times, want about %d", d, got, n/10)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package captcha
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"math/rand/v2"
+)
+
+// glyphs 数字 0-9 的 5x7 点阵，每行低 5 位从左到右
+var glyphs = [10][7]uint8{
+	{0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	{0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	{0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	{0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	{0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	{0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	{0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	{0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	{0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	{0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+}
+
+const (
+	glyphCols = 5
+	glyphRows = 7
+)
+
+// render 将数字绘制为 PNG：每位数字随机偏移、倾斜和着色，再叠加干扰曲线和噪点
+func render(w io.Writer, digits []byte, width, height int) error {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	bg := color.RGBA{uint8(230 + rand.IntN(26)), uint8(230 + rand.IntN(26)), uint8(230 + rand.IntN(26)), 255}
+	fill(img, img.Bounds(), bg)
+
+	// 倾斜最多使字形水平偏移 maxShear*glyphH/2，两侧留出相应边距
+	const maxShear = 0.2
+	cell := max(1, min(width*4/5/len(digits)/glyphCols, height*4/5/glyphRows))
+	glyphW, glyphH := glyphCols*cell, glyphRows*cell
+	pad := int(maxShear*float64(glyphH)/2) + 1
+	slot := (width - 2*pad) / len(digits)
+
+	for i, d := range digits {
+		ink := darkColor()
+		shear := (rand.Float64()*2 - 1) * maxShear
+		x0 := pad + i*slot + rand.IntN(max(1, slot-glyphW))
+		y0 := rand.IntN(max(1, height-glyphH))
+		for row, bits := range glyphs[d] {
+			dx := int(shear * float64(row*cell-glyphH/2))
+			for col := 0; col < glyphCols; col++ {
+				if bits&(1<<(glyphCols-1-col)) != 0 {
+					x, y := x0+col*cell+dx, y0+row*cell
+					fill(img, image.Rect(x, y, x+cell, y+cell), ink)
+				}
+			}
+		}
+	}
+
+	// 干扰曲线穿过数字，增加分割难度
+	for n := 0; n < 2; n++ {
+		ink := darkColor()
+		base := float64(height/4 + rand.IntN(height/2+1))
+		amp := float64(height) / 6 * rand.Float64()
+		freq := (1 + rand.Float64()*2) * math.Pi / float64(width)
+		phase := rand.Float64() * 2 * math.Pi
+		for x := 0; x < width; x++ {
+			y := int(base + amp*math.Sin(float64(x)*freq+phase))
+			fill(img, image.Rect(x, y, x+1, y+1+cell/3), ink)
+		}
+	}
+	for n := 0; n < width*height/40; n++ {
+		img.SetRGBA(rand.IntN(width), rand.IntN(height), darkColor())
+	}
+
+	return png.Encode(w, img)
+}
+
+func darkColor() color.RGBA {
+	return color.RGBA{uint8(rand.IntN(140)), uint8(rand.IntN(140)), uint8(rand.IntN(140)), 255}
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
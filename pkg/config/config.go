@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -12,8 +13,16 @@ type Config struct {
 	App      AppConfig      `mapstructure:"app"`
 	Server   ServerConfig   `mapstructure:"server"`
 	Database DatabaseConfig `mapstructure:"database"`
+	Cache    CacheConfig    `mapstructure:"cache"`
 	Log      LogConfig      `mapstructure:"log"`
 	JWT      JWTConfig      `mapstructure:"jwt"`
+	Security SecurityConfig `mapstructure:"security"`
+	Password PasswordConfig `mapstructure:"password"`
+	SSO      SSOConfig      `mapstructure:"sso"`
+	LDAP     LDAPConfig     `mapstructure:"ldap"`
+	WebAuthn WebAuthnConfig `mapstructure:"webauthn"`
+	SCIM     SCIMConfig     `mapstructure:"scim"`
+	Mail     MailConfig     `mapstructure:"mail"`
 }
 
 type AppConfig struct {
@@ -23,10 +32,11 @@ type AppConfig struct {
 }
 
 type ServerConfig struct {
-	Host         string `mapstructure:"host"`
-	Port         int    `mapstructure:"port"`
-	ReadTimeout  int    `mapstructure:"read_timeout"`  // seconds
-	WriteTimeout int    `mapstructure:"write_timeout"` // seconds
+	Host           string   `mapstructure:"host"`
+	Port           int      `mapstructure:"port"`
+	ReadTimeout    int      `mapstructure:"read_timeout"`    // seconds
+	WriteTimeout   int      `mapstructure:"write_timeout"`   // seconds
+	TrustedProxies []string `mapstructure:"trusted_proxies"` // proxies allowed to set X-Forwarded-For
 }
 
 type DatabaseConfig struct {
@@ -43,6 +53,34 @@ type DatabaseConfig struct {
 	AutoMigrate     bool   `mapstructure:"auto_migrate"`
 }
 
+// CacheConfig selects the cache holding lockout counters, the token
+// blacklist, captchas and other short-lived state. Run more than one replica
+// only with redis.
+type CacheConfig struct {
+	Driver              string      `mapstructure:"driver"`               // memory, redis, two_level (memory in front of redis)
+	DefaultTTL          int         `mapstructure:"default_ttl"`          // seconds an entry set without expiration is kept
+	LocalTTL            int         `mapstructure:"local_ttl"`            // two_level: seconds a local copy is kept at most
+	InvalidationChannel string      `mapstructure:"invalidation_channel"` // two_level: pub/sub channel under redis.key_prefix
+	Redis               RedisConfig `mapstructure:"redis"`
+}
+
+type RedisConfig struct {
+	Mode             string   `mapstructure:"mode"`        // standalone, sentinel, cluster
+	Addrs            []string `mapstructure:"addrs"`       // server, sentinel or cluster seed addresses (host:port)
+	MasterName       string   `mapstructure:"master_name"` // sentinel master
+	Username         string   `mapstructure:"username"`
+	Password         string   `mapstructure:"password"`
+	SentinelUsername string   `mapstructure:"sentinel_username"`
+	SentinelPassword string   `mapstructure:"sentinel_password"`
+	DB               int      `mapstructure:"db"`         // standalone and sentinel only
+	KeyPrefix        string   `mapstructure:"key_prefix"` // namespace of every key, e.g. "myapp:"
+	TLS              bool     `mapstructure:"tls"`
+	PoolSize         int      `mapstructure:"pool_size"`     // connections per node, 0 = 10 per CPU
+	DialTimeout      int      `mapstructure:"dial_timeout"`  // seconds
+	ReadTimeout      int      `mapstructure:"read_timeout"`  // seconds
+	WriteTimeout     int      `mapstructure:"write_timeout"` // seconds
+}
+
 type LogConfig struct {
 	Level      string `mapstructure:"level"`       // debug, info, warn, error
 	Format     string `mapstructure:"format"`      // console, json
@@ -55,9 +93,140 @@ type LogConfig struct {
 }
 
 type JWTConfig struct {
-	Secret       string `mapstructure:"secret"`
-	Expire       int    `mapstructure:"expire"`        // hours
-	RefreshHours int    `mapstructure:"refresh_hours"` // refresh window in hours
+	Secret        string         `mapstructure:"secret"`         // HS256 secret, used only when no keys are configured
+	Keys          []JWTKeyConfig `mapstructure:"keys"`           // asymmetric keys: the first signs, all of them verify
+	Issuer        string         `mapstructure:"issuer"`         // iss claim issued and required
+	Audience      string         `mapstructure:"audience"`       // aud claim issued and required
+	AccessMinutes int            `mapstructure:"access_minutes"` // access token lifetime in minutes
+	RefreshHours  int            `mapstructure:"refresh_hours"`  // refresh token lifetime in hours
+}
+
+type JWTKeyConfig struct {
+	ID             string `mapstructure:"id"`               // kid header, defaults to the RFC 7638 thumbprint
+	PrivateKeyFile string `mapstructure:"private_key_file"` // PEM: RSA (RS256), P-256 (ES256) or Ed25519 (EdDSA)
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // PEM, for retired keys that only verify
+}
+
+type SecurityConfig struct {
+	Authenticators         []string            `mapstructure:"authenticators"` // password login backends tried in order: local, ldap
+	Login                  LoginSecurityConfig `mapstructure:"login"`
+	PasswordHash           PasswordHashConfig  `mapstructure:"password_hash"`
+	PasswordHistory        int                 `mapstructure:"password_history"`         // previous passwords that cannot be reused, 0 disables
+	EmailVerificationHours int                 `mapstructure:"email_verification_hours"` // lifetime of mailed verification links
+	PasswordResetMinutes   int                 `mapstructure:"password_reset_minutes"`   // lifetime of mailed password reset links
+	ImpersonationMinutes   int                 `mapstructure:"impersonation_minutes"`    // lifetime of admin impersonation tokens
+}
+
+type PasswordHashConfig struct {
+	Algorithm         string `mapstructure:"algorithm"`          // argon2id, bcrypt; outdated hashes are upgraded at login
+	Argon2Memory      uint32 `mapstructure:"argon2_memory"`      // KiB
+	Argon2Iterations  uint32 `mapstructure:"argon2_iterations"`  // passes over the memory
+	Argon2Parallelism uint8  `mapstructure:"argon2_parallelism"` // lanes
+	BcryptCost        int    `mapstructure:"bcrypt_cost"`        // 4-31
+	MaxConcurrent     int    `mapstructure:"max_concurrent"`     // hash operations running at once, 0 = number of CPUs
+	QueueTimeout      int    `mapstructure:"queue_timeout"`      // seconds to wait for a free slot before answering 503
+}
+
+// PasswordConfig is the policy for passwords chosen by users or set by administrators
+type PasswordConfig struct {
+	MinLength      int    `mapstructure:"min_length"` // characters, not bytes
+	MaxLength      int    `mapstructure:"max_length"` // characters, at most 72; 0 = only the 72 accepted by requests
+	RequireUpper   bool   `mapstructure:"require_upper"`
+	RequireLower   bool   `mapstructure:"require_lower"`
+	RequireNumber  bool   `mapstructure:"require_number"`
+	RequireSpecial bool   `mapstructure:"require_special"`
+	MinStrength    int    `mapstructure:"min_strength"`  // entropy-based score 0-4 a password must reach, 0 disables
+	BreachedList   string `mapstructure:"breached_list"` // builtin, a SHA-1 hash file or a k-anonymity prefix directory; empty disables
+}
+
+type LoginSecurityConfig struct {
+	MaxAccountFailures int `mapstructure:"max_account_failures"` // failures per username before lockout
+	MaxIPFailures      int `mapstructure:"max_ip_failures"`      // failures per client IP before lockout
+	FailureWindow      int `mapstructure:"failure_window"`       // minutes a failure counter is kept
+	LockoutMinutes     int `mapstructure:"lockout_minutes"`      // first lockout, doubled on each further failure
+	MaxLockoutMinutes  int `mapstructure:"max_lockout_minutes"`  // backoff cap
+	CaptchaAfter       int `mapstructure:"captcha_after"`        // failures per username or client IP before a captcha is required, 0 disables
+	CaptchaTTL         int `mapstructure:"captcha_ttl"`          // seconds a captcha can be answered
+	CaptchaRate        int `mapstructure:"captcha_rate"`         // captchas a client IP may request per minute, 0 disables the limit
+}
+
+type SSOConfig struct {
+	Enabled       bool             `mapstructure:"enabled"`
+	Issuer        string           `mapstructure:"issuer"`         // OIDC issuer URL; endpoints are discovered from it
+	ClientID      string           `mapstructure:"client_id"`      // OAuth2 client registered at the IdP
+	ClientSecret  string           `mapstructure:"client_secret"`  // empty for public clients (PKCE only)
+	RedirectURL   string           `mapstructure:"redirect_url"`   // <server>/api/v1/auth/oidc/callback, registered at the IdP
+	FrontendURL   string           `mapstructure:"frontend_url"`   // page the browser returns to after the callback
+	Scopes        []string         `mapstructure:"scopes"`         // requested scopes, "openid" is always added
+	UsernameClaim string           `mapstructure:"username_claim"` // claim used as the local username
+	GroupsClaim   string           `mapstructure:"groups_claim"`   // claim listing the user's IdP groups
+	RoleMappings  []SSORoleMapping `mapstructure:"role_mappings"`  // first matching group wins
+	DefaultRole   string           `mapstructure:"default_role"`   // role when no group matches; empty rejects the user
+	AutoProvision bool             `mapstructure:"auto_provision"` // create local users on first login
+	LinkByEmail   bool             `mapstructure:"link_by_email"`  // link existing non-admin users by verified email
+}
+
+type SSORoleMapping struct {
+	Group string `mapstructure:"group"`
+	Role  string `mapstructure:"role"`
+}
+
+type LDAPConfig struct {
+	URL                string           `mapstructure:"url"`                  // ldap://host:389 or ldaps://host:636
+	StartTLS           bool             `mapstructure:"start_tls"`            // upgrade ldap:// connections before binding
+	CAFile             string           `mapstructure:"ca_file"`              // PEM bundle to verify the server with, empty uses the system pool
+	InsecureSkipVerify bool             `mapstructure:"insecure_skip_verify"` // accept any server certificate (local testing only)
+	Timeout            int              `mapstructure:"timeout"`              // seconds per connection and operation
+	BindDN             string           `mapstructure:"bind_dn"`              // search user, empty searches anonymously
+	BindPassword       string           `mapstructure:"bind_password"`
+	BaseDN             string           `mapstructure:"base_dn"`            // subtree searched for users
+	UserFilter         string           `mapstructure:"user_filter"`        // %s is replaced by the escaped username
+	UsernameAttribute  string           `mapstructure:"username_attribute"` // used as the local username
+	NameAttribute      string           `mapstructure:"name_attribute"`
+	EmailAttribute     string           `mapstructure:"email_attribute"`
+	GroupAttribute     string           `mapstructure:"group_attribute"`  // user attribute listing group DNs, e.g. memberOf
+	GroupBaseDN        string           `mapstructure:"group_base_dn"`    // subtree searched with group_filter, defaults to base_dn
+	GroupFilter        string           `mapstructure:"group_filter"`     // %s is replaced by the escaped user DN, empty skips the search
+	RoleMappings       []SSORoleMapping `mapstructure:"role_mappings"`    // group DN or CN; first match wins
+	DefaultRole        string           `mapstructure:"default_role"`     // role when no group matches; empty rejects the user
+	AutoProvision      bool             `mapstructure:"auto_provision"`   // create local users on first login
+	LinkByUsername     bool             `mapstructure:"link_by_username"` // link existing local users with the same username
+}
+
+type WebAuthnConfig struct {
+	Enabled          bool     `mapstructure:"enabled"`
+	RPID             string   `mapstructure:"rp_id"`             // domain passkeys are bound to, e.g. example.com; cannot change later
+	RPDisplayName    string   `mapstructure:"rp_display_name"`   // shown by authenticators, defaults to app.name
+	RPOrigins        []string `mapstructure:"rp_origins"`        // origins of the pages running the ceremonies, e.g. https://admin.example.com
+	Timeout          int      `mapstructure:"timeout"`           // seconds a registration or login may take
+	UserVerification string   `mapstructure:"user_verification"` // required, preferred or discouraged
+}
+
+type SCIMConfig struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	Token       string `mapstructure:"token"`        // bearer token of the provisioning client, at least 32 characters
+	Tenant      string `mapstructure:"tenant"`       // code of the tenant provisioned users belong to
+	DefaultRole string `mapstructure:"default_role"` // role of new users and of users removed from their group
+	MaxResults  int    `mapstructure:"max_results"`  // page size cap of list requests
+	ManageRoles bool   `mapstructure:"manage_roles"` // let the identity provider create and delete roles through /Groups
+}
+
+type MailConfig struct {
+	Driver      string     `mapstructure:"driver"`        // log (development: messages are only logged) or smtp
+	From        string     `mapstructure:"from"`          // sender, e.g. "My Service <no-reply@example.com>"
+	LinkBaseURL string     `mapstructure:"link_base_url"` // public frontend URL that mailed links point to
+	TemplateDir string     `mapstructure:"template_dir"`  // files here override the built-in templates of the same name
+	Dir         string     `mapstructure:"dir"`           // log driver: also save each message as an .eml file here
+	SMTP        SMTPConfig `mapstructure:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host       string `mapstructure:"host"`
+	Port       int    `mapstructure:"port"`
+	Username   string `mapstructure:"username"` // empty skips authentication
+	Password   string `mapstructure:"password"`
+	Encryption string `mapstructure:"encryption"` // starttls, tls (implicit, port 465) or none (local test servers only)
+	Timeout    int    `mapstructure:"timeout"`    // seconds per message
 }
 
 // Load reads configuration from file
@@ -109,6 +278,20 @@ func DefaultConfig() *Config {
 			ConnMaxLifetime: 60,
 			AutoMigrate:     true,
 		},
+		Cache: CacheConfig{
+			Driver:              "memory",
+			DefaultTTL:          900,
+			LocalTTL:            30,
+			InvalidationChannel: "cache:invalidate",
+			Redis: RedisConfig{
+				Mode:         "standalone",
+				Addrs:        []string{"localhost:6379"},
+				KeyPrefix:    "myapp:",
+				DialTimeout:  5,
+				ReadTimeout:  3,
+				WriteTimeout: 3,
+			},
+		},
 		Log: LogConfig{
 			Level:      "info",
 			Format:     "console",
@@ -120,9 +303,82 @@ func DefaultConfig() *Config {
 			Compress:   true,
 		},
 		JWT: JWTConfig{
-			Secret:       "change-me-in-production",
-			Expire:       24,
-			RefreshHours: 168, // 7 days
+			Secret:        "change-me-in-production",
+			Issuer:        "my-service",
+			Audience:      "my-service",
+			AccessMinutes: 15,
+			RefreshHours:  168, // 7 days
+		},
+		Security: SecurityConfig{
+			Authenticators: []string{"local"},
+			Login: LoginSecurityConfig{
+				MaxAccountFailures: 5,
+				MaxIPFailures:      20,
+				FailureWindow:      15,
+				LockoutMinutes:     1,
+				MaxLockoutMinutes:  60,
+				CaptchaAfter:       3,
+				CaptchaTTL:         120,
+				CaptchaRate:        30,
+			},
+			PasswordHash: PasswordHashConfig{
+				Algorithm:         "argon2id",
+				Argon2Memory:      19 * 1024,
+				Argon2Iterations:  2,
+				Argon2Parallelism: 1,
+				BcryptCost:        12,
+				QueueTimeout:      5,
+			},
+			PasswordHistory:        5,
+			EmailVerificationHours: 48,
+			PasswordResetMinutes:   30,
+			ImpersonationMinutes:   30,
+		},
+		Password: PasswordConfig{
+			MinLength:    8,
+			MaxLength:    64,
+			MinStrength:  2,
+			BreachedList: "builtin",
+		},
+		SSO: SSOConfig{
+			FrontendURL:   "/login",
+			Scopes:        []string{"openid", "profile", "email"},
+			UsernameClaim: "preferred_username",
+			GroupsClaim:   "groups",
+			DefaultRole:   "user",
+			AutoProvision: true,
+		},
+		LDAP: LDAPConfig{
+			Timeout:           10,
+			UserFilter:        "(&(objectClass=person)(uid=%s))",
+			UsernameAttribute: "uid",
+			NameAttribute:     "cn",
+			EmailAttribute:    "mail",
+			GroupAttribute:    "memberOf",
+			DefaultRole:       "user",
+			AutoProvision:     true,
+		},
+		WebAuthn: WebAuthnConfig{
+			Enabled:          true,
+			RPID:             "localhost",
+			RPOrigins:        []string{"http://localhost:8080"},
+			Timeout:          300,
+			UserVerification: "preferred",
+		},
+		SCIM: SCIMConfig{
+			Tenant:      "default",
+			DefaultRole: "user",
+			MaxResults:  100,
+		},
+		Mail: MailConfig{
+			Driver:      "log",
+			From:        "My Service <no-reply@localhost>",
+			LinkBaseURL: "http://localhost:8080",
+			SMTP: SMTPConfig{
+				Port:       587,
+				Encryption: "starttls",
+				Timeout:    30,
+			},
 		},
 	}
 }
@@ -146,8 +402,137 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("unsupported database type: %s", c.Database.Type)
 	}
 
-	if c.JWT.Secret == "" {
-		return fmt.Errorf("jwt.secret is required")
+	if c.Cache.DefaultTTL <= 0 {
+		return fmt.Errorf("cache.default_ttl must be positive")
+	}
+	switch r := c.Cache.Redis; c.Cache.Driver {
+	case "memory":
+	case "redis", "two_level":
+		if c.Cache.Driver == "two_level" && (c.Cache.LocalTTL <= 0 || c.Cache.InvalidationChannel == "") {
+			return fmt.Errorf("cache.driver two_level requires a positive local_ttl and an invalidation_channel")
+		}
+		if len(r.Addrs) == 0 {
+			return fmt.Errorf("cache.redis.addrs is required")
+		}
+		switch r.Mode {
+		case "standalone", "cluster":
+		case "sentinel":
+			if r.MasterName == "" {
+				return fmt.Errorf("cache.redis sentinel mode requires master_name")
+			}
+		default:
+			return fmt.Errorf("unsupported cache.redis.mode: %s", r.Mode)
+		}
+		if r.Mode == "cluster" && r.DB != 0 {
+			return fmt.Errorf("cache.redis.db must be 0 in cluster mode")
+		}
+	default:
+		return fmt.Errorf("unsupported cache.driver: %s", c.Cache.Driver)
+	}
+
+	if len(c.JWT.Keys) == 0 && c.JWT.Secret == "" {
+		return fmt.Errorf("jwt.secret or jwt.keys is required")
+	}
+	for i, k := range c.JWT.Keys {
+		if k.PrivateKeyFile == "" && k.PublicKeyFile == "" {
+			return fmt.Errorf("jwt.keys[%d] requires private_key_file or public_key_file", i)
+		}
+	}
+	if len(c.JWT.Keys) > 0 && c.JWT.Keys[0].PrivateKeyFile == "" {
+		return fmt.Errorf("jwt.keys[0] is the signing key and requires private_key_file")
+	}
+	if c.JWT.Issuer == "" || c.JWT.Audience == "" {
+		return fmt.Errorf("jwt.issuer and jwt.audience are required")
+	}
+	if c.JWT.AccessMinutes <= 0 || c.JWT.RefreshHours <= 0 {
+		return fmt.Errorf("jwt.access_minutes and jwt.refresh_hours must be positive")
+	}
+
+	switch h := c.Security.PasswordHash; h.Algorithm {
+	case "argon2id":
+		if h.Argon2Memory == 0 || h.Argon2Iterations == 0 || h.Argon2Parallelism == 0 {
+			return fmt.Errorf("security.password_hash argon2 parameters must be positive")
+		}
+	case "bcrypt":
+		if h.BcryptCost < 4 || h.BcryptCost > 31 {
+			return fmt.Errorf("security.password_hash.bcrypt_cost must be between 4 and 31")
+		}
+	default:
+		return fmt.Errorf("unsupported security.password_hash.algorithm: %s", h.Algorithm)
+	}
+	if c.Security.ImpersonationMinutes <= 0 {
+		return fmt.Errorf("security.impersonation_minutes must be positive")
+	}
+
+	if c.Password.MinLength <= 0 {
+		return fmt.Errorf("password.min_length must be positive")
+	}
+	if c.Password.MaxLength != 0 && (c.Password.MaxLength < c.Password.MinLength || c.Password.MaxLength > 72) {
+		return fmt.Errorf("password.max_length must be 0 or between password.min_length and 72")
+	}
+	if c.Password.MinStrength < 0 || c.Password.MinStrength > 4 {
+		return fmt.Errorf("password.min_strength must be between 0 and 4")
+	}
+
+	if len(c.Security.Authenticators) == 0 {
+		return fmt.Errorf("security.authenticators must not be empty")
+	}
+	for i, name := range c.Security.Authenticators {
+		if slices.Contains(c.Security.Authenticators[:i], name) {
+			return fmt.Errorf("security.authenticators lists %s twice", name)
+		}
+		switch name {
+		case "local":
+		case "ldap":
+			if c.LDAP.URL == "" || c.LDAP.BaseDN == "" || c.LDAP.UserFilter == "" {
+				return fmt.Errorf("ldap requires url, base_dn and user_filter")
+			}
+		default:
+			return fmt.Errorf("unsupported security.authenticators entry: %s", name)
+		}
+	}
+
+	if c.SSO.Enabled && (c.SSO.Issuer == "" || c.SSO.ClientID == "" || c.SSO.RedirectURL == "") {
+		return fmt.Errorf("sso requires issuer, client_id and redirect_url")
+	}
+
+	if c.WebAuthn.Enabled {
+		if c.WebAuthn.RPID == "" || len(c.WebAuthn.RPOrigins) == 0 {
+			return fmt.Errorf("webauthn requires rp_id and rp_origins")
+		}
+		if c.WebAuthn.Timeout <= 0 {
+			return fmt.Errorf("webauthn.timeout must be positive")
+		}
+		switch c.WebAuthn.UserVerification {
+		case "required", "preferred", "discouraged":
+		default:
+			return fmt.Errorf("unsupported webauthn.user_verification: %s", c.WebAuthn.UserVerification)
+		}
+	}
+
+	if c.SCIM.Enabled {
+		if len(c.SCIM.Token) < 32 {
+			return fmt.Errorf("scim.token must be at least 32 characters")
+		}
+		if c.SCIM.Tenant == "" || c.SCIM.DefaultRole == "" {
+			return fmt.Errorf("scim requires tenant and default_role")
+		}
+		if c.SCIM.MaxResults <= 0 {
+			return fmt.Errorf("scim.max_results must be positive")
+		}
+	}
+
+	switch c.Mail.Driver {
+	case "log":
+	case "smtp":
+		if c.Mail.SMTP.Host == "" || c.Mail.SMTP.Port == 0 {
+			return fmt.Errorf("mail.smtp requires host and port")
+		}
+	default:
+		return fmt.Errorf("unsupported mail.driver: %s", c.Mail.Driver)
+	}
+	if c.Mail.From == "" || c.Mail.LinkBaseURL == "" {
+		return fmt.Errorf("mail.from and mail.link_base_url are required")
 	}
 
 	return nil
@@ -0,0 +1,234 @@
+// Package directory 通过 LDAP（含 Active Directory）验证用户名密码并读取用户信息
+package directory
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ErrInvalidCredentials 用户不存在、不唯一或密码错误
+var ErrInvalidCredentials = errors.New("directory: invalid credentials")
+
+// Config LDAP 连接与查询参数
+type Config struct {
+	URL               string        // ldap://host:389 或 ldaps://host:636
+	StartTLS          bool          // 在 ldap:// 连接上先执行 StartTLS 再绑定
+	TLS               *tls.Config   // 为空时按主机名校验系统信任的证书
+	Timeout           time.Duration // 建立连接及每次操作的时限
+	BindDN            string        // 搜索用户，为空时匿名搜索
+	BindPassword      string
+	BaseDN            string // 搜索用户的子树
+	UserFilter        string // %s 替换为转义后的用户名，如 (&(objectClass=person)(uid=%s))
+	UsernameAttribute string // 作为本地用户名的属性，如 uid、sAMAccountName
+	NameAttribute     string
+	EmailAttribute    string
+	GroupAttribute    string // 用户条目上列出所属组 DN 的属性，如 memberOf；为空时不读取
+	GroupBaseDN       string // 搜索组的子树，为空时使用 BaseDN
+	GroupFilter       string // %s 替换为转义后的用户 DN，如 (member=%s)；为空时不搜索组
+}
+
+// Entry 目录中的用户
+type Entry struct {
+	DN       string
+	Username string
+	Name     string
+	Email    string
+	Groups   []string // 所属组的 DN
+}
+
+// InGroup 用户是否属于 group；group 可以是完整 DN，也可以是组 DN 第一段的值（如 cn），均不区分大小写
+func (e *Entry) InGroup(group string) bool {
+	for _, dn := range e.Groups {
+		if strings.EqualFold(dn, group) {
+			return true
+		}
+		parsed, err := ldap.ParseDN(dn)
+		if err != nil || len(parsed.RDNs) == 0 {
+			continue
+		}
+		for _, attr := range parsed.RDNs[0].Attributes {
+			if strings.EqualFold(attr.Value, group) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Client LDAP 认证客户端
+// 每次认证使用一条新连接：先以搜索用户绑定并查找用户条目，再以用户 DN 和密码绑定。
+type Client struct {
+	cfg Config
+}
+
+// New 创建客户端，并检查 URL 与过滤器的格式
+func New(cfg Config) (*Client, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("directory: invalid url %q: %w", cfg.URL, err)
+	}
+	switch u.Scheme {
+	case "ldap":
+	case "ldaps":
+		if cfg.StartTLS {
+			return nil, fmt.Errorf("directory: start_tls cannot be used with ldaps://")
+		}
+	default:
+		return nil, fmt.Errorf("directory: unsupported url scheme %q", u.Scheme)
+	}
+	if cfg.BaseDN == "" {
+		return nil, fmt.Errorf("directory: base_dn is required")
+	}
+	if _, err := ldap.CompileFilter(fmt.Sprintf(cfg.UserFilter, "user")); err != nil || !strings.Contains(cfg.UserFilter, "%s") {
+		return nil, fmt.Errorf("directory: invalid user_filter %q", cfg.UserFilter)
+	}
+	if cfg.GroupFilter != "" {
+		if _, err := ldap.CompileFilter(fmt.Sprintf(cfg.GroupFilter, "cn=user")); err != nil || !strings.Contains(cfg.GroupFilter, "%s") {
+			return nil, fmt.Errorf("directory: invalid group_filter %q", cfg.GroupFilter)
+		}
+	}
+	if cfg.UsernameAttribute == "" {
+		cfg.UsernameAttribute = "uid"
+	}
+	if cfg.GroupBaseDN == "" {
+		cfg.GroupBaseDN = cfg.BaseDN
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.TLS == nil {
+		cfg.TLS = &tls.Config{}
+	}
+	if cfg.TLS.ServerName == "" {
+		cfg.TLS = cfg.TLS.Clone()
+		cfg.TLS.ServerName = u.Hostname()
+	}
+	return &Client{cfg: cfg}, nil
+}
+
+// Authenticate 验证用户名和密码，成功时返回用户条目
+// 凭据错误返回 ErrInvalidCredentials，其他错误表示目录不可用或配置有误。
+func (c *Client) Authenticate(ctx context.Context, username, password string) (*Entry, error) {
+	// 空密码的简单绑定在 LDAP 中是匿名绑定，会被服务器当作成功
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	// go-ldap 的操作不接收 context，取消时直接关闭连接
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if c.cfg.BindDN != "" {
+		if err := conn.Bind(c.cfg.BindDN, c.cfg.BindPassword); err != nil {
+			return nil, fmt.Errorf("directory: bind as search user: %w", err)
+		}
+	}
+
+	entry, err := c.findUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+	if c.cfg.GroupFilter != "" {
+		groups, err := c.findGroups(conn, entry.DN)
+		if err != nil {
+			return nil, err
+		}
+		entry.Groups = append(entry.Groups, groups...)
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("directory: bind as user: %w", err)
+	}
+	return entry, nil
+}
+
+// dial 建立连接，需要时升级为 TLS
+func (c *Client) dial() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(c.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: c.cfg.Timeout}),
+		ldap.DialWithTLSConfig(c.cfg.TLS),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("directory: dial %s: %w", c.cfg.URL, err)
+	}
+	conn.SetTimeout(c.cfg.Timeout)
+	if c.cfg.StartTLS {
+		if err := conn.StartTLS(c.cfg.TLS); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("directory: start tls: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// findUser 按过滤器查找唯一的用户条目
+func (c *Client) findUser(conn *ldap.Conn, username string) (*Entry, error) {
+	attributes := []string{c.cfg.UsernameAttribute}
+	for _, attr := range []string{c.cfg.NameAttribute, c.cfg.EmailAttribute, c.cfg.GroupAttribute} {
+		if attr != "" {
+			attributes = append(attributes, attr)
+		}
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		c.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		2, int(c.cfg.Timeout.Seconds()), false,
+		fmt.Sprintf(c.cfg.UserFilter, ldap.EscapeFilter(username)),
+		attributes, nil,
+	))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("directory: search user: %w", err)
+	}
+	// 不存在或匹配到多个条目都按凭据错误处理，不向调用方透露区别
+	if err != nil || len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+
+	e := result.Entries[0]
+	entry := &Entry{
+		DN:       e.DN,
+		Username: e.GetEqualFoldAttributeValue(c.cfg.UsernameAttribute),
+		Name:     e.GetEqualFoldAttributeValue(c.cfg.NameAttribute),
+		Email:    e.GetEqualFoldAttributeValue(c.cfg.EmailAttribute),
+	}
+	if entry.Username == "" {
+		entry.Username = username
+	}
+	if c.cfg.GroupAttribute != "" {
+		entry.Groups = e.GetEqualFoldAttributeValues(c.cfg.GroupAttribute)
+	}
+	return entry, nil
+}
+
+// findGroups 搜索包含该用户的组，用于不提供 memberOf 的服务器
+func (c *Client) findGroups(conn *ldap.Conn, userDN string) ([]string, error) {
+	result, err := conn.Search(ldap.NewSearchRequest(
+		c.cfg.GroupBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, int(c.cfg.Timeout.Seconds()), false,
+		fmt.Sprintf(c.cfg.GroupFilter, ldap.EscapeFilter(userDN)),
+		[]string{"1.1"}, nil, // 只需要 DN
+	))
+	if err != nil {
+		return nil, fmt.Errorf("directory: search groups: %w", err)
+	}
+	groups := make([]string, len(result.Entries))
+	for i, e := range result.Entries {
+		groups[i] = e.DN
+	}
+	return groups, nil
+}
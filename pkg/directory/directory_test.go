@@ -0,0 +1,141 @@
+package directory_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"slices"
+	"testing"
+
+	"go-ddd-scaffold/pkg/directory"
+	"go-ddd-scaffold/pkg/directory/directorytest"
+)
+
+func startDirectory(t *testing.T) *directorytest.Server {
+	t.Helper()
+	srv, err := directorytest.Start(directorytest.Options{
+		Users: []directorytest.User{
+			{Username: "alice", Password: "alice-secret", Name: "Alice Liddell", Email: "alice@example.org", Groups: []string{"admins", "staff"}},
+			{Username: "bob", Password: "bob-secret"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newClient(t *testing.T, srv *directorytest.Server, modify func(cfg *directory.Config)) *directory.Client {
+	t.Helper()
+	cfg := directory.Config{
+		URL:               srv.URL,
+		BindDN:            srv.BindDN,
+		BindPassword:      srv.BindPassword,
+		BaseDN:            srv.BaseDN,
+		UserFilter:        "(&(objectClass=person)(uid=%s))",
+		UsernameAttribute: "uid",
+		NameAttribute:     "cn",
+		EmailAttribute:    "mail",
+		GroupAttribute:    "memberOf",
+	}
+	if modify != nil {
+		modify(&cfg)
+	}
+	c, err := directory.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestAuthenticate(t *testing.T) {
+	srv := startDirectory(t)
+	c := newClient(t, srv, nil)
+
+	entry, err := c.Authenticate(context.Background(), "alice", "alice-secret")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if entry.DN != "uid=alice,ou=people,"+srv.BaseDN || entry.Username != "alice" ||
+		entry.Name != "Alice Liddell" || entry.Email != "alice@example.org" {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if !entry.InGroup("admins") || !entry.InGroup("cn=staff,ou=groups,"+srv.BaseDN) || entry.InGroup("ops") {
+		t.Fatalf("groups = %v", entry.Groups)
+	}
+}
+
+func TestAuthenticateGroupSearch(t *testing.T) {
+	srv := startDirectory(t)
+	c := newClient(t, srv, func(cfg *directory.Config) {
+		cfg.GroupAttribute = ""
+		cfg.GroupBaseDN = "ou=groups," + srv.BaseDN
+		cfg.GroupFilter = "(&(objectClass=groupOfNames)(member=%s))"
+	})
+
+	entry, err := c.Authenticate(context.Background(), "alice", "alice-secret")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	slices.Sort(entry.Groups)
+	want := []string{"cn=admins,ou=groups," + srv.BaseDN, "cn=staff,ou=groups," + srv.BaseDN}
+	if !slices.Equal(entry.Groups, want) {
+		t.Fatalf("groups = %v, want %v", entry.Groups, want)
+	}
+}
+
+func TestAuthenticateStartTLS(t *testing.T) {
+	srv := startDirectory(t)
+	c := newClient(t, srv, func(cfg *directory.Config) {
+		cfg.StartTLS = true
+		cfg.TLS = &tls.Config{RootCAs: srv.CertPool()}
+	})
+
+	if _, err := c.Authenticate(context.Background(), "bob", "bob-secret"); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+}
+
+func TestAuthenticateRejectsInvalidCredentials(t *testing.T) {
+	srv := startDirectory(t)
+	c := newClient(t, srv, nil)
+
+	for name, tc := range map[string]struct{ username, password string }{
+		"wrong password": {"alice", "bob-secret"},
+		"unknown user":   {"carol", "alice-secret"},
+		// 空密码的绑定会被服务器当作匿名绑定而成功
+		"empty password":   {"alice", ""},
+		"empty username":   {"", "alice-secret"},
+		"filter injection": {"*", "alice-secret"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := c.Authenticate(context.Background(), tc.username, tc.password)
+			if !errors.Is(err, directory.ErrInvalidCredentials) {
+				t.Fatalf("error = %v, want ErrInvalidCredentials", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateDirectoryDown(t *testing.T) {
+	srv := startDirectory(t)
+	c := newClient(t, srv, nil)
+	srv.Close()
+
+	_, err := c.Authenticate(context.Background(), "alice", "alice-secret")
+	if err == nil || errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Fatalf("error = %v, want an unavailable directory", err)
+	}
+}
+
+func TestAuthenticateWrongSearchCredentials(t *testing.T) {
+	srv := startDirectory(t)
+	c := newClient(t, srv, func(cfg *directory.Config) { cfg.BindPassword = "wrong" })
+
+	// 搜索用户配置错误不是用户的凭据错误
+	_, err := c.Authenticate(context.Background(), "alice", "alice-secret")
+	if err == nil || errors.Is(err, directory.ErrInvalidCredentials) {
+		t.Fatalf("error = %v, want a bind error", err)
+	}
+}
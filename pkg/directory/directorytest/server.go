@@ -0,0 +1,642 @@
+// Package directorytest 提供内存中的 LDAP 服务器，用于在进程内开发和测试 directory 客户端，
+// 用法类似 net/http/httptest。
+//
+// 只实现认证所需的子集：简单绑定、搜索（常用过滤器）和 StartTLS，不支持写操作。
+// 目录结构为：
+//
+//	<base>
+//	ou=people,<base>   用户 uid=<用户名>，objectClass inetOrgPerson，带 memberOf
+//	ou=groups,<base>   组 cn=<组名>，objectClass groupOfNames，带 member
+package directorytest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// startTLSOID StartTLS 扩展操作（RFC 4511 4.14）
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// User 目录中的用户
+type User struct {
+	Username string
+	Password string
+	Name     string   // cn，为空时使用用户名
+	Email    string   // mail
+	Groups   []string // 组名，组不存在时自动创建
+}
+
+// Options 服务器参数
+type Options struct {
+	Addr            string // 监听地址，为空时使用 127.0.0.1 上的随机端口
+	BaseDN          string // 为空时使用 dc=example,dc=org
+	BindDN          string // 搜索用户，为空时使用 cn=admin,<BaseDN>
+	BindPassword    string // 为空时使用 admin
+	LDAPS           bool   // 使用隐式 TLS（ldaps://）而不是明文加 StartTLS
+	AnonymousSearch bool   // 允许未绑定的连接搜索
+	Users           []User
+	Logger          *log.Logger // 记录每个请求，为空时不记录
+}
+
+// Server 运行中的 LDAP 服务器
+type Server struct {
+	URL          string // ldap://127.0.0.1:port 或 ldaps://...
+	BaseDN       string
+	BindDN       string
+	BindPassword string
+
+	opts     Options
+	listener net.Listener
+	tls      *tls.Config
+	cert     *x509.Certificate
+
+	mu        sync.Mutex
+	entries   []*entry
+	passwords map[string]string // 规范化的 DN → 密码
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// entry 一个目录条目
+type entry struct {
+	dn    string
+	attrs []attribute
+}
+
+type attribute struct {
+	name   string
+	values []string
+}
+
+// Start 启动服务器；使用完毕后调用 Close
+func Start(opts Options) (*Server, error) {
+	if opts.Addr == "" {
+		opts.Addr = "127.0.0.1:0"
+	}
+	if opts.BaseDN == "" {
+		opts.BaseDN = "dc=example,dc=org"
+	}
+	if opts.BindDN == "" {
+		opts.BindDN = "cn=admin," + opts.BaseDN
+	}
+	if opts.BindPassword == "" {
+		opts.BindPassword = "admin"
+	}
+
+	cert, tlsConfig, err := selfSigned()
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+	scheme := "ldap"
+	if opts.LDAPS {
+		ln = tls.NewListener(ln, tlsConfig)
+		scheme = "ldaps"
+	}
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	s := &Server{
+		URL:          fmt.Sprintf("%s://localhost:%s", scheme, port),
+		BaseDN:       opts.BaseDN,
+		BindDN:       opts.BindDN,
+		BindPassword: opts.BindPassword,
+		opts:         opts,
+		listener:     ln,
+		tls:          tlsConfig,
+		cert:         cert,
+		passwords:    map[string]string{normalizeDN(opts.BindDN): opts.BindPassword},
+		conns:        make(map[net.Conn]struct{}),
+	}
+	base := newEntry(opts.BaseDN, "objectClass", "top", "objectClass", "domain")
+	s.entries = append(s.entries,
+		base,
+		newEntry("ou=people,"+opts.BaseDN, "objectClass", "organizationalUnit", "ou", "people"),
+		newEntry("ou=groups,"+opts.BaseDN, "objectClass", "organizationalUnit", "ou", "groups"),
+	)
+	for _, u := range opts.Users {
+		s.AddUser(u)
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Close 停止监听并断开所有连接
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+// CertPool 信任服务器自签名证书的证书池，用于校验 StartTLS 和 ldaps 连接
+func (s *Server) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.cert)
+	return pool
+}
+
+// AddUser 添加或替换用户，并把用户加入其所属的组
+func (s *Server) AddUser(u User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dn := fmt.Sprintf("uid=%s,ou=people,%s", ldap.EscapeDN(u.Username), s.BaseDN)
+	s.removeLocked(dn)
+	name := u.Name
+	if name == "" {
+		name = u.Username
+	}
+	e := newEntry(dn,
+		"objectClass", "top", "objectClass", "person", "objectClass", "organizationalPerson", "objectClass", "inetOrgPerson",
+		"uid", u.Username, "cn", name, "sn", name,
+	)
+	if u.Email != "" {
+		e.add("mail", u.Email)
+	}
+	for _, group := range u.Groups {
+		groupDN := fmt.Sprintf("cn=%s,ou=groups,%s", ldap.EscapeDN(group), s.BaseDN)
+		g := s.findLocked(groupDN)
+		if g == nil {
+			g = newEntry(groupDN, "objectClass", "top", "objectClass", "groupOfNames", "cn", group)
+			s.entries = append(s.entries, g)
+		}
+		g.add("member", dn)
+		e.add("memberOf", groupDN)
+	}
+	s.entries = append(s.entries, e)
+	s.passwords[normalizeDN(dn)] = u.Password
+}
+
+// RemoveUser 删除用户及其组成员关系
+func (s *Server) RemoveUser(username string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(fmt.Sprintf("uid=%s,ou=people,%s", ldap.EscapeDN(username), s.BaseDN))
+}
+
+func (s *Server) removeLocked(dn string) {
+	key := normalizeDN(dn)
+	delete(s.passwords, key)
+	s.entries = slices.DeleteFunc(s.entries, func(e *entry) bool { return normalizeDN(e.dn) == key })
+	for _, e := range s.entries {
+		for i := range e.attrs {
+			if strings.EqualFold(e.attrs[i].name, "member") {
+				e.attrs[i].values = slices.DeleteFunc(e.attrs[i].values, func(v string) bool { return normalizeDN(v) == key })
+			}
+		}
+	}
+}
+
+func (s *Server) findLocked(dn string) *entry {
+	key := normalizeDN(dn)
+	for _, e := range s.entries {
+		if normalizeDN(e.dn) == key {
+			return e
+		}
+	}
+	return nil
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// session 一条连接的状态
+type session struct {
+	conn  net.Conn
+	bound string // 已绑定的 DN，为空表示匿名
+	tls   bool
+}
+
+func (s *Server) handle(conn net.Conn) {
+	sess := &session{conn: conn, tls: s.opts.LDAPS}
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, sess.conn)
+		s.mu.Unlock()
+		_ = sess.conn.Close()
+	}()
+
+	for {
+		packet, err := ber.ReadPacket(sess.conn)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				s.logf("read: %v", err)
+			}
+			return
+		}
+		if len(packet.Children) < 2 {
+			s.logf("malformed message")
+			return
+		}
+		id, _ := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		if op.ClassType != ber.ClassApplication {
+			s.logf("malformed message")
+			return
+		}
+
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			s.bind(sess, id, op)
+		case ldap.ApplicationUnbindRequest:
+			return
+		case ldap.ApplicationSearchRequest:
+			s.search(sess, id, op)
+		case ldap.ApplicationExtendedRequest:
+			if !s.extended(sess, id, op) {
+				return
+			}
+		case ldap.ApplicationAbandonRequest:
+		default:
+			// 写操作的响应标签都是请求标签加一
+			s.write(sess, id, result(op.Tag+1, ldap.LDAPResultUnwillingToPerform, "the test server is read-only"))
+		}
+	}
+}
+
+// bind 处理简单绑定
+func (s *Server) bind(sess *session, id int64, op *ber.Packet) {
+	if len(op.Children) < 3 {
+		s.write(sess, id, result(ldap.ApplicationBindResponse, ldap.LDAPResultProtocolError, "malformed bind request"))
+		return
+	}
+	name := op.Children[1].Data.String()
+	auth := op.Children[2]
+	if auth.ClassType != ber.ClassContext || auth.Tag != 0 {
+		s.write(sess, id, result(ldap.ApplicationBindResponse, ldap.LDAPResultAuthMethodNotSupported, "only simple bind is supported"))
+		return
+	}
+	pwd := auth.Data.String()
+	sess.bound = ""
+
+	switch {
+	case name == "" && pwd == "":
+		s.logf("bind anonymous")
+		s.write(sess, id, result(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, ""))
+		return
+	case pwd == "":
+		// RFC 4513 5.1.2：未认证绑定默认拒绝
+		s.logf("bind %q: unauthenticated bind refused", name)
+		s.write(sess, id, result(ldap.ApplicationBindResponse, ldap.LDAPResultUnwillingToPerform, "unauthenticated bind is not allowed"))
+		return
+	}
+
+	s.mu.Lock()
+	want, ok := s.passwords[normalizeDN(name)]
+	s.mu.Unlock()
+	if !ok || want != pwd {
+		s.logf("bind %q: invalid credentials", name)
+		s.write(sess, id, result(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials, ""))
+		return
+	}
+	sess.bound = name
+	s.logf("bind %q: ok", name)
+	s.write(sess, id, result(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, ""))
+}
+
+// search 处理搜索请求
+func (s *Server) search(sess *session, id int64, op *ber.Packet) {
+	if len(op.Children) < 8 {
+		s.write(sess, id, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, "malformed search request"))
+		return
+	}
+	if sess.bound == "" && !s.opts.AnonymousSearch {
+		s.write(sess, id, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights, "bind first"))
+		return
+	}
+	baseDN := op.Children[0].Data.String()
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+	var wanted []string
+	for _, a := range op.Children[7].Children {
+		wanted = append(wanted, a.Data.String())
+	}
+	filterText, _ := ldap.DecompileFilter(filter)
+
+	s.mu.Lock()
+	base := s.findLocked(baseDN)
+	var matches []*entry
+	if base != nil {
+		for _, e := range s.entries {
+			if inScope(e.dn, base.dn, scope) && matchFilter(e, filter) {
+				matches = append(matches, e)
+			}
+		}
+	}
+	s.mu.Unlock()
+	s.logf("search base=%q scope=%d filter=%s: %d entries", baseDN, scope, filterText, len(matches))
+
+	if base == nil {
+		s.write(sess, id, result(ldap.ApplicationSearchResultDone, ldap.LDAPResultNoSuchObject, ""))
+		return
+	}
+	code := ldap.LDAPResultSuccess
+	if sizeLimit > 0 && int64(len(matches)) > sizeLimit {
+		matches, code = matches[:sizeLimit], ldap.LDAPResultSizeLimitExceeded
+	}
+	for _, e := range matches {
+		s.write(sess, id, e.encode(wanted))
+	}
+	s.write(sess, id, result(ldap.ApplicationSearchResultDone, uint16(code), ""))
+}
+
+// extended 处理扩展操作，只支持 StartTLS；返回 false 时关闭连接
+func (s *Server) extended(sess *session, id int64, op *ber.Packet) bool {
+	name := ""
+	if len(op.Children) > 0 {
+		name = op.Children[0].Data.String()
+	}
+	if name != startTLSOID {
+		s.write(sess, id, extendedResult(ldap.LDAPResultProtocolError, "unsupported extended operation", ""))
+		return true
+	}
+	if sess.tls {
+		s.write(sess, id, extendedResult(ldap.LDAPResultOperationsError, "TLS is already established", startTLSOID))
+		return true
+	}
+	s.write(sess, id, extendedResult(ldap.LDAPResultSuccess, "", startTLSOID))
+
+	tlsConn := tls.Server(sess.conn, s.tls)
+	if err := tlsConn.Handshake(); err != nil {
+		s.logf("start tls: %v", err)
+		return false
+	}
+	s.mu.Lock()
+	delete(s.conns, sess.conn)
+	s.conns[tlsConn] = struct{}{}
+	s.mu.Unlock()
+	sess.conn, sess.tls = tlsConn, true
+	s.logf("start tls: ok")
+	return true
+}
+
+func (s *Server) write(sess *session, id int64, op *ber.Packet) {
+	msg := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+	msg.AppendChild(op)
+	if _, err := sess.conn.Write(msg.Bytes()); err != nil {
+		s.logf("write: %v", err)
+	}
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.opts.Logger != nil {
+		s.opts.Logger.Printf(format, args...)
+	}
+}
+
+// result 编码 LDAPResult
+func result(tag ber.Tag, code uint16, message string) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, ldap.ApplicationMap[uint8(tag)])
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Diagnostic Message"))
+	return op
+}
+
+func extendedResult(code uint16, message, name string) *ber.Packet {
+	op := result(ldap.ApplicationExtendedResponse, code, message)
+	if name != "" {
+		op.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, 10, name, "Response Name"))
+	}
+	return op
+}
+
+func newEntry(dn string, pairs ...string) *entry {
+	e := &entry{dn: dn}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		e.add(pairs[i], pairs[i+1])
+	}
+	return e
+}
+
+func (e *entry) add(name, value string) {
+	for i := range e.attrs {
+		if strings.EqualFold(e.attrs[i].name, name) {
+			if !slices.Contains(e.attrs[i].values, value) {
+				e.attrs[i].values = append(e.attrs[i].values, value)
+			}
+			return
+		}
+	}
+	e.attrs = append(e.attrs, attribute{name: name, values: []string{value}})
+}
+
+func (e *entry) get(name string) []string {
+	for _, a := range e.attrs {
+		if strings.EqualFold(a.name, name) {
+			return a.values
+		}
+	}
+	return nil
+}
+
+// encode 编码 SearchResultEntry；wanted 为空或含 * 时返回全部属性，1.1 表示不返回属性
+func (e *entry) encode(wanted []string) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "Object Name"))
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	all := len(wanted) == 0 || slices.Contains(wanted, "*")
+	for _, a := range e.attrs {
+		if !all && !slices.ContainsFunc(wanted, func(w string) bool { return strings.EqualFold(w, a.name) }) {
+			continue
+		}
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, a.name, "Type"))
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range a.values {
+			values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+		}
+		attr.AppendChild(values)
+		attrs.AppendChild(attr)
+	}
+	op.AppendChild(attrs)
+	return op
+}
+
+// matchFilter 对条目求值 RFC 4511 过滤器；比较均不区分大小写，不支持可扩展匹配
+func matchFilter(e *entry, f *ber.Packet) bool {
+	if f.ClassType != ber.ClassContext {
+		return false
+	}
+	switch f.Tag {
+	case ldap.FilterAnd:
+		for _, child := range f.Children {
+			if !matchFilter(e, child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range f.Children {
+			if matchFilter(e, child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return len(f.Children) == 1 && !matchFilter(e, f.Children[0])
+	case ldap.FilterPresent:
+		return len(e.get(f.Data.String())) > 0
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch, ldap.FilterGreaterOrEqual, ldap.FilterLessOrEqual:
+		if len(f.Children) != 2 {
+			return false
+		}
+		name, want := f.Children[0].Data.String(), strings.ToLower(f.Children[1].Data.String())
+		return slices.ContainsFunc(e.get(name), func(v string) bool {
+			v = strings.ToLower(v)
+			switch f.Tag {
+			case ldap.FilterGreaterOrEqual:
+				return v >= want
+			case ldap.FilterLessOrEqual:
+				return v <= want
+			default:
+				return v == want || normalizeDN(v) == normalizeDN(want)
+			}
+		})
+	case ldap.FilterSubstrings:
+		if len(f.Children) != 2 {
+			return false
+		}
+		return slices.ContainsFunc(e.get(f.Children[0].Data.String()), func(v string) bool {
+			return matchSubstrings(strings.ToLower(v), f.Children[1].Children)
+		})
+	default:
+		return false
+	}
+}
+
+func matchSubstrings(v string, parts []*ber.Packet) bool {
+	for _, p := range parts {
+		s := strings.ToLower(p.Data.String())
+		switch p.Tag {
+		case ldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(v, s) {
+				return false
+			}
+			v = v[len(s):]
+		case ldap.FilterSubstringsAny:
+			i := strings.Index(v, s)
+			if i < 0 {
+				return false
+			}
+			v = v[i+len(s):]
+		case ldap.FilterSubstringsFinal:
+			if !strings.HasSuffix(v, s) {
+				return false
+			}
+			v = ""
+		}
+	}
+	return true
+}
+
+// inScope 条目是否在搜索范围内：0 仅基准条目，1 直接子条目，2 整个子树
+func inScope(dn, base string, scope int64) bool {
+	d, err := ldap.ParseDN(dn)
+	if err != nil {
+		return false
+	}
+	b, err := ldap.ParseDN(base)
+	if err != nil {
+		return false
+	}
+	switch scope {
+	case ldap.ScopeBaseObject:
+		return d.EqualFold(b)
+	case ldap.ScopeSingleLevel:
+		return len(d.RDNs) == len(b.RDNs)+1 && b.AncestorOfFold(d)
+	default:
+		return d.EqualFold(b) || b.AncestorOfFold(d)
+	}
+}
+
+// normalizeDN 返回用于比较的 DN；无法解析时原样转为小写
+func normalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(dn)
+	}
+	return strings.ToLower(parsed.String())
+}
+
+// selfSigned 为 localhost 和 127.0.0.1 生成自签名证书
+func selfSigned() (*x509.Certificate, *tls.Config, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "directorytest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
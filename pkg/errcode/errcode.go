@@ -6,6 +6,7 @@ import "net/http"
 type Error struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"` // 供客户端处理的结构化详情
 }
 
 func (e *Error) Error() string { return e.Message }
@@ -20,6 +21,11 @@ func (e *Error) WithMessage(msg string) *Error {
 	return &Error{Code: e.Code, Message: msg}
 }
 
+// WithData 创建带结构化详情的同 Code 错误，详情随响应的 data 字段返回
+func (e *Error) WithData(data any) *Error {
+	return &Error{Code: e.Code, Message: e.Message, Data: data}
+}
+
 // GetHTTPStatus 根据错误码自动映射 HTTP 状态码
 func (e *Error) GetHTTPStatus() int {
 	switch {
@@ -33,6 +39,8 @@ func (e *Error) GetHTTPStatus() int {
 		return http.StatusForbidden
 	case e.Code >= 40001 && e.Code <= 40999:
 		return http.StatusBadRequest
+	case e.Code == ErrServerBusy.Code, e.Code == ErrDirectoryUnavailable.Code:
+		return http.StatusServiceUnavailable
 	case e.Code >= 50001 && e.Code <= 50999:
 		return http.StatusInternalServerError
 	default:
@@ -45,22 +53,61 @@ var (
 	// 认证相关 (10xxx → 401)
 	ErrInvalidToken      = New(10001, "无效的Token")
 	ErrTokenExpired      = New(10002, "Token已过期")
+	ErrTokenRevoked      = New(10003, "Token已被吊销")
 	ErrInvalidCredential = New(10004, "用户名或密码错误")
 	ErrAccountDisabled   = New(10005, "账号已被禁用")
 	ErrAccountLocked     = New(10008, "账号已被锁定")
+	ErrSSOFailed         = New(10009, "单点登录失败")
+	ErrCaptchaRequired   = New(10010, "请输入验证码")
+	ErrCaptchaInvalid    = New(10011, "验证码错误或已过期")
+	ErrPasskeyFailed     = New(10012, "通行密钥验证失败")
 
 	// 资源相关 (20xxx → 400)
 	ErrAccountNotFound  = New(20001, "账号不存在")
 	ErrAccountExists    = New(20002, "账号已存在")
-	ErrPasswordTooShort = New(20005, "密码不符合策略要求")
+	ErrRoleNotFound     = New(20003, "角色不存在")
+	ErrRoleExists       = New(20004, "角色已存在")
+	ErrPasswordPolicy   = New(20005, "密码不符合策略要求")
+	ErrRoleInUse        = New(20006, "角色仍被用户使用")
+	ErrPasswordReused   = New(20007, "不能使用最近用过的密码")
+	ErrWrongPassword    = New(20008, "当前密码错误")
+	ErrInvalidOTP       = New(20009, "动态验证码错误")
+	ErrMFANotEnabled    = New(20010, "未启用两步验证")
+	ErrMFAEnabled       = New(20011, "已启用两步验证")
+	ErrAPIKeyNotFound   = New(20012, "API Key不存在")
+	ErrSessionNotFound  = New(20013, "会话不存在")
+	ErrTenantNotFound   = New(20014, "租户不存在")
+	ErrTenantExists     = New(20015, "租户已存在")
+	ErrTenantRequired   = New(20016, "请指定租户")
+	ErrInvalidLink      = New(20017, "链接无效或已过期")
+	ErrEmailVerified    = New(20018, "邮箱已验证")
+	ErrEmailRequired    = New(20019, "请先设置邮箱")
+	ErrTooFrequent      = New(20020, "操作过于频繁，请稍后再试")
+	ErrNotImpersonating = New(20021, "当前未处于模拟登录状态")
+	ErrPasskeyNotFound  = New(20022, "通行密钥不存在")
+	ErrPasskeyInvalid   = New(20023, "通行密钥注册失败")
+	ErrPasswordTooLong  = New(20024, "密码不能超过 72 字节")
 
 	// 权限相关 (30xxx → 403)
-	ErrPermissionDenied = New(30001, "没有操作权限")
+	ErrPermissionDenied        = New(30001, "没有操作权限")
+	ErrPasswordChangeRequired  = New(30002, "请先修改密码")
+	ErrMFAEnrollRequired       = New(30003, "请先启用两步验证")
+	ErrMFARequiredByRole       = New(30004, "当前角色要求启用两步验证")
+	ErrAPIKeyNotAllowed        = New(30005, "该接口不支持API Key访问")
+	ErrScopeNotGranted         = New(30006, "API Key权限范围超出角色权限")
+	ErrSSODisabled             = New(30007, "未启用单点登录")
+	ErrSuperAdminRequired      = New(30008, "需要超级管理员权限")
+	ErrImpersonationDenied     = New(30009, "不能模拟登录该用户")
+	ErrImpersonationNotAllowed = New(30010, "模拟登录期间不允许此操作")
+	ErrPasskeyDisabled         = New(30011, "未启用通行密钥登录")
+	ErrSCIMDisabled            = New(30012, "未启用 SCIM 用户同步")
 
 	// 参数相关 (40xxx → 400)
 	ErrInvalidParams = New(40001, "请求参数错误")
 
 	// 系统相关 (50xxx → 500)
-	ErrInternalServer = New(50001, "服务器内部错误")
-	ErrDatabaseError  = New(50002, "数据库操作失败")
+	ErrInternalServer       = New(50001, "服务器内部错误")
+	ErrDatabaseError        = New(50002, "数据库操作失败")
+	ErrServerBusy           = New(50003, "服务繁忙，请稍后重试") // → 503
+	ErrDirectoryUnavailable = New(50004, "目录服务不可用")    // LDAP 认证源，→ 503
 )
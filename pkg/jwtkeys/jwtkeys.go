@@ -0,0 +1,297 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key 一把签名/验签密钥
+// Private 为 nil 时只能验签（轮换期间保留的旧公钥）。
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.PrivateKey // HMAC 时为 []byte
+	Public  crypto.PublicKey  // HMAC 时为 []byte
+}
+
+// IsSymmetric 是否为 HMAC 密钥（不会发布到 JWKS）
+func (k *Key) IsSymmetric() bool {
+	_, ok := k.Method.(*jwt.SigningMethodHMAC)
+	return ok
+}
+
+// NewHMAC 创建 HS256 密钥，kid 取密钥 SHA-256 的前 16 位十六进制
+func NewHMAC(secret string) *Key {
+	sum := sha256.Sum256([]byte(secret))
+	return &Key{
+		ID:      hex.EncodeToString(sum[:8]),
+		Method:  jwt.SigningMethodHS256,
+		Private: []byte(secret),
+		Public:  []byte(secret),
+	}
+}
+
+// LoadPEM 从 PEM 文件加载密钥
+// 提供私钥文件时可签名，只提供公钥文件时仅用于验签；
+// 算法由密钥类型决定：RSA → RS256，P-256 → ES256，Ed25519 → EdDSA。
+// id 为空时使用 RFC 7638 JWK 指纹。
+func LoadPEM(id, privateKeyFile, publicKeyFile string) (*Key, error) {
+	var priv crypto.PrivateKey
+	var pub crypto.PublicKey
+	switch {
+	case privateKeyFile != "":
+		block, err := readPEM(privateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if priv, err = parsePrivateKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("%s: %w", privateKeyFile, err)
+		}
+		signer, ok := priv.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported private key type %T", privateKeyFile, priv)
+		}
+		pub = signer.Public()
+	case publicKeyFile != "":
+		block, err := readPEM(publicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if pub, err = parsePublicKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("%s: %w", publicKeyFile, err)
+		}
+	default:
+		return nil, errors.New("private_key_file or public_key_file is required")
+	}
+
+	method, err := methodFor(pub)
+	if err != nil {
+		return nil, err
+	}
+	key := &Key{ID: id, Method: method, Private: priv, Public: pub}
+	if key.ID == "" {
+		if key.ID, err = Thumbprint(pub); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// KeySet 当前签名密钥与全部验签密钥
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	order   []*Key
+}
+
+// NewKeySet 创建密钥集，第一把密钥用于签名，全部密钥都可验签
+func NewKeySet(keys ...*Key) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+	if keys[0].Private == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", keys[0].ID)
+	}
+	set := &KeySet{signing: keys[0], keys: make(map[string]*Key, len(keys)), order: keys}
+	for _, k := range keys {
+		if _, dup := set.keys[k.ID]; dup {
+			return nil, fmt.Errorf("duplicate key id %q", k.ID)
+		}
+		set.keys[k.ID] = k
+	}
+	return set, nil
+}
+
+// Signing 返回当前签名密钥
+func (s *KeySet) Signing() *Key {
+	return s.signing
+}
+
+// Lookup 按 kid 查找验签密钥
+func (s *KeySet) Lookup(kid string) (*Key, bool) {
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+// Algorithms 返回密钥集使用的全部算法，用于限制可接受的 alg
+func (s *KeySet) Algorithms() []string {
+	seen := make(map[string]bool)
+	var algs []string
+	for _, k := range s.order {
+		if alg := k.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+// JWK RFC 7517 JSON Web Key（仅公钥字段）
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS RFC 7517 JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS 返回全部非对称公钥，HMAC 密钥不会发布
+func (s *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, k := range s.order {
+		if k.IsSymmetric() {
+			continue
+		}
+		jwk, err := toJWK(k.Public)
+		if err != nil {
+			continue
+		}
+		jwk.Use = "sig"
+		jwk.Alg = k.Method.Alg()
+		jwk.Kid = k.ID
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// Thumbprint 计算公钥的 RFC 7638 JWK 指纹（SHA-256，base64url）
+func Thumbprint(pub crypto.PublicKey) (string, error) {
+	jwk, err := toJWK(pub)
+	if err != nil {
+		return "", err
+	}
+	// 必需成员按字典序排列
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func toJWK(pub crypto.PublicKey) (JWK, error) {
+	enc := base64.RawURLEncoding
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return JWK{Kty: "RSA", N: enc.EncodeToString(k.N.Bytes()), E: enc.EncodeToString(big.NewInt(int64(k.E)).Bytes())}, nil
+	case *ecdsa.PublicKey:
+		ecdh, err := k.ECDH()
+		if err != nil {
+			return JWK{}, err
+		}
+		// 未压缩点格式：0x04 || X || Y
+		point := ecdh.Bytes()
+		size := (len(point) - 1) / 2
+		return JWK{Kty: "EC", Crv: k.Curve.Params().Name, X: enc.EncodeToString(point[1 : 1+size]), Y: enc.EncodeToString(point[1+size:])}, nil
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: enc.EncodeToString(k)}, nil
+	default:
+		return JWK{}, fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+func methodFor(pub crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("rsa key must be at least 2048 bits, got %d", k.N.BitLen())
+		}
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ecdsa key must use P-256 for ES256, got %s", k.Curve.Params().Name)
+		}
+		return jwt.SigningMethodES256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", pub)
+	}
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("%s: no PEM key block found", path)
+		}
+		// openssl ecparam -genkey 会在私钥前输出曲线参数块
+		if block.Type != "EC PARAMETERS" {
+			return block, nil
+		}
+	}
+}
+
+func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
+	if k, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParseECPrivateKey(der); err == nil {
+		return k, nil
+	}
+	return nil, errors.New("unsupported private key format (want PKCS#8, PKCS#1 or SEC 1)")
+}
+
+func parsePublicKey(der []byte) (crypto.PublicKey, error) {
+	if k, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return k, nil
+	}
+	if k, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return k, nil
+	}
+	return nil, errors.New("unsupported public key format (want PKIX or PKCS#1)")
+}
@@ -0,0 +1,220 @@
+package jwtkeys_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go-ddd-scaffold/pkg/jwtkeys"
+)
+
+var (
+	rsaKey, _   = rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _    = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ = ed25519.GenerateKey(rand.Reader)
+	smallRSA, _ = rsa.GenerateKey(rand.Reader, 1024)
+	p384Key, _  = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+)
+
+// writePEM 把 DER 写成 PEM 文件，blocks 为 (类型, DER) 对
+func writePEM(t *testing.T, name string, blocks ...any) string {
+	t.Helper()
+	var data []byte
+	for i := 0; i < len(blocks); i += 2 {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: blocks[i].(string), Bytes: blocks[i+1].([]byte)})...)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writePKCS8 写出 PKCS#8 私钥和 PKIX 公钥文件
+func writePKCS8(t *testing.T, priv crypto.Signer) (privateFile, publicFile string) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writePEM(t, "key.pem", "PRIVATE KEY", der), writePEM(t, "pub.pem", "PUBLIC KEY", pubDER)
+}
+
+func TestLoadPEM(t *testing.T) {
+	for name, tc := range map[string]struct {
+		key crypto.Signer
+		alg string
+	}{
+		"rsa":     {rsaKey, "RS256"},
+		"p-256":   {ecKey, "ES256"},
+		"ed25519": {edKey, "EdDSA"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			privateFile, publicFile := writePKCS8(t, tc.key)
+			thumbprint, err := jwtkeys.Thumbprint(tc.key.Public())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			signing, err := jwtkeys.LoadPEM("", privateFile, "")
+			if err != nil {
+				t.Fatalf("LoadPEM private: %v", err)
+			}
+			if signing.Method.Alg() != tc.alg || signing.ID != thumbprint || signing.Private == nil {
+				t.Fatalf("signing key = %s %s private=%v, want %s %s", signing.Method.Alg(), signing.ID, signing.Private != nil, tc.alg, thumbprint)
+			}
+
+			// 只有公钥的旧密钥只能验签，kid 与私钥加载时一致
+			retired, err := jwtkeys.LoadPEM("", "", publicFile)
+			if err != nil {
+				t.Fatalf("LoadPEM public: %v", err)
+			}
+			if retired.Method.Alg() != tc.alg || retired.ID != thumbprint || retired.Private != nil {
+				t.Fatalf("verification key = %s %s private=%v", retired.Method.Alg(), retired.ID, retired.Private != nil)
+			}
+
+			named, err := jwtkeys.LoadPEM("2026-10", privateFile, "")
+			if err != nil || named.ID != "2026-10" {
+				t.Fatalf("LoadPEM with id = %v, %v", named, err)
+			}
+		})
+	}
+}
+
+func TestLoadPEMLegacyFormats(t *testing.T) {
+	// PKCS#1 RSA 私钥与公钥
+	rsaPrivate := writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	rsaPublic := writePEM(t, "rsa.pub", "RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey))
+	// openssl ecparam -genkey 输出的 SEC 1 私钥，前面带曲线参数块
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPrivate := writePEM(t, "ec.pem", "EC PARAMETERS", []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}, "EC PRIVATE KEY", sec1)
+
+	for name, files := range map[string][2]string{
+		"pkcs1 private": {rsaPrivate, ""},
+		"pkcs1 public":  {"", rsaPublic},
+		"sec1 private":  {ecPrivate, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := jwtkeys.LoadPEM("", files[0], files[1]); err != nil {
+				t.Fatalf("LoadPEM: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadPEMRejects(t *testing.T) {
+	small, _ := writePKCS8(t, smallRSA)
+	p384, _ := writePKCS8(t, p384Key)
+	for name, file := range map[string]string{
+		"rsa under 2048 bits": small,
+		"p-384":               p384,
+		"not pem":             writePEM(t, "empty.pem"),
+		"not a key":           writePEM(t, "cert.pem", "PRIVATE KEY", []byte("garbage")),
+		"missing file":        filepath.Join(t.TempDir(), "missing.pem"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := jwtkeys.LoadPEM("", file, ""); err == nil {
+				t.Fatal("LoadPEM succeeded")
+			}
+		})
+	}
+	if _, err := jwtkeys.LoadPEM("", "", ""); err == nil {
+		t.Fatal("LoadPEM without files succeeded")
+	}
+}
+
+func TestKeySet(t *testing.T) {
+	ecPrivate, _ := writePKCS8(t, ecKey)
+	signing, err := jwtkeys.LoadPEM("current", ecPrivate, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, rsaPublic := writePKCS8(t, rsaKey)
+	retired, err := jwtkeys.LoadPEM("retired", "", rsaPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hmac := jwtkeys.NewHMAC("legacy-secret")
+
+	set, err := jwtkeys.NewKeySet(signing, hmac, retired)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	if set.Signing() != signing {
+		t.Fatal("the first key does not sign")
+	}
+	if k, ok := set.Lookup("retired"); !ok || k != retired {
+		t.Fatal("Lookup(retired) failed")
+	}
+	if _, ok := set.Lookup("unknown"); ok {
+		t.Fatal("Lookup(unknown) succeeded")
+	}
+	if algs := set.Algorithms(); !slices.Equal(algs, []string{"ES256", "HS256", "RS256"}) {
+		t.Fatalf("Algorithms() = %v", algs)
+	}
+
+	// HMAC 密钥是共享密钥，不能发布
+	jwks := set.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("JWKS has %d keys, want 2: %+v", len(jwks.Keys), jwks.Keys)
+	}
+	ec, rs := jwks.Keys[0], jwks.Keys[1]
+	if ec.Kid != "current" || ec.Kty != "EC" || ec.Crv != "P-256" || ec.Alg != "ES256" || ec.Use != "sig" || ec.X == "" || ec.Y == "" {
+		t.Fatalf("EC JWK = %+v", ec)
+	}
+	if rs.Kid != "retired" || rs.Kty != "RSA" || rs.Alg != "RS256" || rs.E != "AQAB" || rs.N == "" {
+		t.Fatalf("RSA JWK = %+v", rs)
+	}
+
+	if _, err := jwtkeys.NewKeySet(); err == nil {
+		t.Fatal("NewKeySet without keys succeeded")
+	}
+	if _, err := jwtkeys.NewKeySet(retired, signing); err == nil {
+		t.Fatal("NewKeySet signing with a public key succeeded")
+	}
+	if _, err := jwtkeys.NewKeySet(signing, signing); err == nil {
+		t.Fatal("NewKeySet with a duplicate kid succeeded")
+	}
+}
+
+func TestThumbprint(t *testing.T) {
+	dec := base64.RawURLEncoding
+	// RFC 7638 第 3.1 节的 RSA 示例
+	n, _ := dec.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	rsaPub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+	// RFC 8037 附录 A.3 的 Ed25519 示例
+	x, _ := dec.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+
+	for name, tc := range map[string]struct {
+		pub  crypto.PublicKey
+		want string
+	}{
+		"rfc 7638 rsa":     {rsaPub, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"},
+		"rfc 8037 ed25519": {ed25519.PublicKey(x), "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := jwtkeys.Thumbprint(tc.pub)
+			if err != nil || got != tc.want {
+				t.Fatalf("Thumbprint = %q, %v, want %q", got, err, tc.want)
+			}
+		})
+	}
+}
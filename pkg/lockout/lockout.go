@@ -8,56 +8,139 @@ import (
 	"go-ddd-scaffold/pkg/cache"
 )
 
-const keyPrefix = "login_fail:"
+const (
+	keyPrefix     = "login_fail:"
+	lockKeyPrefix = "login_lock:"
+)
+
+// Options 锁定策略
+type Options struct {
+	Scope       string        // 键命名空间，如 "account:"、"ip:"
+	Threshold   int           // 触发锁定的失败次数
+	Window      time.Duration // 失败计数自第一次失败起保留的时长，每次锁定时续期
+	Duration    time.Duration // 首次锁定时长
+	MaxDuration time.Duration // 指数退避的锁定时长上限
+}
 
 // Manager 登录失败锁定管理器
+// 达到阈值后锁定 Duration，之后每次失败锁定时长翻倍，直至 MaxDuration。
+// 状态全部存放在 cache.Cache 中，使用共享缓存时多实例间生效。
 type Manager struct {
-	cache     cache.Cache
-	threshold int
-	duration  time.Duration
+	cache       cache.Cache
+	scope       string
+	threshold   int
+	window      time.Duration
+	duration    time.Duration
+	maxDuration time.Duration
 }
 
-// New 创建锁定管理器
+// New 创建锁定管理器（固定时长，无退避）
 func New(c cache.Cache, threshold int, duration time.Duration) *Manager {
-	if threshold <= 0 {
-		threshold = 5
+	return NewWithOptions(c, Options{Threshold: threshold, Duration: duration})
+}
+
+// NewWithOptions 按策略创建锁定管理器
+func NewWithOptions(c cache.Cache, opts Options) *Manager {
+	if opts.Threshold <= 0 {
+		opts.Threshold = 5
+	}
+	if opts.Duration <= 0 {
+		opts.Duration = 15 * time.Minute
 	}
-	if duration <= 0 {
-		duration = 15 * time.Minute
+	if opts.MaxDuration < opts.Duration {
+		opts.MaxDuration = opts.Duration
+	}
+	// 计数需比最长锁定活得久，否则锁定期间计数过期，退避无法升级
+	if opts.Window < opts.MaxDuration {
+		opts.Window = opts.MaxDuration
+	}
+	return &Manager{
+		cache:       c,
+		scope:       opts.Scope,
+		threshold:   opts.Threshold,
+		window:      opts.Window,
+		duration:    opts.Duration,
+		maxDuration: opts.MaxDuration,
 	}
-	return &Manager{cache: c, threshold: threshold, duration: duration}
 }
 
-// RecordFailure 记录一次登录失败（原子操作），返回当前失败次数
-func (m *Manager) RecordFailure(ctx context.Context, username string) int {
-	count, err := m.cache.Increment(ctx, keyPrefix+username, m.duration)
+// RecordFailure 记录一次登录失败（原子操作），返回当前失败次数。
+// 达到阈值时写入锁定标记。
+func (m *Manager) RecordFailure(ctx context.Context, key string) int {
+	count, err := m.cache.Increment(ctx, m.failKey(key), m.window)
 	if err != nil {
-		val, _ := m.cache.GetString(ctx, keyPrefix+username)
+		val, _ := m.cache.GetString(ctx, m.failKey(key))
 		c, _ := strconv.Atoi(val)
 		c++
-		_ = m.cache.SetString(ctx, keyPrefix+username, strconv.Itoa(c), m.duration)
-		return c
+		_ = m.cache.SetString(ctx, m.failKey(key), strconv.Itoa(c), m.window)
+		count = int64(c)
+	}
+
+	if int(count) >= m.threshold {
+		lock := m.lockDuration(int(count))
+		until := time.Now().Add(lock)
+		_ = m.cache.SetString(ctx, m.lockKey(key), strconv.FormatInt(until.UnixNano(), 10), lock)
+		// Increment 不会延长计数的有效期；锁定时续期，持续失败时退避才能跨窗口继续升级
+		_ = m.cache.SetString(ctx, m.failKey(key), strconv.Itoa(int(count)), m.window)
 	}
 	return int(count)
 }
 
+// LockedFor 返回剩余锁定时长，未锁定返回 0。
+// 缓存故障时放行（fail-open），避免缓存不可用导致所有人无法登录。
+func (m *Manager) LockedFor(ctx context.Context, key string) time.Duration {
+	val, err := m.cache.GetString(ctx, m.lockKey(key))
+	if err != nil {
+		return 0
+	}
+	until, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0
+	}
+	if remaining := time.Until(time.Unix(0, until)); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
 // IsLocked 是否已被锁定
-func (m *Manager) IsLocked(ctx context.Context, username string) bool {
-	val, err := m.cache.GetString(ctx, keyPrefix+username)
+func (m *Manager) IsLocked(ctx context.Context, key string) bool {
+	return m.LockedFor(ctx, key) > 0
+}
+
+// Failures 返回当前失败次数
+func (m *Manager) Failures(ctx context.Context, key string) int {
+	val, err := m.cache.GetString(ctx, m.failKey(key))
 	if err != nil {
-		return false
+		return 0
 	}
 	count, _ := strconv.Atoi(val)
-	return count >= m.threshold
+	return count
 }
 
-// Clear 清除失败记录（登录成功后调用）
-func (m *Manager) Clear(ctx context.Context, username string) {
-	_ = m.cache.Delete(ctx, keyPrefix+username)
+// Clear 清除失败记录与锁定（登录成功后调用）
+func (m *Manager) Clear(ctx context.Context, key string) {
+	_ = m.cache.Delete(ctx, m.failKey(key))
+	_ = m.cache.Delete(ctx, m.lockKey(key))
 }
 
 // Threshold 返回阈值
 func (m *Manager) Threshold() int { return m.threshold }
 
-// Duration 返回锁定时长
+// Duration 返回首次锁定时长
 func (m *Manager) Duration() time.Duration { return m.duration }
+
+// lockDuration 第 n 次失败对应的锁定时长：Duration * 2^(n-threshold)，封顶 MaxDuration
+func (m *Manager) lockDuration(count int) time.Duration {
+	d := m.duration
+	for i := m.threshold; i < count && d < m.maxDuration; i++ {
+		d *= 2
+	}
+	if d > m.maxDuration {
+		d = m.maxDuration
+	}
+	return d
+}
+
+func (m *Manager) failKey(key string) string { return keyPrefix + m.scope + key }
+func (m *Manager) lockKey(key string) string { return lockKeyPrefix + m.scope + key }
@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go-ddd-scaffold/pkg/logger"
+)
+
+// LogMailer 开发用发送器：邮件不会发出，只写入日志；
+// 配置目录时另存为 .eml 文件，可用邮件客户端打开
+type LogMailer struct {
+	from *mail.Address
+	dir  string
+	seq  atomic.Uint64
+}
+
+// NewLog 创建日志发送器，dir 为空时只写日志
+func NewLog(from, dir string) (*LogMailer, error) {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: invalid from address %q: %w", from, err)
+	}
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("mailer: %w", err)
+		}
+	}
+	return &LogMailer{from: addr, dir: dir}, nil
+}
+
+// Send 记录邮件
+func (m *LogMailer) Send(_ context.Context, msg *Message) error {
+	now := time.Now()
+	data, err := msg.Bytes(m.from, now)
+	if err != nil {
+		return err
+	}
+	logger.Infof("mail to=%s subject=%q\n%s", strings.Join(msg.To, ","), msg.Subject, msg.Text)
+	if m.dir == "" {
+		return nil
+	}
+	name := fmt.Sprintf("%s-%04d.eml", now.Format("20060102-150405"), m.seq.Add(1))
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o600)
+}
+
+// Name 返回实现名称
+func (m *LogMailer) Name() string { return "log" }
+
+var _ Mailer = (*LogMailer)(nil)
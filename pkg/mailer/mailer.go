@@ -0,0 +1,101 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Message 一封邮件
+type Message struct {
+	To      []string
+	Subject string
+	Text    string // 纯文本正文
+	HTML    string // HTML 正文，为空时只发送纯文本
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+	Name() string
+}
+
+// Bytes 按 RFC 5322 编码邮件：UTF-8 正文使用 quoted-printable，
+// 同时有纯文本和 HTML 时为 multipart/alternative
+func (m *Message) Bytes(from *mail.Address, now time.Time) ([]byte, error) {
+	if len(m.To) == 0 {
+		return nil, fmt.Errorf("mailer: message has no recipients")
+	}
+	to := make([]string, len(m.To))
+	for i, addr := range m.To {
+		parsed, err := mail.ParseAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("mailer: invalid recipient %q: %w", addr, err)
+		}
+		to[i] = parsed.String()
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domain := from.Address[strings.LastIndexByte(from.Address, '@')+1:]
+
+	var buf bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	header("From", from.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<%s@%s>", hex.EncodeToString(id), domain))
+	header("MIME-Version", "1.0")
+
+	if m.HTML == "" {
+		header("Content-Type", `text/plain; charset="utf-8"`)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQP(&buf, m.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct{ typ, content string }{{"text/plain", m.Text}, {"text/html", m.HTML}} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.typ + `; charset="utf-8"`},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQP(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	header("Content-Type", fmt.Sprintf(`multipart/alternative; boundary="%s"`, mw.Boundary()))
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func writeQP(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -0,0 +1,339 @@
+// Package mailertest 提供内存中的 SMTP 服务器，用于在进程内开发和测试邮件发送，
+// 用法类似 net/http/httptest。
+//
+// 接受所有邮件并保存在内存中，只实现 net/smtp 等常见客户端所需的命令，不提供认证和 TLS。
+package mailertest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxMessageSize 单封邮件 DATA 的上限
+const maxMessageSize = 10 << 20
+
+// Message 收到的邮件
+type Message struct {
+	ID         int       `json:"id"`
+	ReceivedAt time.Time `json:"received_at"`
+	From       string    `json:"from"`
+	To         []string  `json:"to"`
+	Subject    string    `json:"subject"`
+	Text       string    `json:"text"` // 解码后的 text/plain 正文
+	Raw        string    `json:"raw"`
+}
+
+// Options 服务器参数
+type Options struct {
+	Addr   string      // 监听地址，为空时使用 127.0.0.1 上的随机端口
+	Dir    string      // 非空时每封邮件另存为该目录下的 .eml 文件
+	Keep   int         // 内存中保留的邮件数，<= 0 时为 100
+	Logger *log.Logger // 记录每封邮件，为空时不记录
+}
+
+// Server 运行中的 SMTP 服务器
+type Server struct {
+	Host string
+	Port int
+
+	opts     Options
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	conns    map[net.Conn]struct{}
+	seq      int
+	messages []*Message
+	changed  chan struct{} // 收到新邮件时关闭并替换
+}
+
+// Start 启动服务器；使用完毕后调用 Close
+func Start(opts Options) (*Server, error) {
+	if opts.Addr == "" {
+		opts.Addr = "127.0.0.1:0"
+	}
+	if opts.Keep <= 0 {
+		opts.Keep = 100
+	}
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	ln, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	s := &Server{opts: opts, listener: ln, conns: make(map[net.Conn]struct{}), changed: make(chan struct{})}
+	s.Host = host
+	s.Port, _ = strconv.Atoi(port)
+
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// Close 停止监听并断开所有连接
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Messages 收到的邮件，最新的在前
+func (s *Server) Messages() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]*Message, 0, len(s.messages))
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		list = append(list, s.messages[i])
+	}
+	return list
+}
+
+// Reset 清空收到的邮件
+func (s *Server) Reset() {
+	s.mu.Lock()
+	s.messages = nil
+	s.mu.Unlock()
+}
+
+// Wait 等待一封满足 match 的邮件（match 为 nil 时为任意邮件），包括已经收到的；超时返回 nil
+func (s *Server) Wait(timeout time.Duration, match func(*Message) bool) *Message {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		s.mu.Lock()
+		changed := s.changed
+		for i := len(s.messages) - 1; i >= 0; i-- {
+			if m := s.messages[i]; match == nil || match(m) {
+				s.mu.Unlock()
+				return m
+			}
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return nil
+		}
+	}
+}
+
+// ServeHTTP GET 以 JSON 列出邮件（最新的在前），DELETE 清空
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.Messages())
+	case http.MethodDelete:
+		s.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				s.logf("accept: %v", err)
+			}
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serve(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// serve 一条 SMTP 会话
+func (s *Server) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(format string, args ...any) {
+		fmt.Fprintf(w, format+"\r\n", args...)
+		_ = w.Flush()
+	}
+
+	var from string
+	var to []string
+	reply("220 mailertest ready")
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Minute))
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			reply("250-mailertest")
+			reply("250-8BITMIME")
+			reply("250 SIZE %d", maxMessageSize)
+		case "HELO":
+			reply("250 mailertest")
+		case "MAIL":
+			from, to = addressArg(arg), nil
+			reply("250 OK")
+		case "RCPT":
+			if from == "" {
+				reply("503 MAIL first")
+				continue
+			}
+			to = append(to, addressArg(arg))
+			reply("250 OK")
+		case "DATA":
+			if len(to) == 0 {
+				reply("503 RCPT first")
+				continue
+			}
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			raw, err := readData(r)
+			if err != nil {
+				reply("552 %v", err)
+				return
+			}
+			m := s.add(from, to, raw)
+			reply("250 OK queued as %d", m.ID)
+			from, to = "", nil
+		case "RSET":
+			from, to = "", nil
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *Server) add(from string, to []string, raw []byte) *Message {
+	m := &Message{ReceivedAt: time.Now(), From: from, To: to, Raw: string(raw)}
+	if parsed, err := mail.ReadMessage(bytes.NewReader(raw)); err == nil {
+		m.Subject, _ = new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		m.Text = textBody(parsed.Header.Get("Content-Type"), parsed.Header.Get("Content-Transfer-Encoding"), parsed.Body)
+	}
+
+	s.mu.Lock()
+	s.seq++
+	m.ID = s.seq
+	s.messages = append(s.messages, m)
+	if len(s.messages) > s.opts.Keep {
+		s.messages = s.messages[len(s.messages)-s.opts.Keep:]
+	}
+	close(s.changed)
+	s.changed = make(chan struct{})
+	s.mu.Unlock()
+
+	s.logf("mail #%d from=%s to=%s subject=%q\n%s", m.ID, from, strings.Join(to, ","), m.Subject, m.Text)
+	if s.opts.Dir != "" {
+		name := filepath.Join(s.opts.Dir, fmt.Sprintf("%s-%04d.eml", m.ReceivedAt.Format("20060102-150405"), m.ID))
+		if err := os.WriteFile(name, raw, 0o600); err != nil {
+			s.logf("save %s: %v", name, err)
+		}
+	}
+	return m
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.opts.Logger != nil {
+		s.opts.Logger.Printf(format, args...)
+	}
+}
+
+// addressArg 从 "FROM:<a@b> SIZE=1" 或 "TO:<a@b>" 中取出地址
+func addressArg(arg string) string {
+	_, v, _ := strings.Cut(arg, ":")
+	v = strings.TrimSpace(v)
+	if i := strings.IndexByte(v, '>'); strings.HasPrefix(v, "<") && i > 0 {
+		return v[1:i]
+	}
+	v, _, _ = strings.Cut(v, " ")
+	return v
+}
+
+// readData 读取以单独一行 "." 结束的 DATA，并还原行首的点
+func readData(r *bufio.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return buf.Bytes(), nil
+		}
+		if buf.Len()+len(line) > maxMessageSize {
+			return nil, fmt.Errorf("message exceeds %d bytes", maxMessageSize)
+		}
+		buf.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+// textBody 解码 text/plain 正文，多部分邮件中查找第一个 text/plain 部分
+func textBody(contentType, encoding string, body io.Reader) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err != nil {
+				return ""
+			}
+			if text := textBody(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part); text != "" {
+				return text
+			}
+		}
+	}
+	if mediaType != "text/plain" {
+		return ""
+	}
+	if strings.EqualFold(encoding, "quoted-printable") {
+		body = quotedprintable.NewReader(body)
+	}
+	data, _ := io.ReadAll(body)
+	return string(data)
+}
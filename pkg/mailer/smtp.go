@@ -0,0 +1,128 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// 连接加密方式
+const (
+	EncryptionSTARTTLS = "starttls" // 明文连接后升级，通常为 587 端口
+	EncryptionTLS      = "tls"      // 隐式 TLS，通常为 465 端口
+	EncryptionNone     = "none"     // 不加密，仅用于本地测试服务器
+)
+
+// SMTPConfig SMTP 连接参数
+type SMTPConfig struct {
+	Host       string
+	Port       int
+	Username   string // 为空时不认证
+	Password   string
+	From       string // 发件人，如 "My Service <no-reply@example.com>"
+	Encryption string
+	Timeout    time.Duration // 单封邮件的发送时限
+}
+
+// SMTPMailer 通过 SMTP 服务器发送邮件
+// 每封邮件使用一条新连接；认证使用 PLAIN，标准库只允许在加密连接或 localhost 上进行。
+type SMTPMailer struct {
+	cfg  SMTPConfig
+	from *mail.Address
+}
+
+// NewSMTP 创建 SMTP 发送器
+func NewSMTP(cfg SMTPConfig) (*SMTPMailer, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: invalid from address %q: %w", cfg.From, err)
+	}
+	switch cfg.Encryption {
+	case "":
+		cfg.Encryption = EncryptionSTARTTLS
+	case EncryptionSTARTTLS, EncryptionTLS, EncryptionNone:
+	default:
+		return nil, fmt.Errorf("mailer: unsupported encryption %q", cfg.Encryption)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &SMTPMailer{cfg: cfg, from: from}, nil
+}
+
+// Send 发送邮件
+func (m *SMTPMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := msg.Bytes(m.from, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("mailer: dial %s: %w", addr, err)
+	}
+	if m.cfg.Encryption == EncryptionTLS {
+		conn = tls.Client(conn, &tls.Config{ServerName: m.cfg.Host})
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mailer: %w", err)
+	}
+	defer c.Close()
+
+	if m.cfg.Encryption == EncryptionSTARTTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("mailer: %s does not support STARTTLS", addr)
+		}
+		if err := c.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return fmt.Errorf("mailer: starttls: %w", err)
+		}
+	}
+	if m.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return fmt.Errorf("mailer: auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("mailer: MAIL FROM: %w", err)
+	}
+	for _, to := range msg.To {
+		rcpt, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("mailer: invalid recipient %q: %w", to, err)
+		}
+		if err := c.Rcpt(rcpt.Address); err != nil {
+			return fmt.Errorf("mailer: RCPT TO %s: %w", rcpt.Address, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("mailer: DATA: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("mailer: write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mailer: DATA: %w", err)
+	}
+	return c.Quit()
+}
+
+// Name 返回实现名称
+func (m *SMTPMailer) Name() string { return "smtp" }
+
+var _ Mailer = (*SMTPMailer)(nil)
@@ -0,0 +1,97 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*
+var defaultTemplates embed.FS
+
+// Templates 邮件模板集
+// 每封邮件由 <name>.txt（纯文本）和可选的 <name>.html 组成，
+// 主题在 txt 中用 {{define "<name>.subject"}}...{{end}} 定义。
+// 内置 verify_email 与 reset_password 两个模板。
+type Templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// LoadTemplates 加载内置模板；dir 不为空时，其中的同名文件覆盖内置模板
+func LoadTemplates(dir string) (*Templates, error) {
+	sub, _ := fs.Sub(defaultTemplates, "templates")
+	sources := []fs.FS{sub}
+	if dir != "" {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("mailer: %w", err)
+		}
+		sources = append(sources, os.DirFS(dir))
+	}
+
+	t := &Templates{text: texttemplate.New(""), html: htmltemplate.New("")}
+	for _, src := range sources {
+		if ok, err := hasFiles(src, "*.txt"); err != nil {
+			return nil, err
+		} else if ok {
+			if _, err := t.text.ParseFS(src, "*.txt"); err != nil {
+				return nil, fmt.Errorf("mailer: %w", err)
+			}
+		}
+		if ok, err := hasFiles(src, "*.html"); err != nil {
+			return nil, err
+		} else if ok {
+			if _, err := t.html.ParseFS(src, "*.html"); err != nil {
+				return nil, fmt.Errorf("mailer: %w", err)
+			}
+		}
+	}
+	return t, nil
+}
+
+// Render 用 data 渲染名为 name 的邮件
+func (t *Templates) Render(name string, data any, to ...string) (*Message, error) {
+	subject, err := t.execText(name+".subject", data)
+	if err != nil {
+		return nil, err
+	}
+	text, err := t.execText(name+".txt", data)
+	if err != nil {
+		return nil, err
+	}
+	msg := &Message{To: to, Subject: strings.TrimSpace(subject), Text: strings.TrimSpace(text) + "\n"}
+
+	if tpl := t.html.Lookup(name + ".html"); tpl != nil {
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("mailer: %w", err)
+		}
+		msg.HTML = buf.String()
+	}
+	return msg, nil
+}
+
+func (t *Templates) execText(name string, data any) (string, error) {
+	tpl := t.text.Lookup(name)
+	if tpl == nil {
+		return "", fmt.Errorf("mailer: template %q not found", name)
+	}
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("mailer: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func hasFiles(fsys fs.FS, pattern string) (bool, error) {
+	matches, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return false, fmt.Errorf("mailer: %w", err)
+	}
+	return len(matches) > 0, nil
+}
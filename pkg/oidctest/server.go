@@ -0,0 +1,332 @@
+// Package oidctest 提供内存中的 OpenID Connect 身份提供方，用于在进程内开发和测试单点登录，
+// 用法类似 net/http/httptest。
+//
+// 只实现授权码流程：发现文档、授权端点、令牌端点和 JWKS。授权码必须使用 PKCE（S256），且只能兑换一次。
+// 授权端点默认显示一个可以填写任意用户名、邮箱和组的表单；设置 AutoLogin 后直接以该用户登录。
+// 不校验任何密码，不要在开发机之外暴露。
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go-ddd-scaffold/pkg/jwtkeys"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// User 登录的用户
+type User struct {
+	Subject  string   // sub，为空时由用户名派生
+	Username string   // preferred_username 和 name
+	Email    string   // email，非空时 email_verified 为 true
+	Groups   []string // groups
+
+	EmailUnverified bool // 为 true 时 email_verified 恒为 false
+}
+
+// Options 服务器参数
+type Options struct {
+	Addr         string      // 监听地址，为空时使用 127.0.0.1 上的随机端口
+	Issuer       string      // 签发者 URL，须与服务访问本服务器的地址一致；为空时为 http://<监听地址>
+	ClientID     string      // 接受的 client_id（也是 id_token 的 audience），为空时为 my-service
+	ClientSecret string      // 要求的 client_secret，为空时接受公开客户端
+	AutoLogin    *User       // 跳过表单，直接以该用户登录
+	Logger       *log.Logger // 记录每个授权和兑换，为空时不记录
+}
+
+// Server 运行中的身份提供方
+type Server struct {
+	Issuer   string
+	ClientID string
+
+	opts     Options
+	key      *jwtkeys.Key
+	keys     *jwtkeys.KeySet
+	listener net.Listener
+	srv      *http.Server
+
+	mu     sync.Mutex
+	auto   *User
+	grants map[string]*grant
+}
+
+// grant 已签发、等待兑换的授权码
+type grant struct {
+	ClientID    string
+	RedirectURI string
+	Challenge   string
+	Nonce       string
+	User        User
+	ExpiresAt   time.Time
+}
+
+var form = template.Must(template.New("form").Parse(`<!doctype html>
+<title>Mock OIDC sign-in</title>
+<h3>Mock OIDC sign-in</h3>
+<form method="post">
+{{range $k, $v := .Params}}<input type="hidden" name="{{$k}}" value="{{index $v 0}}">
+{{end}}<p><label>Username <input name="username" value="{{.Username}}"></label></p>
+<p><label>Email <input name="email" value="{{.Email}}"></label></p>
+<p><label>Groups <input name="groups" value="{{.Groups}}" placeholder="comma-separated"></label></p>
+<button type="submit">Sign in</button>
+</form>`))
+
+// Start 启动服务器；使用完毕后调用 Close
+func Start(opts Options) (*Server, error) {
+	if opts.Addr == "" {
+		opts.Addr = "127.0.0.1:0"
+	}
+	if opts.ClientID == "" {
+		opts.ClientID = "my-service"
+	}
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	key := &jwtkeys.Key{ID: "mock", Method: jwt.SigningMethodRS256, Private: priv, Public: &priv.PublicKey}
+	keys, err := jwtkeys.NewKeySet(key)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Issuer == "" {
+		opts.Issuer = "http://" + ln.Addr().String()
+	}
+
+	s := &Server{
+		Issuer:   strings.TrimRight(opts.Issuer, "/"),
+		ClientID: opts.ClientID,
+		opts:     opts,
+		key:      key,
+		keys:     keys,
+		listener: ln,
+		auto:     opts.AutoLogin,
+		grants:   make(map[string]*grant),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+	s.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = s.srv.Serve(ln) }()
+	return s, nil
+}
+
+// Close 停止服务器
+func (s *Server) Close() {
+	_ = s.srv.Close()
+}
+
+// SetAutoLogin 更换自动登录的用户，nil 时恢复为表单
+func (s *Server) SetAutoLogin(u *User) {
+	s.mu.Lock()
+	s.auto = u
+	s.mu.Unlock()
+}
+
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                s.Issuer,
+		"authorization_endpoint":                s.Issuer + "/authorize",
+		"token_endpoint":                        s.Issuer + "/token",
+		"jwks_uri":                              s.Issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "profile", "email", "groups"},
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.keys.JWKS())
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := r.Form
+	if q.Get("response_type") != "code" || q.Get("client_id") != s.ClientID || q.Get("redirect_uri") == "" {
+		http.Error(w, "unsupported response_type, unknown client_id or missing redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256" {
+		redirectError(w, r, q, "invalid_request", "PKCE with S256 is required")
+		return
+	}
+
+	s.mu.Lock()
+	auto := s.auto
+	s.mu.Unlock()
+
+	var u User
+	switch {
+	case r.Method == http.MethodPost:
+		u = User{Username: q.Get("username"), Email: q.Get("email"), Groups: splitGroups(q.Get("groups"))}
+	case auto != nil:
+		u = *auto
+	default:
+		params := url.Values{}
+		for _, k := range []string{"response_type", "client_id", "redirect_uri", "scope", "state", "nonce", "code_challenge", "code_challenge_method"} {
+			params[k] = []string{q.Get(k)}
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = form.Execute(w, map[string]any{"Params": params, "Username": "alice", "Email": "alice@example.com"})
+		return
+	}
+	if u.Subject == "" {
+		sum := sha256.Sum256([]byte(u.Username))
+		u.Subject = base64.RawURLEncoding.EncodeToString(sum[:12])
+	}
+
+	g := &grant{
+		ClientID:    q.Get("client_id"),
+		RedirectURI: q.Get("redirect_uri"),
+		Challenge:   q.Get("code_challenge"),
+		Nonce:       q.Get("nonce"),
+		User:        u,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+	code := rand.Text()
+	s.mu.Lock()
+	s.grants[code] = g
+	s.mu.Unlock()
+	s.logf("authorize sub=%s username=%s groups=%s", u.Subject, u.Username, strings.Join(u.Groups, ","))
+
+	target, _ := url.Parse(g.RedirectURI)
+	params := target.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	target.RawQuery = params.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request", err.Error())
+		return
+	}
+	id, sec, ok := r.BasicAuth()
+	if !ok {
+		id, sec = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if id != s.ClientID || (s.opts.ClientSecret != "" && sec != s.opts.ClientSecret) {
+		tokenError(w, "invalid_client", "unknown client or wrong secret")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", "only authorization_code is supported")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	g, found := s.grants[code]
+	delete(s.grants, code) // 授权码只能兑换一次
+	s.mu.Unlock()
+	if !found || time.Now().After(g.ExpiresAt) || g.ClientID != id || g.RedirectURI != r.PostForm.Get("redirect_uri") {
+		tokenError(w, "invalid_grant", "unknown, expired or mismatched code")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != g.Challenge {
+		s.logf("token rejected: code_verifier does not match code_challenge")
+		tokenError(w, "invalid_grant", "code_verifier does not match code_challenge")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                s.Issuer,
+		"sub":                g.User.Subject,
+		"aud":                g.ClientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(5 * time.Minute).Unix(),
+		"nonce":              g.Nonce,
+		"preferred_username": g.User.Username,
+		"name":               g.User.Username,
+		"email":              g.User.Email,
+		"email_verified":     g.User.Email != "" && !g.User.EmailUnverified,
+		"groups":             g.User.Groups,
+	}
+	if claims["groups"] == nil {
+		claims["groups"] = []string{}
+	}
+	t := jwt.NewWithClaims(s.key.Method, claims)
+	t.Header["kid"] = s.key.ID
+	idToken, err := t.SignedString(s.key.Private)
+	if err != nil {
+		tokenError(w, "server_error", err.Error())
+		return
+	}
+	s.logf("token issued sub=%s", g.User.Subject)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.opts.Logger != nil {
+		s.opts.Logger.Printf(format, args...)
+	}
+}
+
+func redirectError(w http.ResponseWriter, r *http.Request, q url.Values, code, description string) {
+	target, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, description, http.StatusBadRequest)
+		return
+	}
+	params := target.Query()
+	params.Set("error", code)
+	params.Set("error_description", description)
+	params.Set("state", q.Get("state"))
+	target.RawQuery = params.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func tokenError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code, "error_description": description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func splitGroups(s string) []string {
+	out := []string{}
+	for _, g := range strings.Split(s, ",") {
+		if g = strings.TrimSpace(g); g != "" {
+			out = append(out, g)
+		}
+	}
+	return out
+}
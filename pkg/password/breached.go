@@ -0,0 +1,142 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// BreachedList 已泄露密码列表，按 Pwned Passwords 的约定以 UTF-8 密码的 SHA-1 查询
+type BreachedList interface {
+	// Contains 密码是否在列表中
+	Contains(pwd string) (bool, error)
+}
+
+// HashList 载入内存的 SHA-1 哈希集合，适合内置或较小的列表
+type HashList struct {
+	hashes map[[sha1.Size]byte]struct{}
+}
+
+// LoadHashList 读取每行一个 SHA-1 十六进制哈希的列表。
+// 哈希后可带 ":出现次数"（Pwned Passwords 下载文件的格式），空行和 # 开头的行被忽略。
+func LoadHashList(r io.Reader) (*HashList, error) {
+	l := &HashList{hashes: make(map[[sha1.Size]byte]struct{})}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hash, _, _ := strings.Cut(line, ":")
+		var sum [sha1.Size]byte
+		if len(hash) != 2*sha1.Size {
+			return nil, fmt.Errorf("line %d: not a SHA-1 hash", n)
+		}
+		if _, err := hex.Decode(sum[:], []byte(hash)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		l.hashes[sum] = struct{}{}
+	}
+	return l, scanner.Err()
+}
+
+// Contains 密码是否在列表中
+func (l *HashList) Contains(pwd string) (bool, error) {
+	_, ok := l.hashes[sha1.Sum([]byte(pwd))]
+	return ok, nil
+}
+
+// Len 列表中的哈希数
+func (l *HashList) Len() int {
+	return len(l.hashes)
+}
+
+// PrefixDir k-anonymity 哈希前缀目录。
+// 每个文件以 SHA-1 的前 5 位十六进制命名（可带 .txt 扩展名），每行是其余 35 位后缀加 ":出现次数"，
+// 与 Pwned Passwords range 接口的返回及其下载工具的输出一致。
+// 每次查询只读取一个前缀文件，完整的离线数据无需载入内存。
+type PrefixDir struct {
+	dir string
+}
+
+// NewPrefixDir 创建前缀目录列表
+func NewPrefixDir(dir string) *PrefixDir {
+	return &PrefixDir{dir: dir}
+}
+
+// Contains 密码是否在列表中；目录中没有对应前缀的文件视为不在列表中
+func (d *PrefixDir) Contains(pwd string) (bool, error) {
+	sum := sha1.Sum([]byte(pwd))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	f, err := os.Open(filepath.Join(d.dir, prefix))
+	if errors.Is(err, fs.ErrNotExist) {
+		f, err = os.Open(filepath.Join(d.dir, prefix+".txt"))
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		s, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(s, suffix) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// OpenBreachedList 打开离线的已泄露密码列表：目录视为 PrefixDir，文件由 LoadHashList 载入
+func OpenBreachedList(path string) (BreachedList, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return NewPrefixDir(path), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	l, err := LoadHashList(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return l, nil
+}
+
+//go:embed breached.txt
+var builtinBreached string
+
+var (
+	builtinOnce sync.Once
+	builtinList *HashList
+)
+
+// BuiltinBreachedList 内置的常见泄露密码列表（数百个最常用的密码）
+func BuiltinBreachedList() *HashList {
+	builtinOnce.Do(func() {
+		var err error
+		if builtinList, err = LoadHashList(strings.NewReader(builtinBreached)); err != nil {
+			panic("password: invalid builtin breached list: " + err.Error())
+		}
+	})
+	return builtinList
+}
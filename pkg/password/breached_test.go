@@ -0,0 +1,150 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sha1Hex 密码的大写 SHA-1 十六进制，与 Pwned Passwords 一致
+func sha1Hex(pwd string) string {
+	sum := sha1.Sum([]byte(pwd))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadHashList(t *testing.T) {
+	list := "# 注释\n\n" +
+		sha1Hex("hunter2") + "\n" +
+		strings.ToLower(sha1Hex("letmein")) + ":52\n" +
+		"  " + sha1Hex("trustno1") + ":7  \n"
+	l, err := LoadHashList(strings.NewReader(list))
+	if err != nil {
+		t.Fatalf("LoadHashList: %v", err)
+	}
+	if l.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", l.Len())
+	}
+	for pwd, want := range map[string]bool{
+		"hunter2":  true,
+		"letmein":  true,
+		"trustno1": true,
+		"Hunter2":  false,
+		"":         false,
+	} {
+		if got, err := l.Contains(pwd); got != want || err != nil {
+			t.Errorf("Contains(%q) = %v, %v, want %v", pwd, got, err, want)
+		}
+	}
+}
+
+func TestLoadHashListRejects(t *testing.T) {
+	for name, tc := range map[string]struct {
+		list string
+		line string
+	}{
+		"short hash":    {sha1Hex("a") + "\n" + sha1Hex("b")[:39], "line 2"},
+		"long hash":     {sha1Hex("a") + "0", "line 1"},
+		"not hex":       {"# 注释\n" + strings.Repeat("z", 40), "line 2"},
+		"prefix format": {sha1Hex("a")[5:] + ":3", "line 1"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadHashList(strings.NewReader(tc.list))
+			if err == nil || !strings.HasPrefix(err.Error(), tc.line+":") {
+				t.Fatalf("LoadHashList error = %v, want one on %s", err, tc.line)
+			}
+		})
+	}
+}
+
+func TestPrefixDir(t *testing.T) {
+	dir := t.TempDir()
+	// 前缀文件可不带扩展名，也可带 .txt；后缀不区分大小写
+	plain, txt := sha1Hex("hunter2"), sha1Hex("letmein")
+	writeFile(t, filepath.Join(dir, plain[:5]), "0000000000000000000000000000000000A:1\r\n"+plain[5:]+":17\r\n")
+	writeFile(t, filepath.Join(dir, txt[:5]+".txt"), strings.ToLower(txt[5:])+":3\n")
+	d := NewPrefixDir(dir)
+
+	for pwd, want := range map[string]bool{
+		"hunter2":  true,
+		"letmein":  true,
+		"trustno1": false, // 没有对应的前缀文件
+	} {
+		if got, err := d.Contains(pwd); got != want || err != nil {
+			t.Errorf("Contains(%q) = %v, %v, want %v", pwd, got, err, want)
+		}
+	}
+
+	// 无法读取的前缀文件返回错误而不是视为不在列表中
+	broken := sha1Hex("trustno1")
+	if err := os.Mkdir(filepath.Join(dir, broken[:5]), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := d.Contains("trustno1"); ok || err == nil {
+		t.Fatalf("Contains with an unreadable prefix file = %v, %v, want an error", ok, err)
+	}
+}
+
+func TestOpenBreachedList(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "hashes.txt")
+	writeFile(t, file, sha1Hex("hunter2")+":17\n")
+	bad := filepath.Join(dir, "bad.txt")
+	writeFile(t, bad, "not a hash\n")
+
+	l, err := OpenBreachedList(file)
+	if err != nil {
+		t.Fatalf("OpenBreachedList(file): %v", err)
+	}
+	if _, ok := l.(*HashList); !ok {
+		t.Fatalf("a file opens as %T, want *HashList", l)
+	}
+	if ok, _ := l.Contains("hunter2"); !ok {
+		t.Fatal("hash list misses hunter2")
+	}
+
+	l, err = OpenBreachedList(dir)
+	if err != nil {
+		t.Fatalf("OpenBreachedList(dir): %v", err)
+	}
+	if _, ok := l.(*PrefixDir); !ok {
+		t.Fatalf("a directory opens as %T, want *PrefixDir", l)
+	}
+
+	if _, err := OpenBreachedList(filepath.Join(dir, "missing")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("OpenBreachedList(missing) = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := OpenBreachedList(bad); err == nil || !strings.Contains(err.Error(), bad) {
+		t.Fatalf("OpenBreachedList(bad) = %v, want an error naming the file", err)
+	}
+}
+
+func TestBuiltinBreachedList(t *testing.T) {
+	l := BuiltinBreachedList()
+	if l != BuiltinBreachedList() {
+		t.Fatal("the builtin list is loaded more than once")
+	}
+	if l.Len() < 500 {
+		t.Fatalf("builtin list has %d hashes", l.Len())
+	}
+	// 默认管理员的初始密码也在列表中
+	for _, pwd := range []string{"admin123", "password", "123456", "qwerty", "P@ssw0rd"} {
+		if ok, err := l.Contains(pwd); !ok || err != nil {
+			t.Errorf("builtin list misses %q: %v", pwd, err)
+		}
+	}
+	if ok, _ := l.Contains("Zq8#vLm2!pT9x"); ok {
+		t.Error("builtin list contains a random password")
+	}
+}
@@ -0,0 +1,210 @@
+package password
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 支持的哈希算法
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+	bcryptMaxLength  = 72
+)
+
+var (
+	// ErrBusy 等待哈希工作池超时
+	ErrBusy = errors.New("password hasher busy")
+	// ErrTooLong 密码超过 bcrypt 的 72 字节上限
+	ErrTooLong = errors.New("password exceeds 72 bytes")
+)
+
+// Params 哈希参数
+// 新密码使用 Algorithm 哈希；参数与之不同的已有哈希仍可验证，并被 NeedsRehash 标记为需要升级。
+type Params struct {
+	Algorithm     string
+	Memory        uint32        // argon2id 内存，KiB
+	Iterations    uint32        // argon2id 迭代次数
+	Parallelism   uint8         // argon2id 并行度
+	BcryptCost    int           // bcrypt 代价
+	MaxConcurrent int           // 同时进行的哈希运算上限，<= 0 时为 CPU 核数
+	QueueTimeout  time.Duration // 等待空闲槽位的最长时间，<= 0 时只受 ctx 限制
+}
+
+// DefaultParams 默认参数：argon2id（OWASP 推荐的 19 MiB、2 次迭代、并行度 1）
+func DefaultParams() Params {
+	return Params{
+		Algorithm:    Argon2id,
+		Memory:       19 * 1024,
+		Iterations:   2,
+		Parallelism:  1,
+		BcryptCost:   12,
+		QueueTimeout: 5 * time.Second,
+	}
+}
+
+// Hasher 版本化的密码哈希器
+// argon2id 哈希使用 PHC 字符串格式（$argon2id$v=19$m=..,t=..,p=..$salt$hash），
+// bcrypt 使用其标准格式（$2a$cost$...）。所有运算都经过有界工作池，
+// 大量并发登录只会排队或返回 ErrBusy，不会耗尽 CPU 和内存。
+type Hasher struct {
+	params Params
+	slots  chan struct{}
+}
+
+// NewHasher 创建哈希器
+func NewHasher(p Params) (*Hasher, error) {
+	switch p.Algorithm {
+	case Argon2id:
+		if p.Memory < 8*uint32(p.Parallelism) || p.Iterations < 1 || p.Parallelism < 1 {
+			return nil, fmt.Errorf("invalid argon2id parameters m=%d t=%d p=%d", p.Memory, p.Iterations, p.Parallelism)
+		}
+	case Bcrypt:
+		if p.BcryptCost < bcrypt.MinCost || p.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, p.BcryptCost)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", p.Algorithm)
+	}
+	if p.MaxConcurrent <= 0 {
+		p.MaxConcurrent = runtime.NumCPU()
+	}
+	return &Hasher{params: p, slots: make(chan struct{}, p.MaxConcurrent)}, nil
+}
+
+// Hash 使用当前算法和参数哈希密码
+func (h *Hasher) Hash(ctx context.Context, pwd string) (string, error) {
+	if h.params.Algorithm == Bcrypt && len(pwd) > bcryptMaxLength {
+		return "", ErrTooLong
+	}
+	release, err := h.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	if h.params.Algorithm == Bcrypt {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(pwd), h.params.BcryptCost)
+		return string(hashed), err
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	p := h.params
+	key := argon2.IDKey([]byte(pwd), salt, p.Iterations, p.Memory, p.Parallelism, argon2KeyLength)
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		Argon2id, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+// Verify 验证密码是否与哈希匹配
+// 哈希格式无法识别时视为不匹配；只有工作池繁忙或 ctx 结束时返回错误。
+func (h *Hasher) Verify(ctx context.Context, pwd, encoded string) (bool, error) {
+	if encoded == "" {
+		return false, nil
+	}
+	release, err := h.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	if a, ok := parseArgon2(encoded); ok {
+		key := argon2.IDKey([]byte(pwd), a.salt, a.iterations, a.memory, a.parallelism, uint32(len(a.key)))
+		return subtle.ConstantTimeCompare(key, a.key) == 1, nil
+	}
+	return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(pwd)) == nil, nil
+}
+
+// NeedsRehash 哈希的算法或参数是否与当前配置不同
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	p := h.params
+	if a, ok := parseArgon2(encoded); ok {
+		return p.Algorithm != Argon2id || a.memory != p.Memory || a.iterations != p.Iterations ||
+			a.parallelism != p.Parallelism || len(a.key) != argon2KeyLength
+	}
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+	return p.Algorithm != Bcrypt || cost != p.BcryptCost
+}
+
+// acquire 占用一个工作池槽位，返回释放函数
+func (h *Hasher) acquire(ctx context.Context) (func(), error) {
+	release := func() { <-h.slots }
+	select {
+	case h.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	wait := ctx
+	if h.params.QueueTimeout > 0 {
+		var cancel context.CancelFunc
+		wait, cancel = context.WithTimeout(ctx, h.params.QueueTimeout)
+		defer cancel()
+	}
+	select {
+	case h.slots <- struct{}{}:
+		return release, nil
+	case <-wait.Done():
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrBusy
+	}
+}
+
+type argon2Hash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// parseArgon2 解析 PHC 格式的 argon2id 哈希
+func parseArgon2(encoded string) (*argon2Hash, bool) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != Argon2id {
+		return nil, false
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, false
+	}
+	a := &argon2Hash{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &a.memory, &a.iterations, &a.parallelism); err != nil {
+		return nil, false
+	}
+	if a.iterations < 1 || a.parallelism < 1 {
+		return nil, false
+	}
+	var err error
+	if a.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, false
+	}
+	if a.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(a.key) == 0 {
+		return nil, false
+	}
+	return a, true
+}
@@ -0,0 +1,237 @@
+package password
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams 足够快的 argon2id 参数
+func testParams() Params {
+	return Params{Algorithm: Argon2id, Memory: 64, Iterations: 1, Parallelism: 1, BcryptCost: bcrypt.MinCost, MaxConcurrent: 2}
+}
+
+func newTestHasher(t *testing.T, p Params) *Hasher {
+	t.Helper()
+	h, err := NewHasher(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestHashArgon2PHC(t *testing.T) {
+	ctx := context.Background()
+	h := newTestHasher(t, testParams())
+
+	encoded, err := h.Hash(ctx, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" || parts[2] != "v=19" || parts[3] != "m=64,t=1,p=1" {
+		t.Fatalf("hash %q is not in PHC format", encoded)
+	}
+	salt, _ := base64.RawStdEncoding.DecodeString(parts[4])
+	key, _ := base64.RawStdEncoding.DecodeString(parts[5])
+	if len(salt) != argon2SaltLength || len(key) != argon2KeyLength {
+		t.Fatalf("salt %d bytes, key %d bytes", len(salt), len(key))
+	}
+
+	a, ok := parseArgon2(encoded)
+	if !ok || a.memory != 64 || a.iterations != 1 || a.parallelism != 1 {
+		t.Fatalf("parseArgon2(%q) = %+v, %v", encoded, a, ok)
+	}
+	if ok, err := h.Verify(ctx, "correct horse", encoded); !ok || err != nil {
+		t.Fatalf("Verify right password = %v, %v", ok, err)
+	}
+	if ok, err := h.Verify(ctx, "correct horse!", encoded); ok || err != nil {
+		t.Fatalf("Verify wrong password = %v, %v", ok, err)
+	}
+
+	// 每次哈希使用新的盐
+	again, err := h.Hash(ctx, "correct horse")
+	if err != nil || again == encoded {
+		t.Fatalf("hashing twice gave %q, %v", again, err)
+	}
+}
+
+func TestParseArgon2Rejects(t *testing.T) {
+	salt := base64.RawStdEncoding.EncodeToString(make([]byte, 16))
+	key := base64.RawStdEncoding.EncodeToString(make([]byte, 32))
+	for name, encoded := range map[string]string{
+		"empty":                 "",
+		"too few fields":        "$argon2id$v=19$m=64,t=1,p=1$" + salt,
+		"too many fields":       "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$" + key + "$x",
+		"leading text":          "x$argon2id$v=19$m=64,t=1,p=1$" + salt + "$" + key,
+		"argon2i":               "$argon2i$v=19$m=64,t=1,p=1$" + salt + "$" + key,
+		"old version":           "$argon2id$v=16$m=64,t=1,p=1$" + salt + "$" + key,
+		"no version":            "$argon2id$m=64,t=1,p=1$" + salt + "$" + key + "$",
+		"parameters not number": "$argon2id$v=19$m=x,t=1,p=1$" + salt + "$" + key,
+		"missing parameter":     "$argon2id$v=19$m=64,t=1$" + salt + "$" + key,
+		"zero iterations":       "$argon2id$v=19$m=64,t=0,p=1$" + salt + "$" + key,
+		"zero parallelism":      "$argon2id$v=19$m=64,t=1,p=0$" + salt + "$" + key,
+		"parallelism overflow":  "$argon2id$v=19$m=64,t=1,p=256$" + salt + "$" + key,
+		"memory overflow":       "$argon2id$v=19$m=4294967296,t=1,p=1$" + salt + "$" + key,
+		"negative iterations":   "$argon2id$v=19$m=64,t=-1,p=1$" + salt + "$" + key,
+		"salt not base64":       "$argon2id$v=19$m=64,t=1,p=1$!!$" + key,
+		"key not base64":        "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$!!",
+		"empty key":             "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if a, ok := parseArgon2(encoded); ok {
+				t.Fatalf("parseArgon2 accepted %q: %+v", encoded, a)
+			}
+			// 无法识别的哈希按 bcrypt 校验，只是不匹配
+			h := newTestHasher(t, testParams())
+			if ok, err := h.Verify(context.Background(), "", encoded); ok || err != nil {
+				t.Fatalf("Verify = %v, %v, want a mismatch", ok, err)
+			}
+			if !h.NeedsRehash(encoded) {
+				t.Fatal("NeedsRehash = false for an unreadable hash")
+			}
+		})
+	}
+}
+
+func TestVerifyAcrossAlgorithms(t *testing.T) {
+	ctx := context.Background()
+	argon := newTestHasher(t, testParams())
+	bp := testParams()
+	bp.Algorithm = Bcrypt
+	bc := newTestHasher(t, bp)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := argon.Verify(ctx, "correct horse", string(legacy)); !ok || err != nil {
+		t.Fatalf("argon2id hasher verifying bcrypt = %v, %v", ok, err)
+	}
+	if ok, _ := argon.Verify(ctx, "wrong", string(legacy)); ok {
+		t.Fatal("wrong password matched a bcrypt hash")
+	}
+
+	modern, err := argon.Hash(ctx, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := bc.Verify(ctx, "correct horse", modern); !ok || err != nil {
+		t.Fatalf("bcrypt hasher verifying argon2id = %v, %v", ok, err)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	ctx := context.Background()
+	current := testParams()
+	h := newTestHasher(t, current)
+	argonHash, err := h.Hash(ctx, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		change  func(p *Params)
+		encoded string
+		want    bool
+	}{
+		"same argon2id parameters": {func(*Params) {}, argonHash, false},
+		"more memory":              {func(p *Params) { p.Memory = 128 }, argonHash, true},
+		"more iterations":          {func(p *Params) { p.Iterations = 2 }, argonHash, true},
+		"more parallelism":         {func(p *Params) { p.Parallelism = 2 }, argonHash, true},
+		"switched to bcrypt":       {func(p *Params) { p.Algorithm = Bcrypt }, argonHash, true},
+		"bcrypt under argon2id":    {func(*Params) {}, string(bcryptHash), true},
+		"same bcrypt cost":         {func(p *Params) { p.Algorithm = Bcrypt }, string(bcryptHash), false},
+		"higher bcrypt cost":       {func(p *Params) { p.Algorithm, p.BcryptCost = Bcrypt, bcrypt.MinCost+1 }, string(bcryptHash), true},
+		"short argon2id key":       {func(*Params) {}, argonHash[:strings.LastIndex(argonHash, "$")+1] + "AAAAAAAAAAAAAAAAAAAAAA", true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := current
+			tc.change(&p)
+			if got := newTestHasher(t, p).NeedsRehash(tc.encoded); got != tc.want {
+				t.Fatalf("NeedsRehash = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewHasherRejects(t *testing.T) {
+	for name, change := range map[string]func(p *Params){
+		"unknown algorithm":         func(p *Params) { p.Algorithm = "md5" },
+		"zero iterations":           func(p *Params) { p.Iterations = 0 },
+		"zero parallelism":          func(p *Params) { p.Parallelism = 0 },
+		"memory below 8 KiB a lane": func(p *Params) { p.Memory, p.Parallelism = 15, 2 },
+		"bcrypt cost too low":       func(p *Params) { p.Algorithm, p.BcryptCost = Bcrypt, bcrypt.MinCost-1 },
+		"bcrypt cost too high":      func(p *Params) { p.Algorithm, p.BcryptCost = Bcrypt, bcrypt.MaxCost+1 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := testParams()
+			change(&p)
+			if _, err := NewHasher(p); err == nil {
+				t.Fatalf("NewHasher(%+v) succeeded", p)
+			}
+		})
+	}
+}
+
+func TestBcryptRejectsLongPasswords(t *testing.T) {
+	p := testParams()
+	p.Algorithm = Bcrypt
+	h := newTestHasher(t, p)
+	if _, err := h.Hash(context.Background(), strings.Repeat("a", 73)); !errors.Is(err, ErrTooLong) {
+		t.Fatalf("Hash of 73 bytes = %v, want ErrTooLong", err)
+	}
+	if _, err := h.Hash(context.Background(), strings.Repeat("a", 72)); err != nil {
+		t.Fatalf("Hash of 72 bytes: %v", err)
+	}
+}
+
+func TestPoolBusy(t *testing.T) {
+	p := testParams()
+	p.MaxConcurrent = 1
+	p.QueueTimeout = 20 * time.Millisecond
+	h := newTestHasher(t, p)
+	encoded, err := h.Hash(context.Background(), "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 占满唯一的槽位
+	h.slots <- struct{}{}
+
+	start := time.Now()
+	if _, err := h.Hash(context.Background(), "correct horse"); !errors.Is(err, ErrBusy) {
+		t.Fatalf("Hash with a full pool = %v, want ErrBusy", err)
+	}
+	if waited := time.Since(start); waited < p.QueueTimeout {
+		t.Fatalf("gave up after %v, before the %v queue timeout", waited, p.QueueTimeout)
+	}
+	if _, err := h.Verify(context.Background(), "correct horse", encoded); !errors.Is(err, ErrBusy) {
+		t.Fatalf("Verify with a full pool = %v, want ErrBusy", err)
+	}
+
+	// 调用方先放弃时返回 ctx 的错误而不是 ErrBusy
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := h.Verify(ctx, "correct horse", encoded); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Verify with a canceled context = %v, want context.Canceled", err)
+	}
+
+	// 槽位释放后排队的请求得以继续
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		<-h.slots
+	}()
+	if ok, err := h.Verify(context.Background(), "correct horse", encoded); !ok || err != nil {
+		t.Fatalf("Verify after a slot was freed = %v, %v", ok, err)
+	}
+}
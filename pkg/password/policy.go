@@ -1,91 +1,164 @@
 package password
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
+)
 
-	"golang.org/x/crypto/bcrypt"
+// 规则 ID，与配置项同名，前端可据此显示本地化提示
+const (
+	RuleMinLength      = "min_length"
+	RuleMaxLength      = "max_length"
+	RuleRequireUpper   = "require_upper"
+	RuleRequireLower   = "require_lower"
+	RuleRequireNumber  = "require_number"
+	RuleRequireSpecial = "require_special"
+	RuleMinStrength    = "min_strength"
+	RuleBreached       = "breached"
 )
 
-// Policy 密码策略
+// specialChars 视为特殊字符的 ASCII 符号
+const specialChars = "!@#$%^&*()_+-=[]{}|;':\",./<>?`~\\"
+
+// Policy 密码策略，长度按字符（rune）而非字节计算
 type Policy struct {
 	MinLength      int
+	MaxLength      int // 0 不限制
 	RequireUpper   bool
+	RequireLower   bool
 	RequireNumber  bool
 	RequireSpecial bool
+	MinStrength    int          // Score 的最低值（0-4），0 不检查
+	Breached       BreachedList // 已泄露密码列表，nil 不检查
 }
 
-// DefaultPolicy 默认密码策略（最小长度 8）
+// DefaultPolicy 默认密码策略：8-64 个字符、强度不低于 2、不在内置的常见泄露密码列表中
 func DefaultPolicy() *Policy {
-	return &Policy{MinLength: 8}
+	return &Policy{MinLength: 8, MaxLength: 64, MinStrength: 2, Breached: BuiltinBreachedList()}
+}
+
+// Violation 违反的一条规则
+type Violation struct {
+	Rule  string `json:"rule"`
+	Limit int    `json:"limit,omitempty"` // 长度或强度规则的界限
+}
+
+// Message 规则的中文提示
+func (v Violation) Message() string {
+	switch v.Rule {
+	case RuleMinLength:
+		return fmt.Sprintf("密码长度不能少于 %d 位", v.Limit)
+	case RuleMaxLength:
+		return fmt.Sprintf("密码长度不能超过 %d 位", v.Limit)
+	case RuleRequireUpper:
+		return "密码必须包含大写字母"
+	case RuleRequireLower:
+		return "密码必须包含小写字母"
+	case RuleRequireNumber:
+		return "密码必须包含数字"
+	case RuleRequireSpecial:
+		return "密码必须包含特殊字符"
+	case RuleMinStrength:
+		return "密码强度不足，请使用更长或更复杂的密码"
+	case RuleBreached:
+		return "该密码已出现在泄露的密码库中，请更换"
+	default:
+		return v.Rule
+	}
+}
+
+// EnglishMessage 规则的英文提示
+func (v Violation) EnglishMessage() string {
+	switch v.Rule {
+	case RuleMinLength:
+		return fmt.Sprintf("Password must be at least %d characters long", v.Limit)
+	case RuleMaxLength:
+		return fmt.Sprintf("Password must be at most %d characters long", v.Limit)
+	case RuleRequireUpper:
+		return "Password must contain an uppercase letter"
+	case RuleRequireLower:
+		return "Password must contain a lowercase letter"
+	case RuleRequireNumber:
+		return "Password must contain a digit"
+	case RuleRequireSpecial:
+		return "Password must contain a special character"
+	case RuleMinStrength:
+		return "Password is too weak; use a longer or more complex one"
+	case RuleBreached:
+		return "Password has appeared in a data breach; choose another one"
+	default:
+		return v.Rule
+	}
+}
+
+// MarshalJSON 附带英文提示，未做本地化的客户端可直接显示
+func (v Violation) MarshalJSON() ([]byte, error) {
+	type rule Violation
+	return json.Marshal(struct {
+		rule
+		Message string `json:"message"`
+	}{rule(v), v.EnglishMessage()})
+}
+
+// ValidationError 密码不符合策略，作为错误详情返回给客户端
+type ValidationError struct {
+	Violations []Violation `json:"violations"`
+	Score      int         `json:"score"` // 密码的强度评分 0-4
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message()
+	}
+	return strings.Join(msgs, "；")
 }
 
-// Validate 验证密码是否符合策略，返回错误列表
-func (p *Policy) Validate(pwd string) []string {
-	var errs []string
+// Validate 验证密码是否符合策略，不符合时返回 *ValidationError。
+// 查询泄露密码列表失败时 listErr 非空，其余规则的结果不受影响，由调用方决定是否放行。
+func (p *Policy) Validate(pwd string) (verr *ValidationError, listErr error) {
+	var violations []Violation
 	minLen := p.MinLength
 	if minLen <= 0 {
 		minLen = 8
 	}
-	if len(pwd) < minLen {
-		errs = append(errs, fmt.Sprintf("密码长度不能少于 %d 位", minLen))
+	length := utf8.RuneCountInString(pwd)
+	if length < minLen {
+		violations = append(violations, Violation{Rule: RuleMinLength, Limit: minLen})
 	}
-	if p.RequireUpper {
-		has := false
-		for _, r := range pwd {
-			if unicode.IsUpper(r) {
-				has = true
-				break
-			}
-		}
-		if !has {
-			errs = append(errs, "密码必须包含大写字母")
-		}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, Violation{Rule: RuleMaxLength, Limit: p.MaxLength})
 	}
-	if p.RequireNumber {
-		has := false
-		for _, r := range pwd {
-			if unicode.IsDigit(r) {
-				has = true
-				break
-			}
-		}
-		if !has {
-			errs = append(errs, "密码必须包含数字")
-		}
+	if p.RequireUpper && !strings.ContainsFunc(pwd, unicode.IsUpper) {
+		violations = append(violations, Violation{Rule: RuleRequireUpper})
 	}
-	if p.RequireSpecial {
-		has := false
-		for _, r := range pwd {
-			if strings.ContainsRune("!@#$%^&*()_+-=[]{}|;':\",./<>?", r) {
-				has = true
-				break
-			}
-		}
-		if !has {
-			errs = append(errs, "密码必须包含特殊字符")
+	if p.RequireLower && !strings.ContainsFunc(pwd, unicode.IsLower) {
+		violations = append(violations, Violation{Rule: RuleRequireLower})
+	}
+	if p.RequireNumber && !strings.ContainsFunc(pwd, unicode.IsDigit) {
+		violations = append(violations, Violation{Rule: RuleRequireNumber})
+	}
+	if p.RequireSpecial && !strings.ContainsAny(pwd, specialChars) {
+		violations = append(violations, Violation{Rule: RuleRequireSpecial})
+	}
+	score := Score(pwd)
+	if p.MinStrength > 0 && score < p.MinStrength {
+		violations = append(violations, Violation{Rule: RuleMinStrength, Limit: p.MinStrength})
+	}
+	if p.Breached != nil {
+		breached, err := p.Breached.Contains(pwd)
+		if err != nil {
+			listErr = fmt.Errorf("check breached passwords: %w", err)
+		} else if breached {
+			violations = append(violations, Violation{Rule: RuleBreached})
+			score = 0
 		}
 	}
-	return errs
-}
-
-// ValidateError 将验证结果合并为单一错误
-func (p *Policy) ValidateError(pwd string) error {
-	errs := p.Validate(pwd)
-	if len(errs) == 0 {
-		return nil
+	if len(violations) == 0 {
+		return nil, listErr
 	}
-	return fmt.Errorf("%s", strings.Join(errs, "；"))
-}
-
-// Hash 使用 bcrypt 加密密码
-func Hash(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 12)
-	return string(bytes), err
-}
-
-// Verify 验证密码是否匹配
-func Verify(password, hash string) bool {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	return &ValidationError{Violations: violations, Score: score}, listErr
 }
@@ -0,0 +1,139 @@
+package password
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// failingList 查询总是失败的泄露密码列表
+type failingList struct{}
+
+func (failingList) Contains(string) (bool, error) { return false, errors.New("list unavailable") }
+
+// rules 违反的规则 ID
+func rules(verr *ValidationError) []string {
+	if verr == nil {
+		return nil
+	}
+	ids := make([]string, len(verr.Violations))
+	for i, v := range verr.Violations {
+		ids[i] = v.Rule
+	}
+	return ids
+}
+
+func TestValidate(t *testing.T) {
+	strict := &Policy{MinLength: 10, MaxLength: 20, RequireUpper: true, RequireLower: true, RequireNumber: true, RequireSpecial: true}
+	strong := &Policy{MinLength: 8, MinStrength: 3}
+	builtin := DefaultPolicy()
+
+	for name, tc := range map[string]struct {
+		policy *Policy
+		pwd    string
+		want   []string
+	}{
+		"strict accepts":       {strict, "Zq8#vLm2!pT9x", nil},
+		"too short":            {strict, "Zq8#vLm2!", []string{RuleMinLength}},
+		"too long":             {strict, "Zq8#vLm2!pT9xZq8#vLm2!", []string{RuleMaxLength}},
+		"no upper":             {strict, "zq8#vlm2!pt9x", []string{RuleRequireUpper}},
+		"no lower":             {strict, "ZQ8#VLM2!PT9X", []string{RuleRequireLower}},
+		"no number":            {strict, "Zqx#vLmy!pTkx", []string{RuleRequireNumber}},
+		"no special":           {strict, "Zq8kvLm2wpT9x", []string{RuleRequireSpecial}},
+		"every class missing":  {strict, "密码安全很重要啊真的", []string{RuleRequireUpper, RuleRequireLower, RuleRequireNumber, RuleRequireSpecial}},
+		"weak":                 {strong, "correcthorse", []string{RuleMinStrength}},
+		"strong enough":        {strong, "horse battery", nil},
+		"breached":             {builtin, "P@ssw0rd", []string{RuleBreached}},
+		"breached and weak":    {builtin, "admin123", []string{RuleMinStrength, RuleBreached}},
+		"default accepts":      {builtin, "Zq8#vLm2!pT9x", nil},
+		"default minimum of 8": {&Policy{}, "Zq8#vLm", []string{RuleMinLength}},
+		// 长度按字符而非字节计算：8 个汉字是 24 字节
+		"characters not bytes": {&Policy{MinLength: 8, MaxLength: 10}, "密码安全很重要啊", nil},
+		"seven characters":     {&Policy{MinLength: 8, MaxLength: 10}, "密码安全很重要", []string{RuleMinLength}},
+		"eleven characters":    {&Policy{MinLength: 8, MaxLength: 10}, "密码安全很重要啊真的是", []string{RuleMaxLength}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			verr, err := tc.policy.Validate(tc.pwd)
+			if err != nil {
+				t.Fatalf("list error: %v", err)
+			}
+			if got := rules(verr); !slices.Equal(got, tc.want) {
+				t.Fatalf("Validate(%q) violates %v, want %v", tc.pwd, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateLimitsAndScore(t *testing.T) {
+	verr, _ := (&Policy{MinLength: 12, MaxLength: 64, MinStrength: 3}).Validate("abc")
+	want := []Violation{{Rule: RuleMinLength, Limit: 12}, {Rule: RuleMinStrength, Limit: 3}}
+	if verr == nil || !slices.Equal(verr.Violations, want) || verr.Score != 0 {
+		t.Fatalf("Validate = %+v, want %+v with score 0", verr, want)
+	}
+
+	// 泄露的密码无论熵多高都记 0 分
+	verr, _ = DefaultPolicy().Validate("P@ssw0rd")
+	if verr == nil || Score("P@ssw0rd") == 0 || verr.Score != 0 {
+		t.Fatalf("breached password scored %+v", verr)
+	}
+}
+
+func TestValidateWithUnavailableList(t *testing.T) {
+	p := &Policy{MinLength: 8, Breached: failingList{}}
+
+	// 列表不可用时报告错误，其余规则照常检查
+	verr, err := p.Validate("short")
+	if err == nil || !strings.Contains(err.Error(), "list unavailable") {
+		t.Fatalf("list error = %v", err)
+	}
+	if got := rules(verr); !slices.Equal(got, []string{RuleMinLength}) {
+		t.Fatalf("violations = %v, want min_length only", got)
+	}
+	if verr, err = p.Validate("Zq8#vLm2!pT9x"); verr != nil || err == nil {
+		t.Fatalf("Validate = %+v, %v, want only the list error", verr, err)
+	}
+}
+
+func TestViolationMessages(t *testing.T) {
+	for _, v := range []Violation{
+		{Rule: RuleMinLength, Limit: 8},
+		{Rule: RuleMaxLength, Limit: 64},
+		{Rule: RuleRequireUpper},
+		{Rule: RuleRequireLower},
+		{Rule: RuleRequireNumber},
+		{Rule: RuleRequireSpecial},
+		{Rule: RuleMinStrength, Limit: 2},
+		{Rule: RuleBreached},
+	} {
+		if v.Message() == v.Rule || v.EnglishMessage() == v.Rule || v.Message() == v.EnglishMessage() {
+			t.Errorf("rule %s has no message of its own: %q, %q", v.Rule, v.Message(), v.EnglishMessage())
+		}
+	}
+	if got := (Violation{Rule: RuleMinLength, Limit: 12}).EnglishMessage(); !strings.Contains(got, "12") {
+		t.Errorf("min_length message %q lacks the limit", got)
+	}
+	if got := (Violation{Rule: "unknown"}).EnglishMessage(); got != "unknown" {
+		t.Errorf("unknown rule message = %q", got)
+	}
+}
+
+func TestValidationErrorJSON(t *testing.T) {
+	verr := &ValidationError{Violations: []Violation{{Rule: RuleMinLength, Limit: 8}, {Rule: RuleBreached}}}
+	if got, want := verr.Error(), "密码长度不能少于 8 位；该密码已出现在泄露的密码库中，请更换"; got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+
+	data, err := json.Marshal(verr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"violations":[` +
+		`{"rule":"min_length","limit":8,"message":"Password must be at least 8 characters long"},` +
+		`{"rule":"breached","message":"Password has appeared in a data breach; choose another one"}` +
+		`],"score":0}`
+	if string(data) != want {
+		t.Fatalf("JSON = %s\nwant %s", data, want)
+	}
+}
@@ -0,0 +1,92 @@
+package password
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Score 的分档界限（比特），与 zxcvbn 的 0-4 分大致对应
+var scoreBits = [...]float64{28, 36, 60, 80}
+
+// Score 密码强度评分：0 极弱、1 弱、2 一般、3 强、4 很强
+func Score(pwd string) int {
+	bits := Entropy(pwd)
+	for i, limit := range scoreBits {
+		if bits < limit {
+			return i
+		}
+	}
+	return len(scoreBits)
+}
+
+// Entropy 估算密码的熵（比特）：有效长度 × log2(字符集大小)。
+// 与前一字符相同或相邻（aaa、abc、321）的字符只计 1/4 长度，
+// 整体由同一片段重复而成（abcabc）时只计一个片段再加上重复次数的熵。
+func Entropy(pwd string) float64 {
+	runes := []rune(pwd)
+	if len(runes) == 0 {
+		return 0
+	}
+	if period := repeatPeriod(runes); period < len(runes) {
+		return Entropy(string(runes[:period])) + math.Log2(float64(len(runes)/period))
+	}
+	length := 0.0
+	for i, r := range runes {
+		if i > 0 && abs(r-runes[i-1]) <= 1 {
+			length += 0.25
+		} else {
+			length++
+		}
+	}
+	return length * math.Log2(float64(poolSize(pwd)))
+}
+
+// poolSize 密码所用字符类别的字符总数
+func poolSize(pwd string) int {
+	size := 0
+	if strings.ContainsFunc(pwd, func(r rune) bool { return r >= 'a' && r <= 'z' }) {
+		size += 26
+	}
+	if strings.ContainsFunc(pwd, func(r rune) bool { return r >= 'A' && r <= 'Z' }) {
+		size += 26
+	}
+	if strings.ContainsFunc(pwd, func(r rune) bool { return r >= '0' && r <= '9' }) {
+		size += 10
+	}
+	if strings.ContainsFunc(pwd, func(r rune) bool { return r < unicode.MaxASCII && !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		size += 33
+	}
+	if strings.ContainsFunc(pwd, func(r rune) bool { return r > unicode.MaxASCII }) {
+		size += 100
+	}
+	return size
+}
+
+// repeatPeriod 最短的重复片段长度；不是整段重复时返回总长度
+func repeatPeriod(runes []rune) int {
+	n := len(runes)
+	for p := 1; p <= n/2; p++ {
+		if n%p != 0 {
+			continue
+		}
+		repeated := true
+		for i := p; i < n; i++ {
+			if runes[i] != runes[i-p] {
+				repeated = false
+				break
+			}
+		}
+		if repeated {
+			return p
+		}
+	}
+	return n
+}
+
+func abs(r rune) rune {
+	if r < 0 {
+		return -r
+	}
+	return r
+}
@@ -0,0 +1,59 @@
+package password
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEntropy(t *testing.T) {
+	lower := math.Log2(26)
+	for name, tc := range map[string]struct {
+		pwd  string
+		want float64
+	}{
+		"empty":             {"", 0},
+		"single character":  {"a", lower},
+		"unrelated letters": {"qwzk", 4 * lower},
+		// 与前一字符相同或相邻的字符只计 1/4
+		"sequence":         {"abcd", 1.75 * lower},
+		"reverse sequence": {"dcba", 1.75 * lower},
+		"same character":   {"qbbz", 3.25 * lower},
+		// 整段重复只计一个片段再加上重复次数的熵
+		"repeated character": {"aaaaaaaa", lower + 3},
+		"repeated fragment":  {"qzqzqz", 2*lower + math.Log2(3)},
+		"partial repeat":     {"qzqzq", 5 * lower},
+		// 字符集按用到的类别累加
+		"digits":        {"1928", 4 * math.Log2(10)},
+		"mixed classes": {"aZ9!", 4 * math.Log2(26+26+10+33)},
+		"non-ascii":     {"密钥", 2 * math.Log2(100)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := Entropy(tc.pwd); math.Abs(got-tc.want) > 1e-9 {
+				t.Fatalf("Entropy(%q) = %.3f, want %.3f", tc.pwd, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	for pwd, want := range map[string]int{
+		"":                      0,
+		"aaaaaaaa":              0,
+		"abcdefgh":              0,
+		"12345678":              0,
+		"密码密码密码密码":              0,
+		"password":              1,
+		"qwerty":                1,
+		"correcthorse":          2,
+		"Tr0ub4dor":             2,
+		"密码安全很重要啊":              2,
+		"horse battery":         3,
+		"x7#Kq2!mZp":            3,
+		"Zq8#vLm2!pT9x":         4,
+		"correct horse battery": 4,
+	} {
+		if got := Score(pwd); got != want {
+			t.Errorf("Score(%q) = %d (%.1f bits), want %d", pwd, got, Entropy(pwd), want)
+		}
+	}
+}
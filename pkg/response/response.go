@@ -1,8 +1,11 @@
 package response
 
 import (
+	"errors"
 	"net/http"
 
+	"go-ddd-scaffold/pkg/errcode"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -190,6 +193,21 @@ func ServerError(c *gin.Context, message string) {
 	})
 }
 
+// FromError writes an errcode.Error with its mapped HTTP status and any
+// structured details as data; any other error becomes a 500
+func FromError(c *gin.Context, err error) {
+	var e *errcode.Error
+	if errors.As(err, &e) {
+		c.JSON(e.GetHTTPStatus(), Response{
+			Code:    e.Code,
+			Message: e.Message,
+			Data:    e.Data,
+		})
+		return
+	}
+	ServerError(c, "internal server error")
+}
+
 // DatabaseError returns a database error
 func DatabaseError(c *gin.Context) {
 	c.JSON(http.StatusInternalServerError, Response{
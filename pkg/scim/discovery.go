@@ -0,0 +1,84 @@
+package scim
+
+// Supported 某项可选功能是否支持
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+// FilterSupport 过滤功能及单页上限
+type FilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+// BulkSupport 批量操作功能
+type BulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+// AuthenticationScheme 客户端的认证方式
+type AuthenticationScheme struct {
+	Type        string `json:"type"` // oauthbearertoken、httpbasic 等
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary,omitempty"`
+}
+
+// ServiceProviderConfig 服务端支持的协议功能（/ServiceProviderConfig）
+type ServiceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	DocumentationURI      string                 `json:"documentationUri,omitempty"`
+	Patch                 Supported              `json:"patch"`
+	Bulk                  BulkSupport            `json:"bulk"`
+	Filter                FilterSupport          `json:"filter"`
+	ChangePassword        Supported              `json:"changePassword"`
+	Sort                  Supported              `json:"sort"`
+	ETag                  Supported              `json:"etag"`
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  *Meta                  `json:"meta,omitempty"`
+}
+
+// SchemaExtension 资源类型附加的扩展 schema
+type SchemaExtension struct {
+	Schema   string `json:"schema"`
+	Required bool   `json:"required"`
+}
+
+// ResourceType 资源类型（/ResourceTypes）
+type ResourceType struct {
+	Schemas          []string          `json:"schemas"`
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Endpoint         string            `json:"endpoint"`
+	Description      string            `json:"description,omitempty"`
+	Schema           string            `json:"schema"`
+	SchemaExtensions []SchemaExtension `json:"schemaExtensions,omitempty"`
+	Meta             *Meta             `json:"meta,omitempty"`
+}
+
+// Attribute schema 中的属性定义（RFC 7643 7）
+type Attribute struct {
+	Name          string      `json:"name"`
+	Type          string      `json:"type"` // string、boolean、complex、reference、dateTime 等
+	MultiValued   bool        `json:"multiValued"`
+	Description   string      `json:"description,omitempty"`
+	Required      bool        `json:"required"`
+	CaseExact     bool        `json:"caseExact"`
+	Mutability    string      `json:"mutability"` // readOnly、readWrite、immutable、writeOnly
+	Returned      string      `json:"returned"`   // always、never、default、request
+	Uniqueness    string      `json:"uniqueness"` // none、server、global
+	SubAttributes []Attribute `json:"subAttributes,omitempty"`
+	ReferenceType []string    `json:"referenceTypes,omitempty"`
+}
+
+// Schema 资源的 schema 定义（/Schemas）
+type Schema struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Attributes  []Attribute `json:"attributes"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
@@ -0,0 +1,386 @@
+package scim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Operator 过滤表达式的运算符
+type Operator string
+
+// 逻辑运算符与比较运算符（RFC 7644 3.4.2.2）
+const (
+	And       Operator = "and"
+	Or        Operator = "or"
+	Not       Operator = "not"
+	ValuePath Operator = "[]" // attr[filter]：多值属性中存在满足子表达式的值
+
+	Eq Operator = "eq"
+	Ne Operator = "ne"
+	Co Operator = "co"
+	Sw Operator = "sw"
+	Ew Operator = "ew"
+	Pr Operator = "pr"
+	Gt Operator = "gt"
+	Ge Operator = "ge"
+	Lt Operator = "lt"
+	Le Operator = "le"
+)
+
+var comparisons = map[string]Operator{
+	"eq": Eq, "ne": Ne, "co": Co, "sw": Sw, "ew": Ew,
+	"gt": Gt, "ge": Ge, "lt": Lt, "le": Le,
+}
+
+// Filter 过滤表达式语法树
+//
+//   - And、Or：Left 与 Right 为两个子表达式
+//   - Not：Left 为被取反的子表达式
+//   - ValuePath：Attr 为多值属性，Left 为作用于其子属性的表达式
+//   - 比较运算：Attr 为属性路径（如 userName、name.givenName），Value 为
+//     string、float64、bool 或 nil；Pr 没有 Value
+//
+// 属性名去掉了 schema URN 前缀，大小写保持原样，比较时应忽略大小写。
+type Filter struct {
+	Op    Operator
+	Attr  string
+	Value any
+	Left  *Filter
+	Right *Filter
+}
+
+// IsComparison 是否为比较运算（含 pr）
+func (f *Filter) IsComparison() bool {
+	switch f.Op {
+	case And, Or, Not, ValuePath:
+		return false
+	}
+	return true
+}
+
+// Conjunction 把只由 and 连接的比较运算展开为列表，ValuePath 中的比较展开为
+// attr.sub 的形式（emails[value eq "x"] 即 emails.value eq "x"）。
+// 含 or、not 或无法展开的 ValuePath 时返回 false。
+func (f *Filter) Conjunction() ([]*Filter, bool) {
+	switch {
+	case f.Op == And:
+		left, ok := f.Left.Conjunction()
+		if !ok {
+			return nil, false
+		}
+		right, ok := f.Right.Conjunction()
+		if !ok {
+			return nil, false
+		}
+		return append(left, right...), true
+	case f.Op == ValuePath:
+		inner, ok := f.Left.Conjunction()
+		if !ok {
+			return nil, false
+		}
+		result := make([]*Filter, len(inner))
+		for i, c := range inner {
+			result[i] = &Filter{Op: c.Op, Attr: f.Attr + "." + c.Attr, Value: c.Value}
+		}
+		return result, true
+	case f.IsComparison():
+		return []*Filter{f}, true
+	}
+	return nil, false
+}
+
+// ParseFilter 解析 filter 查询参数，语法错误返回 invalidFilter 错误
+func ParseFilter(s string) (*Filter, error) {
+	p, err := newParser(s)
+	if err != nil {
+		return nil, BadRequest(ErrInvalidFilter, err.Error())
+	}
+	f, err := p.parseOr()
+	if err == nil && p.peek().kind != tokEOF {
+		err = fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	if err != nil {
+		return nil, BadRequest(ErrInvalidFilter, err.Error())
+	}
+	return f, nil
+}
+
+// Path PATCH 操作的目标：attr、attr.sub、attr[filter] 或 attr[filter].sub
+type Path struct {
+	Attr   string
+	Sub    string
+	Filter *Filter
+}
+
+// ParsePath 解析 PATCH 操作的 path，语法错误返回 invalidPath 错误
+func ParsePath(s string) (*Path, error) {
+	p, err := newParser(s)
+	if err != nil {
+		return nil, BadRequest(ErrInvalidPath, err.Error())
+	}
+	tok := p.next()
+	if tok.kind != tokWord {
+		return nil, BadRequest(ErrInvalidPath, fmt.Sprintf("invalid path %q", s))
+	}
+	path := &Path{}
+	path.Attr, path.Sub, _ = strings.Cut(stripURN(tok.text), ".")
+
+	if p.peek().kind == tokLBracket {
+		if path.Sub != "" {
+			return nil, BadRequest(ErrInvalidPath, fmt.Sprintf("invalid path %q", s))
+		}
+		p.next()
+		if path.Filter, err = p.parseOr(); err == nil {
+			err = p.expect(tokRBracket)
+		}
+		if err != nil {
+			return nil, BadRequest(ErrInvalidPath, err.Error())
+		}
+		// 紧跟的 .sub 被词法分析为一个以点开头的单词
+		if tok := p.peek(); tok.kind == tokWord && strings.HasPrefix(tok.text, ".") {
+			path.Sub = strings.TrimPrefix(p.next().text, ".")
+		}
+	}
+	if p.peek().kind != tokEOF || path.Attr == "" {
+		return nil, BadRequest(ErrInvalidPath, fmt.Sprintf("invalid path %q", s))
+	}
+	return path, nil
+}
+
+// stripURN 去掉属性路径的 schema URN 前缀，如
+// urn:ietf:params:scim:schemas:core:2.0:User:name.givenName → name.givenName
+func stripURN(attr string) string {
+	if i := strings.LastIndexByte(attr, ':'); i >= 0 {
+		return attr[i+1:]
+	}
+	return attr
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+)
+
+type token struct {
+	kind tokenKind
+	text string // 字符串字面量为解码后的内容
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func newParser(s string) (*parser, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{tokens: tokens}, nil
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")"})
+			i++
+		case c == '[':
+			tokens = append(tokens, token{kind: tokLBracket, text: "["})
+			i++
+		case c == ']':
+			tokens = append(tokens, token{kind: tokRBracket, text: "]"})
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(s) && s[end] != '"'; end++ {
+				if s[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			text, err := strconv.Unquote(s[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at offset %d", i)
+			}
+			tokens = append(tokens, token{kind: tokString, text: text})
+			i = end + 1
+		default:
+			end := i
+			for end < len(s) && !strings.ContainsRune(" \t()[]\"", rune(s[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: s[i:end]})
+			i = end
+		}
+	}
+	return append(tokens, token{kind: tokEOF}), nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind) error {
+	if tok := p.next(); tok.kind != kind {
+		if tok.kind == tokEOF {
+			return fmt.Errorf("unexpected end of expression")
+		}
+		return fmt.Errorf("unexpected %q", tok.text)
+	}
+	return nil
+}
+
+// keyword 下一个单词是否为忽略大小写的关键字
+func (p *parser) keyword(kw string) bool {
+	tok := p.peek()
+	return tok.kind == tokWord && strings.EqualFold(tok.text, kw)
+}
+
+// parseOr 优先级最低：a or b
+func (p *parser) parseOr() (*Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Filter{Op: Or, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (*Filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Filter{Op: And, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseUnary 解析 not (...)、(...)、attr[...] 与比较运算
+func (p *parser) parseUnary() (*Filter, error) {
+	if p.keyword("not") {
+		p.next()
+		if err := p.expect(tokLParen); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen); err != nil {
+			return nil, err
+		}
+		return &Filter{Op: Not, Left: inner}, nil
+	}
+
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	case tokWord:
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("expected attribute, got %q", tok.text)
+	}
+	attr := stripURN(tok.text)
+
+	if p.peek().kind == tokLBracket {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRBracket); err != nil {
+			return nil, err
+		}
+		return &Filter{Op: ValuePath, Attr: attr, Left: inner}, nil
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokWord {
+		return nil, fmt.Errorf("expected operator after %q", attr)
+	}
+	if strings.EqualFold(opTok.text, "pr") {
+		return &Filter{Op: Pr, Attr: attr}, nil
+	}
+	op, ok := comparisons[strings.ToLower(opTok.text)]
+	if !ok {
+		return nil, fmt.Errorf("unknown operator %q", opTok.text)
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{Op: op, Attr: attr, Value: value}, nil
+}
+
+// parseValue 解析比较值：字符串、数字、true、false 或 null
+func (p *parser) parseValue() (any, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokString:
+		return tok.text, nil
+	case tokWord:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		if n, err := strconv.ParseFloat(tok.text, 64); err == nil {
+			return n, nil
+		}
+		return nil, fmt.Errorf("invalid value %q", tok.text)
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("invalid value %q", tok.text)
+}
@@ -0,0 +1,124 @@
+// Package scim 提供 SCIM 2.0（RFC 7643/7644）协议层的公共部分：
+// 资源无关的消息结构、错误响应、过滤表达式与 PATCH 路径的解析。
+// 资源（User、Group）与存储的映射由调用方实现。
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ContentType SCIM 请求与响应的媒体类型
+const ContentType = "application/scim+json"
+
+// 协议消息与核心资源的 schema URN
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SchemaSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// 错误响应的 scimType（RFC 7644 3.12）
+const (
+	ErrInvalidFilter = "invalidFilter"
+	ErrTooMany       = "tooMany"
+	ErrUniqueness    = "uniqueness"
+	ErrMutability    = "mutability"
+	ErrInvalidSyntax = "invalidSyntax"
+	ErrInvalidPath   = "invalidPath"
+	ErrNoTarget      = "noTarget"
+	ErrInvalidValue  = "invalidValue"
+)
+
+// PATCH 操作类型，比较时忽略大小写
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// Error SCIM 错误响应，同时实现 error
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"` // RFC 要求以字符串表示 HTTP 状态码
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+
+	code int
+}
+
+// NewError 创建错误，scimType 可为空
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+		code:     status,
+	}
+}
+
+// BadRequest 创建 400 错误
+func BadRequest(scimType, detail string) *Error {
+	return NewError(http.StatusBadRequest, scimType, detail)
+}
+
+func (e *Error) Error() string {
+	if e.ScimType == "" {
+		return "scim: " + e.Detail
+	}
+	return "scim: " + e.ScimType + ": " + e.Detail
+}
+
+// StatusCode 返回 HTTP 状态码
+func (e *Error) StatusCode() int {
+	return e.code
+}
+
+// Meta 资源的元数据
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+// ListResponse 查询结果，StartIndex 从 1 开始
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int64    `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources" swaggertype:"array,object"`
+}
+
+// NewListResponse 创建查询结果，resources 须为切片
+func NewListResponse(resources any, count int, total int64, startIndex int) *ListResponse {
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: count,
+		Resources:    resources,
+	}
+}
+
+// PatchRequest PATCH 请求体
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations" binding:"required,min=1,dive"`
+}
+
+// PatchOperation 一个 PATCH 操作；Path 为空时 Value 是以属性名为键的对象
+type PatchOperation struct {
+	Op    string          `json:"op" binding:"required"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+}
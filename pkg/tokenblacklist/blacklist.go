@@ -2,19 +2,27 @@ package tokenblacklist
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
+	"errors"
+	"strconv"
 	"time"
 
 	"go-ddd-scaffold/pkg/cache"
 )
 
-const KeyPrefix = "token:blacklist:"
+const (
+	KeyPrefix          = "token:blacklist:"
+	WatermarkKeyPrefix = "token:revoke_before:"
+)
 
 // Blacklist Token 黑名单接口
 type Blacklist interface {
-	Add(ctx context.Context, token string, expiration time.Duration) error
-	IsBlacklisted(ctx context.Context, token string) bool
+	// Add 按 jti 吊销单个 Token，直到其过期
+	Add(ctx context.Context, jti string, expiration time.Duration) error
+	IsBlacklisted(ctx context.Context, jti string) bool
+
+	// RevokeBefore 吊销某主体在 before 之前签发的全部 Token（水位线）
+	RevokeBefore(ctx context.Context, subject string, before time.Time, expiration time.Duration) error
+	IsRevokedBefore(ctx context.Context, subject string, issuedAt time.Time) bool
 }
 
 // CacheBlacklist 基于缓存的 Token 黑名单
@@ -27,23 +35,39 @@ func New(c cache.Cache) *CacheBlacklist {
 	return &CacheBlacklist{cache: c}
 }
 
-func hashToken(token string) string {
-	hash := sha256.Sum256([]byte(token))
-	return hex.EncodeToString(hash[:])
-}
-
-// Add 将 Token 加入黑名单
-func (b *CacheBlacklist) Add(ctx context.Context, token string, expiration time.Duration) error {
-	return b.cache.SetString(ctx, KeyPrefix+hashToken(token), "1", expiration)
+// Add 将 jti 加入黑名单
+func (b *CacheBlacklist) Add(ctx context.Context, jti string, expiration time.Duration) error {
+	if expiration <= 0 {
+		return nil // 已过期的 Token 无需吊销
+	}
+	return b.cache.SetString(ctx, KeyPrefix+jti, "1", expiration)
 }
 
-// IsBlacklisted 检查 Token 是否在黑名单中（fail-closed：缓存出错时拒绝）
-func (b *CacheBlacklist) IsBlacklisted(ctx context.Context, token string) bool {
-	exists, err := b.cache.Exists(ctx, KeyPrefix+hashToken(token))
+// IsBlacklisted 检查 jti 是否在黑名单中（fail-closed：缓存出错时拒绝）
+func (b *CacheBlacklist) IsBlacklisted(ctx context.Context, jti string) bool {
+	exists, err := b.cache.Exists(ctx, KeyPrefix+jti)
 	if err != nil {
 		return true // fail-closed
 	}
 	return exists
 }
 
+// RevokeBefore 设置水位线（毫秒精度），expiration 应不小于 Token 最长有效期
+func (b *CacheBlacklist) RevokeBefore(ctx context.Context, subject string, before time.Time, expiration time.Duration) error {
+	return b.cache.SetString(ctx, WatermarkKeyPrefix+subject, strconv.FormatInt(before.UnixMilli(), 10), expiration)
+}
+
+// IsRevokedBefore 检查签发时间是否早于水位线（fail-closed：缓存出错时拒绝）
+func (b *CacheBlacklist) IsRevokedBefore(ctx context.Context, subject string, issuedAt time.Time) bool {
+	val, err := b.cache.GetString(ctx, WatermarkKeyPrefix+subject)
+	if err != nil {
+		return !errors.Is(err, cache.ErrNotFound)
+	}
+	watermark, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return true
+	}
+	return issuedAt.UnixMilli() < watermark
+}
+
 var _ Blacklist = (*CacheBlacklist)(nil)
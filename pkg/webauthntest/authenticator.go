@@ -0,0 +1,307 @@
+// Package webauthntest 提供软件实现的 WebAuthn 认证器，用于在 Go 测试中完成通行密钥的注册与登录
+//
+// 认证器读取服务端下发的 options JSON（navigator.credentials.create/get 的参数），
+// 返回浏览器会提交给服务端的 PublicKeyCredential JSON。密钥为 ES256，证明格式为 none，
+// 凭据都是可发现凭据（resident key）。仅用于测试，私钥只保存在内存中。
+package webauthntest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// 认证器数据中的标志位
+const (
+	flagUserPresent    = 0x01
+	flagUserVerified   = 0x04
+	flagBackupEligible = 0x08
+	flagBackupState    = 0x10
+	flagAttestedData   = 0x40
+)
+
+var (
+	// ErrCredentialExcluded 认证器已持有 excludeCredentials 中的凭据，对应浏览器的 InvalidStateError
+	ErrCredentialExcluded = errors.New("webauthntest: credential already registered")
+	// ErrNoCredential 没有可用于该依赖方的凭据，对应浏览器的 NotAllowedError
+	ErrNoCredential = errors.New("webauthntest: no matching credential")
+)
+
+// Credential 认证器保存的一个凭据
+type Credential struct {
+	ID         []byte
+	RPID       string
+	UserHandle []byte
+	UserName   string
+	PrivateKey *ecdsa.PrivateKey
+	SignCount  uint32
+}
+
+// Authenticator 软件认证器，可并发使用
+type Authenticator struct {
+	Origin         string   // 发起仪式的页面来源，如 http://localhost:8080
+	AAGUID         [16]byte // 认证器型号标识，默认全零
+	UserVerified   bool     // 是否声明已验证用户（UV），New 默认为 true
+	BackupEligible bool     // 是否声明凭据可同步备份（BE/BS）
+	Attachment     string   // authenticatorAttachment，默认 platform
+
+	mu          sync.Mutex
+	credentials []*Credential
+}
+
+// New 创建认证器，origin 须在服务端的 rp_origins 中
+func New(origin string) *Authenticator {
+	return &Authenticator{Origin: origin, UserVerified: true, Attachment: "platform"}
+}
+
+// Credentials 返回已创建的凭据
+func (a *Authenticator) Credentials() []*Credential {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]*Credential(nil), a.credentials...)
+}
+
+// creationOptions navigator.credentials.create 的参数中认证器用到的部分
+type creationOptions struct {
+	PublicKey struct {
+		RP struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID   protocol.URLEncodedBase64 `json:"id"`
+			Name string                    `json:"name"`
+		} `json:"user"`
+		Challenge          protocol.URLEncodedBase64       `json:"challenge"`
+		Parameters         []protocol.CredentialParameter  `json:"pubKeyCredParams"`
+		ExcludeCredentials []protocol.CredentialDescriptor `json:"excludeCredentials"`
+	} `json:"publicKey"`
+}
+
+// requestOptions navigator.credentials.get 的参数中认证器用到的部分
+type requestOptions struct {
+	PublicKey struct {
+		Challenge        protocol.URLEncodedBase64       `json:"challenge"`
+		RPID             string                          `json:"rpId"`
+		AllowCredentials []protocol.CredentialDescriptor `json:"allowCredentials"`
+		UserVerification string                          `json:"userVerification"`
+	} `json:"publicKey"`
+}
+
+// Register 执行注册仪式：options 为服务端下发的 {"publicKey": {...}}，返回新凭据的 JSON
+func (a *Authenticator) Register(options []byte) ([]byte, error) {
+	var opts creationOptions
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, fmt.Errorf("webauthntest: parse creation options: %w", err)
+	}
+	o := opts.PublicKey
+	if len(o.Challenge) == 0 || len(o.User.ID) == 0 {
+		return nil, fmt.Errorf("webauthntest: creation options lack challenge or user id")
+	}
+	if !supportsES256(o.Parameters) {
+		return nil, fmt.Errorf("webauthntest: ES256 is not among pubKeyCredParams")
+	}
+	rpID, err := a.rpID(o.RP.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, excluded := range o.ExcludeCredentials {
+		if a.find(rpID, excluded.CredentialID) != nil {
+			return nil, ErrCredentialExcluded
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	cred := &Credential{ID: make([]byte, 32), RPID: rpID, UserHandle: o.User.ID, UserName: o.User.Name, PrivateKey: key}
+	if _, err := rand.Read(cred.ID); err != nil {
+		return nil, err
+	}
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: key.X.FillBytes(make([]byte, 32)),
+		YCoord: key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	authData := a.authData(rpID, flagAttestedData, cred.SignCount)
+	authData = append(authData, a.AAGUID[:]...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(cred.ID)))
+	authData = append(authData, cred.ID...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(struct {
+		Format    string         `cbor:"fmt"`
+		Statement map[string]any `cbor:"attStmt"`
+		AuthData  []byte         `cbor:"authData"`
+	}{Format: "none", Statement: map[string]any{}, AuthData: authData})
+	if err != nil {
+		return nil, err
+	}
+	clientData, err := a.clientData(protocol.CreateCeremony, o.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	a.credentials = append(a.credentials, cred)
+	return json.Marshal(map[string]any{
+		"id":                      encode(cred.ID),
+		"rawId":                   encode(cred.ID),
+		"type":                    "public-key",
+		"authenticatorAttachment": a.Attachment,
+		"clientExtensionResults":  map[string]any{},
+		"response": map[string]any{
+			"clientDataJSON":    encode(clientData),
+			"attestationObject": encode(attestation),
+			"transports":        []string{"internal"},
+		},
+	})
+}
+
+// Login 执行登录仪式：options 为服务端下发的 {"publicKey": {...}}，返回断言的 JSON
+// allowCredentials 为空时使用该依赖方最近创建的凭据（可发现凭据登录）。
+func (a *Authenticator) Login(options []byte) ([]byte, error) {
+	var opts requestOptions
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, fmt.Errorf("webauthntest: parse request options: %w", err)
+	}
+	o := opts.PublicKey
+	if len(o.Challenge) == 0 {
+		return nil, fmt.Errorf("webauthntest: request options lack challenge")
+	}
+	if o.UserVerification == string(protocol.VerificationRequired) && !a.UserVerified {
+		return nil, ErrNoCredential
+	}
+	rpID, err := a.rpID(o.RPID)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var cred *Credential
+	if len(o.AllowCredentials) == 0 {
+		for i := len(a.credentials) - 1; i >= 0; i-- {
+			if a.credentials[i].RPID == rpID {
+				cred = a.credentials[i]
+				break
+			}
+		}
+	}
+	for _, allowed := range o.AllowCredentials {
+		if cred = a.find(rpID, allowed.CredentialID); cred != nil {
+			break
+		}
+	}
+	if cred == nil {
+		return nil, ErrNoCredential
+	}
+
+	cred.SignCount++
+	authData := a.authData(rpID, 0, cred.SignCount)
+	clientData, err := a.clientData(protocol.AssertCeremony, o.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(bytes.Clone(authData), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, cred.PrivateKey, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{
+		"id":                      encode(cred.ID),
+		"rawId":                   encode(cred.ID),
+		"type":                    "public-key",
+		"authenticatorAttachment": a.Attachment,
+		"clientExtensionResults":  map[string]any{},
+		"response": map[string]any{
+			"clientDataJSON":    encode(clientData),
+			"authenticatorData": encode(authData),
+			"signature":         encode(signature),
+			"userHandle":        encode(cred.UserHandle),
+		},
+	})
+}
+
+// rpID 返回依赖方 ID，未指定时与浏览器一样取来源的主机名
+func (a *Authenticator) rpID(id string) (string, error) {
+	if id != "" {
+		return id, nil
+	}
+	u, err := url.Parse(a.Origin)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("webauthntest: invalid origin %q", a.Origin)
+	}
+	return u.Hostname(), nil
+}
+
+// find 查找依赖方的凭据，调用方须持有锁
+func (a *Authenticator) find(rpID string, id []byte) *Credential {
+	for _, c := range a.credentials {
+		if c.RPID == rpID && bytes.Equal(c.ID, id) {
+			return c
+		}
+	}
+	return nil
+}
+
+// authData 生成认证器数据的固定部分：rpIdHash | flags | signCount
+func (a *Authenticator) authData(rpID string, flags byte, signCount uint32) []byte {
+	flags |= flagUserPresent
+	if a.UserVerified {
+		flags |= flagUserVerified
+	}
+	if a.BackupEligible {
+		flags |= flagBackupEligible | flagBackupState
+	}
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, signCount)
+}
+
+// clientData 生成浏览器为仪式签名的 clientDataJSON
+func (a *Authenticator) clientData(ceremony protocol.CeremonyType, challenge []byte) ([]byte, error) {
+	return json.Marshal(protocol.CollectedClientData{
+		Type:      ceremony,
+		Challenge: encode(challenge),
+		Origin:    a.Origin,
+	})
+}
+
+func supportsES256(params []protocol.CredentialParameter) bool {
+	for _, p := range params {
+		if p.Algorithm == webauthncose.AlgES256 {
+			return true
+		}
+	}
+	return false
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -1,6 +1,37 @@
 import { history, RequestConfig, RunTimeLayoutConfig } from '@umijs/max';
 import { message } from 'antd';
-import { TOKEN_KEY, API_PREFIX } from './constants';
+import { TOKEN_KEY, REFRESH_TOKEN_KEY, API_PREFIX } from './constants';
+import { logout } from './services/auth';
+
+function clearTokens() {
+  localStorage.removeItem(TOKEN_KEY);
+  localStorage.removeItem(REFRESH_TOKEN_KEY);
+}
+
+// Exchange the stored refresh token for a new pair.
+// Uses fetch directly so a failed refresh does not re-enter the error handler.
+async function tryRefresh(): Promise<boolean> {
+  const refresh_token = localStorage.getItem(REFRESH_TOKEN_KEY);
+  if (!refresh_token) {
+    return false;
+  }
+  try {
+    const res = await fetch(`${API_PREFIX}/auth/refresh`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ refresh_token }),
+    });
+    const body = await res.json();
+    if (!res.ok || body?.code !== 0) {
+      return false;
+    }
+    localStorage.setItem(TOKEN_KEY, body.data.token);
+    localStorage.setItem(REFRESH_TOKEN_KEY, body.data.refresh_token);
+    return true;
+  } catch {
+    return false;
+  }
+}
 
 // Runtime request configuration
 export const request: RequestConfig = {
@@ -29,9 +60,13 @@ export const request: RequestConfig = {
     },
   ],
   errorConfig: {
-    errorHandler: (error: any) => {
+    errorHandler: async (error: any) => {
       if (error?.response?.status === 401) {
-        localStorage.removeItem(TOKEN_KEY);
+        if (location.pathname !== '/login' && (await tryRefresh())) {
+          window.location.reload();
+          return;
+        }
+        clearTokens();
         history.push('/login');
         return;
       }
@@ -77,8 +112,13 @@ export const layout: RunTimeLayoutConfig = ({ initialState }) => {
         history.push('/login');
       }
     },
-    logout: () => {
-      localStorage.removeItem(TOKEN_KEY);
+    logout: async () => {
+      try {
+        await logout({ refresh_token: localStorage.getItem(REFRESH_TOKEN_KEY) || undefined });
+      } catch {
+        // Token may already be expired or revoked
+      }
+      clearTokens();
       history.push('/login');
     },
     menuHeaderRender: undefined,
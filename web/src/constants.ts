@@ -1,2 +1,3 @@
 export const TOKEN_KEY = 'token';
+export const REFRESH_TOKEN_KEY = 'refresh_token';
 export const API_PREFIX = '/api/v1';
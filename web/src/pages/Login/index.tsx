@@ -1,27 +1,240 @@
-import { LoginForm, ProFormText } from '@ant-design/pro-components';
+import { LoginForm, ModalForm, ProFormText } from '@ant-design/pro-components';
 import { history, useModel } from '@umijs/max';
-import { message } from 'antd';
-import { LockOutlined, UserOutlined } from '@ant-design/icons';
-import { login } from '@/services/auth';
-import { TOKEN_KEY } from '@/constants';
+import { Button, Divider, message } from 'antd';
+import { LockOutlined, LoginOutlined, SafetyOutlined, TeamOutlined, UserOutlined } from '@ant-design/icons';
+import { useEffect, useState } from 'react';
+import {
+  changePassword,
+  exchangeSSOCode,
+  forgotPassword,
+  getCaptcha,
+  getSSOConfig,
+  login,
+  resetPassword,
+  verifyEmail,
+  verifyMFA,
+} from '@/services/auth';
+import { API_PREFIX, REFRESH_TOKEN_KEY, TOKEN_KEY } from '@/constants';
+
+// Login error codes asking for (another) captcha
+const CAPTCHA_REQUIRED = 10010;
+const CAPTCHA_INVALID = 10011;
 
 const LoginPage: React.FC = () => {
   const { refresh } = useModel('@@initialState');
+  // Temporary password kept while the user is forced to replace it
+  const [tempPassword, setTempPassword] = useState<string>();
+  // Password kept while the second factor is being verified ('' after single sign-on)
+  const [mfaPassword, setMfaPassword] = useState<string>();
+  const [ssoEnabled, setSSOEnabled] = useState(false);
+  // Shown once the server asks for it after repeated failures
+  const [captcha, setCaptcha] = useState<{ captcha_id: string; image: string }>();
+  const [forgotOpen, setForgotOpen] = useState(false);
+  // Token from a mailed password reset link
+  const [resetToken, setResetToken] = useState<string>();
+
+  useEffect(() => {
+    getSSOConfig()
+      .then((res) => setSSOEnabled(!!res?.data?.enabled))
+      .catch(() => {});
+
+    // Back from the identity provider: redeem the one-time code or show its error
+    const params = new URLSearchParams(window.location.search);
+    const ssoCode = params.get('sso_code');
+    const ssoError = params.get('sso_error');
+    // Links sent by mail
+    const verifyToken = params.get('verify_token');
+    const reset = params.get('reset_token');
+    if (ssoCode || ssoError || verifyToken || reset) {
+      history.replace('/login');
+    }
+    if (ssoError) {
+      message.error(ssoError);
+    } else if (ssoCode) {
+      handleSSO(ssoCode);
+    } else if (verifyToken) {
+      handleVerifyEmail(verifyToken);
+    } else if (reset) {
+      setResetToken(reset);
+    }
+  }, []);
+
+  const loadCaptcha = async () => {
+    try {
+      const res = await getCaptcha();
+      if (res?.data) {
+        setCaptcha(res.data);
+      }
+    } catch (error) {
+      // Error handled by request interceptor
+    }
+  };
+
+  const storeTokens = (data: { token: string; refresh_token?: string }) => {
+    localStorage.setItem(TOKEN_KEY, data.token);
+    if (data.refresh_token) {
+      localStorage.setItem(REFRESH_TOKEN_KEY, data.refresh_token);
+    } else {
+      localStorage.removeItem(REFRESH_TOKEN_KEY);
+    }
+  };
+
+  // Continues after a full (or password-restricted) token pair was issued
+  const proceed = async (data: { restriction?: string }, password: string) => {
+    if (data.restriction === 'password_change') {
+      if (!password) {
+        // Single sign-on does not know the temporary password
+        message.warning('Your password was reset: sign in with the temporary password to choose a new one');
+        localStorage.removeItem(TOKEN_KEY);
+        localStorage.removeItem(REFRESH_TOKEN_KEY);
+        return;
+      }
+      setTempPassword(password);
+      return;
+    }
+    await enter();
+  };
 
-  const handleLogin = async (values: { username: string; password: string }) => {
+  const enter = async () => {
+    message.success('Login successful');
+    await refresh();
+    history.push('/dashboard');
+  };
+
+  const handleLogin = async (values: {
+    tenant?: string;
+    username: string;
+    password: string;
+    captcha_answer?: string;
+  }) => {
+    try {
+      const res = await login({ ...values, captcha_id: captcha?.captcha_id });
+      if (res?.data?.token) {
+        setCaptcha(undefined);
+        storeTokens(res.data);
+        if (res.data.restriction === 'mfa_pending') {
+          setMfaPassword(values.password);
+          return;
+        }
+        await proceed(res.data, values.password);
+      }
+    } catch (error: any) {
+      // Each captcha can be answered once: show a fresh one after any failure
+      const body = error?.response?.data;
+      if (body?.code === CAPTCHA_REQUIRED || body?.code === CAPTCHA_INVALID) {
+        message.warning(body.message);
+      }
+      if (captcha || body?.code === CAPTCHA_REQUIRED || body?.code === CAPTCHA_INVALID) {
+        await loadCaptcha();
+      }
+    }
+  };
+
+  const handleSSO = async (code: string) => {
     try {
-      const res = await login(values);
+      const res = await exchangeSSOCode(code);
       if (res?.data?.token) {
-        localStorage.setItem(TOKEN_KEY, res.data.token);
-        message.success('Login successful');
-        await refresh();
-        history.push('/dashboard');
+        storeTokens(res.data);
+        if (res.data.restriction === 'mfa_pending') {
+          setMfaPassword('');
+          return;
+        }
+        await proceed(res.data, '');
       }
     } catch (error) {
       // Error handled by request interceptor
     }
   };
 
+  const handleVerifyEmail = async (token: string) => {
+    try {
+      await verifyEmail(token);
+      message.success('Your email address is verified');
+    } catch (error) {
+      // Error handled by request interceptor
+    }
+  };
+
+  const handleForgotPassword = async (values: { tenant?: string; email: string }) => {
+    try {
+      await forgotPassword(values);
+      setForgotOpen(false);
+      // The server never tells whether the address belongs to an account
+      message.success('If the address belongs to a verified account, a reset link is on its way');
+      return true;
+    } catch (error) {
+      // Error handled by request interceptor
+    }
+    return false;
+  };
+
+  const handleResetPassword = async (values: { new_password: string }) => {
+    try {
+      await resetPassword({ token: resetToken!, new_password: values.new_password });
+      setResetToken(undefined);
+      message.success('Password reset: sign in with your new password');
+      return true;
+    } catch (error) {
+      // Error handled by request interceptor
+    }
+    return false;
+  };
+
+  const handleVerifyMFA = async (values: { code: string }) => {
+    try {
+      const res = await verifyMFA(values.code);
+      if (res?.data?.token) {
+        storeTokens(res.data);
+        const password = mfaPassword ?? '';
+        setMfaPassword(undefined);
+        await proceed(res.data, password);
+        return true;
+      }
+    } catch (error) {
+      // Error handled by request interceptor
+    }
+    return false;
+  };
+
+  const handleChangePassword = async (values: { new_password: string }) => {
+    try {
+      const res = await changePassword({ old_password: tempPassword!, new_password: values.new_password });
+      if (res?.data?.token) {
+        storeTokens(res.data);
+        setTempPassword(undefined);
+        await enter();
+        return true;
+      }
+    } catch (error) {
+      // Error handled by request interceptor
+    }
+    return false;
+  };
+
+  const newPasswordFields = (
+    <>
+      <ProFormText.Password
+        name="new_password"
+        label="New password"
+        rules={[{ required: true, message: 'Please enter a new password' }]}
+      />
+      <ProFormText.Password
+        name="confirm"
+        label="Confirm password"
+        dependencies={['new_password']}
+        rules={[
+          { required: true, message: 'Please confirm the new password' },
+          ({ getFieldValue }) => ({
+            validator: (_, value) =>
+              !value || getFieldValue('new_password') === value
+                ? Promise.resolve()
+                : Promise.reject(new Error('Passwords do not match')),
+          }),
+        ]}
+      />
+    </>
+  );
+
   return (
     <div style={{ height: '100vh', display: 'flex', alignItems: 'center', justifyContent: 'center', background: '#f0f2f5' }}>
       <LoginForm
@@ -29,6 +242,11 @@ const LoginPage: React.FC = () => {
         subTitle="Backend Management System"
         onFinish={handleLogin}
       >
+        <ProFormText
+          name="tenant"
+          fieldProps={{ size: 'large', prefix: <TeamOutlined /> }}
+          placeholder="Tenant (optional)"
+        />
         <ProFormText
           name="username"
           fieldProps={{ size: 'large', prefix: <UserOutlined /> }}
@@ -41,7 +259,90 @@ const LoginPage: React.FC = () => {
           placeholder="Password"
           rules={[{ required: true, message: 'Please enter password' }]}
         />
+        {captcha && (
+          <div style={{ display: 'flex', gap: 8 }}>
+            <ProFormText
+              name="captcha_answer"
+              formItemProps={{ style: { flex: 1 } }}
+              fieldProps={{ size: 'large', prefix: <SafetyOutlined />, autoComplete: 'off' }}
+              placeholder="Captcha"
+              rules={[{ required: true, message: 'Please enter the characters shown' }]}
+            />
+            <img
+              src={captcha.image}
+              alt="captcha"
+              title="Click for a new one"
+              style={{ height: 40, cursor: 'pointer' }}
+              onClick={loadCaptcha}
+            />
+          </div>
+        )}
+        <div style={{ textAlign: 'right', marginBottom: 24 }}>
+          <a onClick={() => setForgotOpen(true)}>Forgot password?</a>
+        </div>
+        {ssoEnabled && (
+          <>
+            <Divider plain>or</Divider>
+            <Button
+              block
+              size="large"
+              icon={<LoginOutlined />}
+              style={{ marginBottom: 24 }}
+              onClick={() => {
+                window.location.href = `${API_PREFIX}/auth/oidc/login`;
+              }}
+            >
+              Sign in with SSO
+            </Button>
+          </>
+        )}
       </LoginForm>
+      <ModalForm
+        title="Two-factor authentication"
+        open={mfaPassword !== undefined}
+        modalProps={{ destroyOnClose: true, onCancel: () => setMfaPassword(undefined) }}
+        onFinish={handleVerifyMFA}
+      >
+        <ProFormText
+          name="code"
+          label="Authenticator or recovery code"
+          fieldProps={{ autoComplete: 'one-time-code' }}
+          rules={[{ required: true, message: 'Please enter the code' }]}
+        />
+      </ModalForm>
+      <ModalForm
+        title="Change your password"
+        open={tempPassword !== undefined}
+        modalProps={{ closable: false, maskClosable: false, destroyOnClose: true }}
+        submitter={{ resetButtonProps: { style: { display: 'none' } } }}
+        onFinish={handleChangePassword}
+      >
+        {newPasswordFields}
+      </ModalForm>
+      <ModalForm
+        title="Forgot password"
+        open={forgotOpen}
+        modalProps={{ destroyOnClose: true, onCancel: () => setForgotOpen(false) }}
+        onFinish={handleForgotPassword}
+      >
+        <ProFormText name="tenant" label="Tenant" placeholder="Optional" />
+        <ProFormText
+          name="email"
+          label="Verified email address"
+          rules={[
+            { required: true, message: 'Please enter your email address' },
+            { type: 'email', message: 'Please enter a valid email address' },
+          ]}
+        />
+      </ModalForm>
+      <ModalForm
+        title="Reset your password"
+        open={resetToken !== undefined}
+        modalProps={{ destroyOnClose: true, onCancel: () => setResetToken(undefined) }}
+        onFinish={handleResetPassword}
+      >
+        {newPasswordFields}
+      </ModalForm>
     </div>
   );
 };
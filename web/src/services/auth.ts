@@ -1,14 +1,82 @@
 import { request } from '@umijs/max';
 
-export async function login(data: { username: string; password: string }) {
+export async function login(data: {
+  tenant?: string;
+  username: string;
+  password: string;
+  captcha_id?: string;
+  captcha_answer?: string;
+}) {
   return request('/auth/login', {
     method: 'POST',
     data,
   });
 }
 
-export async function refreshToken() {
+export async function getCaptcha() {
+  return request('/auth/captcha', {
+    method: 'GET',
+  });
+}
+
+export async function refreshToken(refresh_token: string) {
   return request('/auth/refresh', {
     method: 'POST',
+    data: { refresh_token },
+  });
+}
+
+export async function logout(data?: { refresh_token?: string; all?: boolean }) {
+  return request('/auth/logout', {
+    method: 'POST',
+    data,
+  });
+}
+
+export async function changePassword(data: { old_password: string; new_password: string }) {
+  return request('/auth/password', {
+    method: 'PUT',
+    data,
+  });
+}
+
+export async function verifyMFA(code: string) {
+  return request('/auth/mfa/verify', {
+    method: 'POST',
+    data: { code },
+  });
+}
+
+export async function getSSOConfig() {
+  return request('/auth/oidc/config', {
+    method: 'GET',
+  });
+}
+
+export async function exchangeSSOCode(code: string) {
+  return request('/auth/oidc/exchange', {
+    method: 'POST',
+    data: { code },
+  });
+}
+
+export async function forgotPassword(data: { tenant?: string; email: string }) {
+  return request('/auth/forgot-password', {
+    method: 'POST',
+    data,
+  });
+}
+
+export async function resetPassword(data: { token: string; new_password: string }) {
+  return request('/auth/reset-password', {
+    method: 'POST',
+    data,
+  });
+}
+
+export async function verifyEmail(token: string) {
+  return request('/auth/email/verify', {
+    method: 'POST',
+    data: { token },
   });
 }